@@ -6,12 +6,14 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+	_ "time/tzdata" // crew timezones must resolve on hosts without a zone database
 
 	"backend/internal/config"
 	"backend/internal/handlers"
 	"backend/internal/logging"
 	"backend/internal/models"
 	"backend/internal/services"
+	"backend/pkg/jwt"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -91,6 +93,11 @@ func main() {
 	}
 	defer logging.Sync()
 
+	// Load token signing and verification keys
+	if err := jwt.Configure(config.ToJWTConfig()); err != nil {
+		logging.Log.Fatal("Failed to configure JWT keys", zap.Error(err))
+	}
+
 	// Setup GORM database connection
 	gormDB, err := setupGormDB(config)
 	if err != nil {
@@ -138,7 +145,27 @@ func main() {
 
 	// Initialize services with both SQLC and GORM
 	// allServices := services.NewServices(queries)
-	allServices := services.NewServices(gormDB)
+	settings := config.ToServiceSettings()
+	settings.PaymentGateway, err = config.NewPaymentGateway()
+	if err != nil {
+		logging.Log.Fatal("Failed to configure the payment gateway", zap.Error(err))
+	}
+	settings.Notifier, err = config.NewNotifier()
+	if err != nil {
+		logging.Log.Fatal("Failed to configure the notifier", zap.Error(err))
+	}
+	settings.SMSSender, err = config.NewSMSSender()
+	if err != nil {
+		logging.Log.Fatal("Failed to configure the SMS sender", zap.Error(err))
+	}
+	allServices := services.NewServices(gormDB, settings)
+
+	// Create the default admin panel menus on a fresh database
+	if config.EnableMigration {
+		if err := allServices.MenuService.SeedDefaults(context.Background()); err != nil {
+			logging.Log.Fatal("Failed to seed default menus", zap.Error(err))
+		}
+	}
 
 	// Setup router
 	router := handlers.SetupRouter(allServices)
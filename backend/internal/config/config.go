@@ -2,11 +2,23 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"backend/internal/logging"
+	"backend/internal/notifications"
+	"backend/internal/payments"
+	"backend/internal/resources/constants"
+	"backend/internal/services"
+	"backend/pkg/jwt"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
+	// Deployment environment: development, test or production
+	AppEnv string `mapstructure:"APP_ENV"`
+
 	// Database settings
 	DBHost              string `mapstructure:"DB_HOST"`
 	DBPort              string `mapstructure:"DB_PORT"`
@@ -27,8 +39,52 @@ type Config struct {
 	ServerHost string `mapstructure:"SERVER_HOST"`
 
 	// JWT settings
-	JWTSecret          string `mapstructure:"JWT_SECRET"`
-	JWTExpirationHours int    `mapstructure:"JWT_EXPIRATION_HOURS"`
+	JWTSecret              string `mapstructure:"JWT_SECRET"`
+	JWTExpirationHours     int    `mapstructure:"JWT_EXPIRATION_HOURS"` // refresh token lifetime
+	JWTAccessTTLMinutes    int    `mapstructure:"JWT_ACCESS_TTL_MINUTES"`
+	JWTAlgorithm           string `mapstructure:"JWT_ALGORITHM"`
+	JWTSigningKeyID        string `mapstructure:"JWT_SIGNING_KEY_ID"`
+	JWTPrivateKeyPath      string `mapstructure:"JWT_PRIVATE_KEY_PATH"`
+	JWTVerificationKeys    string `mapstructure:"JWT_VERIFICATION_KEYS"`    // comma-separated kid=path of retired public keys
+	JWTVerificationSecrets string `mapstructure:"JWT_VERIFICATION_SECRETS"` // comma-separated kid=secret of retired HS256 secrets
+	JWTIssuer              string `mapstructure:"JWT_ISSUER"`
+	JWTAudience            string `mapstructure:"JWT_AUDIENCE"`
+
+	// Login brute-force protection settings
+	LoginMaxFailedAttempts    int `mapstructure:"LOGIN_MAX_FAILED_ATTEMPTS"`
+	LoginIPMaxFailedAttempts  int `mapstructure:"LOGIN_IP_MAX_FAILED_ATTEMPTS"`
+	LoginFailureWindowMinutes int `mapstructure:"LOGIN_FAILURE_WINDOW_MINUTES"`
+	LoginLockoutMinutes       int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+	LoginBackoffBaseSeconds   int `mapstructure:"LOGIN_BACKOFF_BASE_SECONDS"`
+
+	// Password reset settings
+	PasswordResetTTLMinutes int `mapstructure:"PASSWORD_RESET_TTL_MINUTES"`
+
+	// Mobile OTP login settings
+	OTPLength          int `mapstructure:"OTP_LENGTH"`
+	OTPTTLMinutes      int `mapstructure:"OTP_TTL_MINUTES"`
+	OTPResendSeconds   int `mapstructure:"OTP_RESEND_SECONDS"`
+	OTPMaxSendsPerHour int `mapstructure:"OTP_MAX_SENDS_PER_HOUR"`
+	OTPMaxAttempts     int `mapstructure:"OTP_MAX_ATTEMPTS"`
+
+	// Two-factor authentication settings
+	MFAIssuer            string `mapstructure:"MFA_ISSUER"`
+	MFARequiredRoles     string `mapstructure:"MFA_REQUIRED_ROLES"` // comma-separated roles that must enroll
+	MFAPendingTTLMinutes int    `mapstructure:"MFA_PENDING_TTL_MINUTES"`
+
+	// Admin impersonation settings
+	ImpersonationTTLMinutes int `mapstructure:"IMPERSONATION_TTL_MINUTES"`
+
+	// Kiosk check-in settings
+	CheckInCodeTTLSeconds int `mapstructure:"CHECKIN_CODE_TTL_SECONDS"`
+
+	// Payment settings
+	PaymentGateway       string `mapstructure:"PAYMENT_GATEWAY"`
+	PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+
+	// Notification settings
+	Notifier  string `mapstructure:"NOTIFIER"`
+	SMSSender string `mapstructure:"SMS_SENDER"`
 
 	// Logger settings
 	LogLevel       string `mapstructure:"LOG_LEVEL"`
@@ -49,6 +105,8 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("APP_ENV", "production")
+
 	// Set default values for database
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
@@ -67,6 +125,54 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetDefault("LOG_COMPRESS", true)
 	viper.SetDefault("LOG_DEVELOPMENT", false)
 
+	// Set default values for JWT
+	viper.SetDefault("JWT_SECRET", "")
+	viper.SetDefault("JWT_EXPIRATION_HOURS", 24)
+	viper.SetDefault("JWT_ACCESS_TTL_MINUTES", 60)
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
+	viper.SetDefault("JWT_SIGNING_KEY_ID", "default")
+	viper.SetDefault("JWT_PRIVATE_KEY_PATH", "")
+	viper.SetDefault("JWT_VERIFICATION_KEYS", "")
+	viper.SetDefault("JWT_VERIFICATION_SECRETS", "")
+	viper.SetDefault("JWT_ISSUER", "flexiofit")
+	viper.SetDefault("JWT_AUDIENCE", "flexio-admin")
+
+	// Set default values for login brute-force protection
+	viper.SetDefault("LOGIN_MAX_FAILED_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_IP_MAX_FAILED_ATTEMPTS", 20)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW_MINUTES", 15)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("LOGIN_BACKOFF_BASE_SECONDS", 1)
+
+	viper.SetDefault("PASSWORD_RESET_TTL_MINUTES", 30)
+
+	// Set default values for mobile OTP login
+	viper.SetDefault("OTP_LENGTH", 6)
+	viper.SetDefault("OTP_TTL_MINUTES", 5)
+	viper.SetDefault("OTP_RESEND_SECONDS", 60)
+	viper.SetDefault("OTP_MAX_SENDS_PER_HOUR", 5)
+	viper.SetDefault("OTP_MAX_ATTEMPTS", 5)
+
+	// Set default values for two-factor authentication
+	viper.SetDefault("MFA_ISSUER", "FlexioFit")
+	viper.SetDefault("MFA_REQUIRED_ROLES", "SUPERADMIN,ADMIN")
+	viper.SetDefault("MFA_PENDING_TTL_MINUTES", 5)
+
+	// Set default values for admin impersonation
+	viper.SetDefault("IMPERSONATION_TTL_MINUTES", 15)
+
+	// Set default values for kiosk check-in
+	viper.SetDefault("CHECKIN_CODE_TTL_SECONDS", 60)
+
+	// Set default values for payments; no gateway is chosen by default and
+	// webhooks are refused until a secret is set
+	viper.SetDefault("PAYMENT_GATEWAY", "")
+	viper.SetDefault("PAYMENT_WEBHOOK_SECRET", "")
+
+	// Set default values for notifications; nothing is chosen by default
+	viper.SetDefault("NOTIFIER", "")
+	viper.SetDefault("SMS_SENDER", "")
+
 	viper.SetDefault("ENABLE_MIGRATION", false)
 
 	err = viper.ReadInConfig()
@@ -90,3 +196,123 @@ func (c *Config) ToLoggerConfig() logging.LoggerConfig {
 		Development: c.LogDevelopment,
 	}
 }
+
+// Convert config to jwt.Config for token signing and verification
+func (c *Config) ToJWTConfig() jwt.Config {
+	return jwt.Config{
+		Algorithm:           c.JWTAlgorithm,
+		Secret:              c.JWTSecret,
+		SigningKeyID:        c.JWTSigningKeyID,
+		PrivateKeyPath:      c.JWTPrivateKeyPath,
+		VerificationKeys:    keyList(c.JWTVerificationKeys),
+		VerificationSecrets: keyList(c.JWTVerificationSecrets),
+		Issuer:              c.JWTIssuer,
+		Audience:            c.JWTAudience,
+		AccessTTL:           time.Duration(c.JWTAccessTTLMinutes) * time.Minute,
+		RefreshTTL:          time.Duration(c.JWTExpirationHours) * time.Hour,
+	}
+}
+
+// keyList parses a comma-separated list of kid=value pairs
+func keyList(list string) map[string]string {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		kid, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && kid != "" && value != "" {
+			keys[kid] = value
+		}
+	}
+	return keys
+}
+
+// Convert config to services.Settings for service construction
+func (c *Config) ToServiceSettings() services.Settings {
+	var mfaRequiredRoles []resources.USERROLE
+	for _, name := range strings.Split(c.MFARequiredRoles, ",") {
+		if role := resources.GetUserType(strings.TrimSpace(name)); role != resources.INVALID {
+			mfaRequiredRoles = append(mfaRequiredRoles, role)
+		}
+	}
+
+	return services.Settings{
+		Lockout: services.LockoutPolicy{
+			MaxAccountFailures: c.LoginMaxFailedAttempts,
+			MaxIPFailures:      c.LoginIPMaxFailedAttempts,
+			FailureWindow:      time.Duration(c.LoginFailureWindowMinutes) * time.Minute,
+			LockoutDuration:    time.Duration(c.LoginLockoutMinutes) * time.Minute,
+			BackoffBase:        time.Duration(c.LoginBackoffBaseSeconds) * time.Second,
+		},
+		PasswordResetTTL: time.Duration(c.PasswordResetTTLMinutes) * time.Minute,
+		OTP: services.OTPPolicy{
+			Length:          c.OTPLength,
+			TTL:             time.Duration(c.OTPTTLMinutes) * time.Minute,
+			ResendInterval:  time.Duration(c.OTPResendSeconds) * time.Second,
+			MaxSendsPerHour: c.OTPMaxSendsPerHour,
+			MaxAttempts:     c.OTPMaxAttempts,
+		},
+		MFA: services.MFAPolicy{
+			Issuer:        c.MFAIssuer,
+			RequiredRoles: mfaRequiredRoles,
+			PendingTTL:    time.Duration(c.MFAPendingTTLMinutes) * time.Minute,
+		},
+		ImpersonationTTL: time.Duration(c.ImpersonationTTLMinutes) * time.Minute,
+		CheckInCodeTTL:   time.Duration(c.CheckInCodeTTLSeconds) * time.Second,
+	}
+}
+
+// IsDevelopment reports whether the server runs for development or tests
+func (c *Config) IsDevelopment() bool {
+	env := strings.ToLower(strings.TrimSpace(c.AppEnv))
+	return env == "development" || env == "test"
+}
+
+// NewPaymentGateway creates the configured payment gateway. The local gateway
+// reports charges captured without moving any money, so it is only allowed in
+// development and tests; anywhere else a real gateway must be configured.
+func (c *Config) NewPaymentGateway() (payments.PaymentGateway, error) {
+	switch name := strings.ToLower(strings.TrimSpace(c.PaymentGateway)); name {
+	case "local":
+		if !c.IsDevelopment() {
+			return nil, fmt.Errorf("the local payment gateway moves no money and is only allowed when APP_ENV is development or test, not %q", c.AppEnv)
+		}
+		return payments.NewLocalGateway(c.PaymentWebhookSecret), nil
+	case "":
+		return nil, fmt.Errorf("no payment gateway configured: set PAYMENT_GATEWAY")
+	default:
+		return nil, fmt.Errorf("unknown payment gateway %q", c.PaymentGateway)
+	}
+}
+
+// NewNotifier creates the configured email notifier. The log notifier delivers
+// nothing, so it is only allowed in development and tests; anywhere else a real
+// notifier must be configured, or password reset emails would be lost.
+func (c *Config) NewNotifier() (notifications.Notifier, error) {
+	switch name := strings.ToLower(strings.TrimSpace(c.Notifier)); name {
+	case "log":
+		if !c.IsDevelopment() {
+			return nil, fmt.Errorf("the log notifier delivers no email and is only allowed when APP_ENV is development or test, not %q", c.AppEnv)
+		}
+		return notifications.NewLogNotifier(), nil
+	case "":
+		return nil, fmt.Errorf("no notifier configured: set NOTIFIER")
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", c.Notifier)
+	}
+}
+
+// NewSMSSender creates the configured SMS sender. The log sender delivers
+// nothing, so it is only allowed in development and tests; anywhere else a real
+// sender must be configured, or login codes and class notices would be lost.
+func (c *Config) NewSMSSender() (notifications.SMSSender, error) {
+	switch name := strings.ToLower(strings.TrimSpace(c.SMSSender)); name {
+	case "log":
+		if !c.IsDevelopment() {
+			return nil, fmt.Errorf("the log SMS sender delivers no text messages and is only allowed when APP_ENV is development or test, not %q", c.AppEnv)
+		}
+		return notifications.NewLogSMSSender(), nil
+	case "":
+		return nil, fmt.Errorf("no SMS sender configured: set SMS_SENDER")
+	default:
+		return nil, fmt.Errorf("unknown SMS sender %q", c.SMSSender)
+	}
+}
@@ -31,6 +31,39 @@ type UserInfoResponse struct {
     Msg  string `json:"msg"`
 }
 
+type ForgotPasswordRequest struct {
+    Identifier string `json:"identifier" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+    Token    string `json:"token" binding:"required"`
+    Password string `json:"password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+    CurrentPassword string `json:"currentPassword" binding:"required"`
+    NewPassword     string `json:"newPassword" binding:"required,min=6"`
+}
+
+type OTPRequest struct {
+    Mobile string `json:"mobile" binding:"required"`
+}
+
+type OTPVerifyRequest struct {
+    Mobile string `json:"mobile" binding:"required"`
+    Code   string `json:"code" binding:"required"`
+}
+
+type MFAVerifyRequest struct {
+    MfaToken     string `json:"mfaToken" binding:"required"`
+    Code         string `json:"code"`
+    RecoveryCode string `json:"recoveryCode"`
+}
+
+type MFAConfirmRequest struct {
+    Code string `json:"code" binding:"required"`
+}
+
 // JWT Claims structure
 type JWTClaims struct {
     Data []map[string]interface{} `json:"data"`
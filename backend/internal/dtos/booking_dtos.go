@@ -0,0 +1,68 @@
+package dtos
+
+import "time"
+
+type ClassBookingDTO struct {
+	ID           uint       `json:"id"`
+	SessionID    uint       `json:"session_id"`
+	CustomerID   uint       `json:"customer_id"`
+	CustomerName string     `json:"customer_name,omitempty"`
+	Status       string     `json:"status"`
+	BookedAt     *time.Time `json:"booked_at,omitempty"`
+	WaitlistedAt *time.Time `json:"waitlisted_at,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// MyBookingDTO is a booking of the signed-in customer with its session
+type MyBookingDTO struct {
+	ClassBookingDTO
+	Session ClassSessionDTO `json:"session"`
+}
+
+// BookableSessionDTO is a session a customer can book, with its spots left
+type BookableSessionDTO struct {
+	ClassSessionDTO
+	SpotsLeft  int `json:"spots_left"`
+	Waitlisted int `json:"waitlisted"`
+}
+
+// BookClassRequest books a session for a customer of the crew
+type BookClassRequest struct {
+	CustomerID uint `json:"customer_id" binding:"required"`
+}
+
+// MarkAttendanceRequest records whether a booked customer came
+type MarkAttendanceRequest struct {
+	Attended *bool `json:"attended" binding:"required"`
+}
+
+// MyBookingFilter holds the query parameters of the signed-in customer's
+// bookings; bookings of past sessions are left out unless asked for
+type MyBookingFilter struct {
+	PageQuery
+	Status      string `form:"status"`
+	IncludePast bool   `form:"include_past"`
+}
+
+// BookableSessionFilter holds the query parameters of the sessions a customer can book
+type BookableSessionFilter struct {
+	PageQuery
+	ServiceID *uint `form:"service_id"`
+}
+
+type BookingPolicyDTO struct {
+	AllieID             int  `json:"allie_id"`
+	BookingWindowDays   int  `json:"booking_window_days"`
+	CancelCutoffMinutes int  `json:"cancel_cutoff_minutes"`
+	MaxStrikes          int  `json:"max_strikes"`
+	StrikeWindowDays    int  `json:"strike_window_days"`
+	IsDefault           bool `json:"is_default"`
+}
+
+type UpdateBookingPolicyRequest struct {
+	BookingWindowDays   int  `json:"booking_window_days" binding:"required,min=1,max=90"`
+	CancelCutoffMinutes *int `json:"cancel_cutoff_minutes" binding:"required,min=0,max=2880"`
+	MaxStrikes          *int `json:"max_strikes" binding:"required,min=0,max=50"`
+	StrikeWindowDays    int  `json:"strike_window_days" binding:"required,min=1,max=365"`
+}
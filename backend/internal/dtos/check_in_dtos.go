@@ -0,0 +1,61 @@
+package dtos
+
+import "time"
+
+// CheckInRequest checks a customer of the crew in; method is DESK by default, or KIOSK
+type CheckInRequest struct {
+	CustomerID uint   `json:"customer_id" binding:"required"`
+	Method     string `json:"method"`
+}
+
+type CheckOutRequest struct {
+	CustomerID uint `json:"customer_id" binding:"required"`
+}
+
+type CrewVisitDTO struct {
+	ID           uint       `json:"id"`
+	CrewID       int        `json:"crew_id"`
+	CustomerID   uint       `json:"customer_id"`
+	CustomerName string     `json:"customer_name,omitempty"`
+	VisitDate    string     `json:"visit_date"`
+	CheckedInAt  time.Time  `json:"checked_in_at"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	Method       string     `json:"method"`
+	AutoClosed   bool       `json:"auto_closed"`
+}
+
+// CrewOccupancyDTO is who is inside a crew right now
+type CrewOccupancyDTO struct {
+	CrewID    uint           `json:"crew_id"`
+	Capacity  int            `json:"capacity"` // 0 is unlimited
+	Occupancy int            `json:"occupancy"`
+	SpotsLeft *int           `json:"spots_left,omitempty"`
+	Visitors  []CrewVisitDTO `json:"visitors"`
+}
+
+// VisitHistoryFilter holds the query parameters of a customer's attendance
+// history. from and to are dates on the crew's clock, to included.
+type VisitHistoryFilter struct {
+	PageQuery
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// CheckInCodeQuery picks how the signed-in customer's check-in code is returned:
+// json (default) with the signed code, or the code rendered as a png or svg QR image
+type CheckInCodeQuery struct {
+	Format string `form:"format"`
+}
+
+// CheckInCodeDTO is a short-lived check-in code; clients render it as a QR
+// code and fetch a new one before it expires
+type CheckInCodeDTO struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ExpiresIn int       `json:"expires_in"` // seconds
+}
+
+// ScanCheckInCodeRequest checks in the customer whose code the kiosk scanned
+type ScanCheckInCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -0,0 +1,95 @@
+package dtos
+
+import "time"
+
+// ClassTemplateDTO is a weekly class; times and dates are on the crew's clock
+type ClassTemplateDTO struct {
+	ID              uint          `json:"id"`
+	CrewID          int           `json:"crew_id"`
+	Service         FitServiceDTO `json:"service"`
+	TrainerID       uint          `json:"trainer_id"`
+	TrainerName     string        `json:"trainer_name"`
+	Name            string        `json:"name"`
+	Description     string        `json:"description,omitempty"`
+	Weekdays        []string      `json:"weekdays"`
+	StartTime       string        `json:"start_time"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Room            string        `json:"room,omitempty"`
+	Capacity        int           `json:"capacity"`
+	StartsOn        string        `json:"starts_on"`
+	EndsOn          *string       `json:"ends_on,omitempty"`
+	IsActive        bool          `json:"is_active"`
+}
+
+type CreateClassTemplateRequest struct {
+	ServiceID       uint     `json:"service_id" binding:"required"`
+	TrainerID       uint     `json:"trainer_id" binding:"required"`
+	Name            string   `json:"name" binding:"required,max=100"`
+	Description     string   `json:"description" binding:"max=500"`
+	Weekdays        []string `json:"weekdays" binding:"required,min=1,max=7"`
+	StartTime       string   `json:"start_time" binding:"required"`
+	DurationMinutes int      `json:"duration_minutes" binding:"required,min=5,max=480"`
+	Room            string   `json:"room" binding:"max=50"`
+	Capacity        int      `json:"capacity" binding:"required,min=1"`
+	StartsOn        string   `json:"starts_on" binding:"required"`
+	EndsOn          string   `json:"ends_on"`
+}
+
+// UpdateClassTemplateRequest changes a class; its upcoming sessions that were
+// not edited on their own are generated again
+type UpdateClassTemplateRequest struct {
+	CreateClassTemplateRequest
+	IsActive *bool `json:"is_active"` // deactivating removes the upcoming sessions
+}
+
+// GenerateSessionsRequest generates the sessions of a class up to a date included
+type GenerateSessionsRequest struct {
+	Until string `json:"until" binding:"required"`
+}
+
+type GenerateSessionsDTO struct {
+	Generated int64 `json:"generated"`
+}
+
+type ClassSessionDTO struct {
+	ID           uint          `json:"id"`
+	TemplateID   *uint         `json:"template_id,omitempty"`
+	CrewID       int           `json:"crew_id"`
+	Service      FitServiceDTO `json:"service"`
+	TrainerID    uint          `json:"trainer_id"`
+	TrainerName  string        `json:"trainer_name"`
+	Name         string        `json:"name"`
+	Room         string        `json:"room,omitempty"`
+	Capacity     int           `json:"capacity"`
+	StartsAt     time.Time     `json:"starts_at"`
+	EndsAt       time.Time     `json:"ends_at"`
+	Status       string        `json:"status"`
+	Modified     bool          `json:"modified"`
+	CancelReason string        `json:"cancel_reason,omitempty"`
+}
+
+// ClassSessionFilter holds the query parameters of the session list. from and
+// to are dates on the crew's clock, to included; they default to the next 7 days.
+type ClassSessionFilter struct {
+	PageQuery
+	TrainerID *uint  `form:"trainer_id"`
+	ServiceID *uint  `form:"service_id"`
+	Status    string `form:"status"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+}
+
+// UpdateClassSessionRequest edits one session; fields left out are kept. date
+// and start_time are on the crew's clock.
+type UpdateClassSessionRequest struct {
+	Date            *string `json:"date"`
+	StartTime       *string `json:"start_time"`
+	DurationMinutes *int    `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+	Room            *string `json:"room" binding:"omitempty,max=50"`
+	Capacity        *int    `json:"capacity" binding:"omitempty,min=1"`
+	TrainerID       *uint   `json:"trainer_id"`
+}
+
+type CancelClassSessionRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
@@ -0,0 +1,61 @@
+package dtos
+
+import "time"
+
+// OpeningHourDTO is one opening interval; times are HH:MM on the crew's clock
+type OpeningHourDTO struct {
+	Weekday  string `json:"weekday"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
+
+type CrewHolidayDTO struct {
+	ID       uint    `json:"id"`
+	FromDate string  `json:"from_date"`
+	ToDate   string  `json:"to_date"`
+	Name     string  `json:"name"`
+	OpensAt  *string `json:"opens_at,omitempty"` // set when the crew opens with special hours
+	ClosesAt *string `json:"closes_at,omitempty"`
+}
+
+// CrewScheduleDTO is the weekly hours of a crew with its upcoming holidays
+type CrewScheduleDTO struct {
+	CrewID       uint             `json:"crew_id"`
+	Timezone     string           `json:"timezone"`
+	IsOpenNow    bool             `json:"is_open_now"`
+	OpeningHours []OpeningHourDTO `json:"opening_hours"`
+	Holidays     []CrewHolidayDTO `json:"holidays"`
+}
+
+type OpeningHourRequest struct {
+	Weekday  string `json:"weekday" binding:"required"`
+	OpensAt  string `json:"opens_at" binding:"required"`
+	ClosesAt string `json:"closes_at" binding:"required"`
+}
+
+// SetOpeningHoursRequest replaces every weekly interval of a crew; days left
+// out are closed
+type SetOpeningHoursRequest struct {
+	Hours []OpeningHourRequest `json:"hours" binding:"dive"`
+}
+
+// CreateCrewHolidayRequest closes a crew, or gives it special hours, from
+// from_date to to_date included. to_date defaults to from_date.
+type CreateCrewHolidayRequest struct {
+	FromDate string  `json:"from_date" binding:"required"`
+	ToDate   string  `json:"to_date"`
+	Name     string  `json:"name" binding:"required,max=100"`
+	OpensAt  *string `json:"opens_at"`
+	ClosesAt *string `json:"closes_at"`
+}
+
+// CrewOpenQuery holds the optional RFC 3339 time to check; now when empty
+type CrewOpenQuery struct {
+	At string `form:"at"`
+}
+
+type CrewOpenDTO struct {
+	CrewID uint      `json:"crew_id"`
+	At     time.Time `json:"at"`
+	IsOpen bool      `json:"is_open"`
+}
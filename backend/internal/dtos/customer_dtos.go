@@ -0,0 +1,96 @@
+package dtos
+
+import "time"
+
+type CustomerDTO struct {
+	ID               uint       `json:"id"`
+	UserID           uint       `json:"user_id"`
+	CrewID           int        `json:"crew_id"`
+	FirstName        string     `json:"first_name"`
+	MiddleName       string     `json:"middle_name"`
+	LastName         string     `json:"last_name"`
+	Email            string     `json:"email"`
+	Mobile           string     `json:"mobile"`
+	AlternateMobile  string     `json:"alternate_mobile"`
+	DateOfBirth      *time.Time `json:"date_of_birth,omitempty"`
+	State            string     `json:"state,omitempty"`
+	MembershipStart  time.Time  `json:"membership_start"`
+	MembershipEnd    time.Time  `json:"membership_end"`
+	FrozenFrom       *time.Time `json:"frozen_from,omitempty"`
+	FrozenUntil      *time.Time `json:"frozen_until,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	PlanID           *uint      `json:"plan_id,omitempty"`
+	FreezeDaysLeft   int        `json:"freeze_days_left"`
+	VisitLimit       int        `json:"visit_limit"`
+	VisitsPerDay     int        `json:"visits_per_day"`
+	MembershipStatus string     `json:"membership_status"`
+	IsActive         bool       `json:"is_active"`
+}
+
+type MembershipHistoryDTO struct {
+	ID          uint       `json:"id"`
+	Action      string     `json:"action"`
+	FromCrewID  int        `json:"from_crew_id,omitempty"`
+	ToCrewID    int        `json:"to_crew_id,omitempty"`
+	PreviousEnd *time.Time `json:"previous_end,omitempty"`
+	NewEnd      *time.Time `json:"new_end,omitempty"`
+	Days        int        `json:"days,omitempty"`
+	Note        string     `json:"note,omitempty"`
+	PlanID      *uint      `json:"plan_id,omitempty"`
+	PlanName    string     `json:"plan_name,omitempty"`
+	PriceMinor  int64      `json:"price_minor,omitempty"`
+	TaxMinor    int64      `json:"tax_minor,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	PerformedBy uint       `json:"performed_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// EnrollCustomerRequest enrolls a customer on a plan; dates are YYYY-MM-DD and
+// the membership starts today when start_date is omitted
+type EnrollCustomerRequest struct {
+	FirstName       string `json:"first_name" binding:"required"`
+	MiddleName      string `json:"middle_name"`
+	LastName        string `json:"last_name" binding:"required"`
+	Email           string `json:"email" binding:"required,email"`
+	Mobile          string `json:"mobile" binding:"required"`
+	AlternateMobile string `json:"alternate_mobile"`
+	DateOfBirth     string `json:"date_of_birth"`
+	State           string `json:"state"` // billing state, the place of supply of the customer's invoices
+	StartDate       string `json:"start_date"`
+	PlanID          uint   `json:"plan_id" binding:"required"`
+	Method          string `json:"method"` // pays the first term at once; the order is left pending when omitted
+}
+
+// RenewMembershipRequest renews on a plan, the customer's current one when plan_id is omitted
+type RenewMembershipRequest struct {
+	PlanID *uint  `json:"plan_id"`
+	Method string `json:"method" binding:"required"`
+}
+
+type ExtendMembershipRequest struct {
+	Days int    `json:"days" binding:"required,min=1"`
+	Note string `json:"note" binding:"max=255"`
+}
+
+// FreezeMembershipRequest pauses a membership; the freeze starts now when from is omitted
+type FreezeMembershipRequest struct {
+	From string `json:"from"`
+	Days int    `json:"days" binding:"required,min=1"`
+	Note string `json:"note" binding:"max=255"`
+}
+
+type TransferMembershipRequest struct {
+	CrewID uint   `json:"crew_id" binding:"required"`
+	Note   string `json:"note" binding:"max=255"`
+}
+
+type MembershipNoteRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+// CustomerFilter holds the query parameters of the customer list
+type CustomerFilter struct {
+	PageQuery
+	Search   string `form:"search"`
+	IsActive *bool  `form:"is_active"`
+}
@@ -0,0 +1,88 @@
+package dtos
+
+import (
+	"time"
+)
+
+type FitAllieDTO struct {
+	ID                uint      `json:"id"`
+	UserID            int       `json:"user_id"`
+	BusinessName      string    `json:"business_name"`
+	OwnerFirstName    string    `json:"owner_first_name"`
+	OwnerMiddleName   string    `json:"owner_middle_name"`
+	OwnerLastName     string    `json:"owner_last_name"`
+	CoOwnerFirstName  string    `json:"co_owner_first_name"`
+	CoOwnerMiddleName string    `json:"co_owner_middle_name"`
+	CoOwnerLastName   string    `json:"co_owner_last_name"`
+	Email             string    `json:"email"`
+	Mobile            string    `json:"mobile"`
+	AlternateMobile   string    `json:"alternate_mobile"`
+	CommissionRate    int       `json:"commission_rate"`
+	NoOfBranch        int       `json:"no_of_branch"`
+	IsActive          bool      `json:"is_active"`
+	Address           string    `json:"address"`
+	City              string    `json:"city"`
+	State             string    `json:"state"`
+	PinCode           string    `json:"pin_code"`
+	GSTIN             string    `json:"gstin,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type CreateFitAllieRequest struct {
+	BusinessName      string `json:"business_name" binding:"required"`
+	OwnerFirstName    string `json:"owner_first_name" binding:"required"`
+	OwnerMiddleName   string `json:"owner_middle_name"`
+	OwnerLastName     string `json:"owner_last_name"`
+	CoOwnerFirstName  string `json:"co_owner_first_name"`
+	CoOwnerMiddleName string `json:"co_owner_middle_name"`
+	CoOwnerLastName   string `json:"co_owner_last_name"`
+	Email             string `json:"email" binding:"required,email"`
+	Mobile            string `json:"mobile" binding:"required"`
+	AlternateMobile   string `json:"alternate_mobile"`
+	CommissionRate    int    `json:"commission_rate"`
+	NoOfBranch        int    `json:"no_of_branch"`
+	Address           string `json:"address"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	PinCode           string `json:"pin_code"`
+	GSTIN             string `json:"gstin"`
+	// Owner login; when set, a GYM user is created together with the allie
+	Account *FitAllieAccountRequest `json:"account"`
+}
+
+// FitAllieAccountRequest holds the login of the GYM user created for an allie.
+// The password is temporary and must be changed at first login.
+type FitAllieAccountRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type UpdateFitAllieRequest struct {
+	BusinessName      string `json:"business_name" binding:"required"`
+	OwnerFirstName    string `json:"owner_first_name" binding:"required"`
+	OwnerMiddleName   string `json:"owner_middle_name"`
+	OwnerLastName     string `json:"owner_last_name"`
+	CoOwnerFirstName  string `json:"co_owner_first_name"`
+	CoOwnerMiddleName string `json:"co_owner_middle_name"`
+	CoOwnerLastName   string `json:"co_owner_last_name"`
+	Email             string `json:"email" binding:"required,email"`
+	Mobile            string `json:"mobile" binding:"required"`
+	AlternateMobile   string `json:"alternate_mobile"`
+	CommissionRate    int    `json:"commission_rate"`
+	NoOfBranch        int    `json:"no_of_branch"`
+	Address           string `json:"address"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	PinCode           string `json:"pin_code"`
+	GSTIN             string `json:"gstin"`
+	IsActive          *bool  `json:"is_active"`
+}
+
+// FitAllieFilter holds the query parameters of the allie list
+type FitAllieFilter struct {
+	PageQuery
+	Search   string `form:"search"`
+	City     string `form:"city"`
+	State    string `form:"state"`
+	IsActive *bool  `form:"is_active"`
+}
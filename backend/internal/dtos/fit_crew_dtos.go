@@ -0,0 +1,101 @@
+package dtos
+
+type FitCrewDTO struct {
+	ID                uint     `json:"id"`
+	AllieID           int      `json:"allie_id"`
+	GymName           string   `json:"gym_name"`
+	ManagerFirstName  string   `json:"manager_first_name"`
+	ManagerMiddleName string   `json:"manager_middle_name"`
+	ManagerLastName   string   `json:"manager_last_name"`
+	Email             string   `json:"email"`
+	Mobile            string   `json:"mobile"`
+	AlternateMobile   string   `json:"alternate_mobile"`
+	IsActive          bool     `json:"is_active"`
+	Address           string   `json:"address"`
+	City              string   `json:"city"`
+	State             string   `json:"state"`
+	PinCode           string   `json:"pin_code"`
+	Lat               *float64 `json:"lat"`
+	Long              *float64 `json:"long"`
+	Capacity          int      `json:"capacity"`
+	Timezone          string   `json:"timezone"`
+}
+
+type CreateFitCrewRequest struct {
+	GymName           string `json:"gym_name" binding:"required"`
+	ManagerFirstName  string `json:"manager_first_name" binding:"required"`
+	ManagerMiddleName string `json:"manager_middle_name"`
+	ManagerLastName   string `json:"manager_last_name"`
+	Email             string `json:"email" binding:"required,email"`
+	Mobile            string `json:"mobile" binding:"required"`
+	AlternateMobile   string `json:"alternate_mobile"`
+	Address           string `json:"address"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	PinCode           string `json:"pin_code"`
+	Lat               string `json:"lat"`
+	Long              string `json:"long"`
+	Capacity          int    `json:"capacity" binding:"min=0"`
+	Timezone          string `json:"timezone"` // IANA zone, defaults to Asia/Kolkata
+}
+
+type UpdateFitCrewRequest struct {
+	GymName           string `json:"gym_name" binding:"required"`
+	ManagerFirstName  string `json:"manager_first_name" binding:"required"`
+	ManagerMiddleName string `json:"manager_middle_name"`
+	ManagerLastName   string `json:"manager_last_name"`
+	Email             string `json:"email" binding:"required,email"`
+	Mobile            string `json:"mobile" binding:"required"`
+	AlternateMobile   string `json:"alternate_mobile"`
+	Address           string `json:"address"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	PinCode           string `json:"pin_code"`
+	Lat               string `json:"lat"`
+	Long              string `json:"long"`
+	Capacity          int    `json:"capacity" binding:"min=0"`
+	Timezone          string `json:"timezone"`  // IANA zone, unchanged when empty
+	IsActive          *bool  `json:"is_active"` // deactivating hides the crew's trainers
+}
+
+// PublicFitCrewDTO is the view of a crew shown to customers looking for a gym
+type PublicFitCrewDTO struct {
+	ID       uint     `json:"id"`
+	AllieID  int      `json:"allie_id"`
+	GymName  string   `json:"gym_name"`
+	Mobile   string   `json:"mobile"`
+	Address  string   `json:"address"`
+	City     string   `json:"city"`
+	State    string   `json:"state"`
+	PinCode  string   `json:"pin_code"`
+	Lat      *float64 `json:"lat"`
+	Long     *float64 `json:"long"`
+	Timezone string   `json:"timezone"`
+}
+
+// FitCrewSearchFilter holds the query parameters of the public gym search
+type FitCrewSearchFilter struct {
+	PageQuery
+	Search    string `form:"search"`
+	City      string `form:"city"`
+	State     string `form:"state"`
+	ServiceID *uint  `form:"service_id"`
+}
+
+// NearbyFitCrewDTO is a gym found by the nearby search with its distance from the customer
+type NearbyFitCrewDTO struct {
+	PublicFitCrewDTO
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyFitCrewFilter holds the query parameters of the nearby gym search.
+// The radius is in kilometres.
+type NearbyFitCrewFilter struct {
+	PageQuery
+	Lat       *float64 `form:"lat" binding:"required,min=-90,max=90"`
+	Lng       *float64 `form:"lng" binding:"required,min=-180,max=180"`
+	Radius    float64  `form:"radius,default=5" binding:"gt=0,max=50"`
+	ServiceID *uint    `form:"service"`
+	OpenNow   bool     `form:"open_now"`
+	HasSpace  bool     `form:"has_space"` // below capacity by live occupancy
+}
@@ -0,0 +1,62 @@
+package dtos
+
+type FitServiceDTO struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Icon        string `json:"icon"`
+	Description string `json:"description,omitempty"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type CreateFitServiceRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Category    string `json:"category" binding:"max=50"`
+	Icon        string `json:"icon" binding:"max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type UpdateFitServiceRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Category    string `json:"category" binding:"max=50"`
+	Icon        string `json:"icon" binding:"max=100"`
+	Description string `json:"description" binding:"max=500"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// FitServiceFilter holds the query parameters of the catalog
+type FitServiceFilter struct {
+	Category string `form:"category"`
+	Search   string `form:"search"`
+}
+
+// OfferingDTO is a service offered by an allie; price_minor is the per-visit
+// price in minor units and is omitted when the service is included
+type OfferingDTO struct {
+	ID         uint          `json:"id"`
+	AllieID    int           `json:"allie_id"`
+	CrewID     *int          `json:"crew_id,omitempty"`
+	Service    FitServiceDTO `json:"service"`
+	PriceMinor *int64        `json:"price_minor,omitempty"`
+	Currency   string        `json:"currency,omitempty"`
+	IsActive   bool          `json:"is_active"`
+}
+
+// CreateOfferingRequest offers a service at every crew of the allie, or at one crew
+type CreateOfferingRequest struct {
+	ServiceID  uint   `json:"service_id" binding:"required"`
+	CrewID     *int   `json:"crew_id"`
+	PriceMinor *int64 `json:"price_minor" binding:"omitempty,min=0"`
+	Currency   string `json:"currency"`
+}
+
+type UpdateOfferingRequest struct {
+	PriceMinor *int64 `json:"price_minor" binding:"omitempty,min=0"`
+	Currency   string `json:"currency"`
+	IsActive   *bool  `json:"is_active"`
+}
+
+// OfferingFilter holds the query parameters of the offering list
+type OfferingFilter struct {
+	CrewID *uint `form:"crew_id"`
+}
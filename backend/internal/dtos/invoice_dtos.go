@@ -0,0 +1,40 @@
+package dtos
+
+import "time"
+
+// InvoiceDTO is a GST tax invoice or credit note. Amounts are in minor units;
+// state codes are GST codes, 29 for Karnataka.
+type InvoiceDTO struct {
+	ID              uint      `json:"id"`
+	Number          string    `json:"number"`
+	Kind            string    `json:"kind"`
+	FiscalYear      string    `json:"fiscal_year"`
+	IssuedAt        time.Time `json:"issued_at"`
+	OrderID         uint      `json:"order_id"`
+	RefundID        *uint     `json:"refund_id,omitempty"`
+	OriginalNumber  string    `json:"original_number,omitempty"`
+	CrewID          int       `json:"crew_id"`
+	CustomerID      uint      `json:"customer_id"`
+	SellerName      string    `json:"seller_name"`
+	SellerAddress   string    `json:"seller_address"`
+	SellerGSTIN     string    `json:"seller_gstin,omitempty"`
+	SellerStateCode string    `json:"seller_state_code"`
+	BuyerName       string    `json:"buyer_name"`
+	PlaceOfSupply   string    `json:"place_of_supply"`
+	Description     string    `json:"description"`
+	SAC             string    `json:"sac"`
+	TaxRateBps      int       `json:"tax_rate_bps"`
+	TaxableMinor    int64     `json:"taxable_minor"`
+	CGSTMinor       int64     `json:"cgst_minor"`
+	SGSTMinor       int64     `json:"sgst_minor"`
+	IGSTMinor       int64     `json:"igst_minor"`
+	TotalMinor      int64     `json:"total_minor"`
+	Currency        string    `json:"currency"`
+}
+
+// InvoiceFilter holds the query parameters of invoice listings; kind is INVOICE or CREDIT_NOTE
+type InvoiceFilter struct {
+	PageQuery
+	Kind    string `form:"kind"`
+	OrderID *uint  `form:"order_id"`
+}
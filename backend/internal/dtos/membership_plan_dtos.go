@@ -0,0 +1,55 @@
+package dtos
+
+// MembershipPlanDTO carries prices in minor units (paise for INR), excluding tax
+type MembershipPlanDTO struct {
+	ID           uint            `json:"id"`
+	AllieID      int             `json:"allie_id"`
+	CrewID       *int            `json:"crew_id,omitempty"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	DurationDays int             `json:"duration_days"`
+	PriceMinor   int64           `json:"price_minor"`
+	TaxMinor     int64           `json:"tax_minor"`
+	Currency     string          `json:"currency"`
+	TaxRateBps   int             `json:"tax_rate_bps"`
+	FreezeDays   int             `json:"freeze_days"`
+	VisitLimit   int             `json:"visit_limit"`
+	VisitsPerDay int             `json:"visits_per_day"`
+	IsActive     bool            `json:"is_active"`
+	Services     []FitServiceDTO `json:"services"`
+}
+
+type CreateMembershipPlanRequest struct {
+	CrewID       *int   `json:"crew_id"`
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description" binding:"max=500"`
+	DurationDays int    `json:"duration_days" binding:"required,min=1"`
+	PriceMinor   int64  `json:"price_minor" binding:"min=0"`
+	Currency     string `json:"currency"`
+	TaxRateBps   int    `json:"tax_rate_bps" binding:"min=0,max=10000"`
+	FreezeDays   int    `json:"freeze_days" binding:"min=0"`
+	VisitLimit   int    `json:"visit_limit" binding:"min=0"`
+	VisitsPerDay int    `json:"visits_per_day" binding:"min=0"`
+	ServiceIDs   []uint `json:"service_ids"`
+}
+
+type UpdateMembershipPlanRequest struct {
+	CrewID       *int   `json:"crew_id"`
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description" binding:"max=500"`
+	DurationDays int    `json:"duration_days" binding:"required,min=1"`
+	PriceMinor   int64  `json:"price_minor" binding:"min=0"`
+	Currency     string `json:"currency"`
+	TaxRateBps   int    `json:"tax_rate_bps" binding:"min=0,max=10000"`
+	FreezeDays   int    `json:"freeze_days" binding:"min=0"`
+	VisitLimit   int    `json:"visit_limit" binding:"min=0"`
+	VisitsPerDay int    `json:"visits_per_day" binding:"min=0"`
+	ServiceIDs   []uint `json:"service_ids"`
+	IsActive     *bool  `json:"is_active"`
+}
+
+// MembershipPlanFilter holds the query parameters of the plan list
+type MembershipPlanFilter struct {
+	CrewID     *uint `form:"crew_id"`
+	ActiveOnly bool  `form:"active_only"`
+}
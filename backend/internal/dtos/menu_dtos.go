@@ -0,0 +1,71 @@
+package dtos
+
+// MenuRouteMeta is the route metadata the admin panel router expects
+type MenuRouteMeta struct {
+	Title      string `json:"title"`
+	I18nKey    string `json:"i18nKey,omitempty"`
+	Icon       string `json:"icon,omitempty"`
+	Order      int    `json:"order"`
+	Constant   bool   `json:"constant,omitempty"`
+	HideInMenu bool   `json:"hideInMenu,omitempty"`
+	KeepAlive  bool   `json:"keepAlive,omitempty"`
+}
+
+// MenuRouteDTO is a node of the route tree served to the admin panel
+type MenuRouteDTO struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Path      string         `json:"path"`
+	Component string         `json:"component"`
+	Meta      MenuRouteMeta  `json:"meta"`
+	Children  []MenuRouteDTO `json:"children,omitempty"`
+}
+
+type MenuDTO struct {
+	ID         uint      `json:"id"`
+	ParentID   *uint     `json:"parent_id"`
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Component  string    `json:"component"`
+	Title      string    `json:"title"`
+	I18nKey    string    `json:"i18n_key"`
+	Icon       string    `json:"icon"`
+	Order      int       `json:"order"`
+	Permission string    `json:"permission"`
+	Constant   bool      `json:"constant"`
+	HideInMenu bool      `json:"hide_in_menu"`
+	KeepAlive  bool      `json:"keep_alive"`
+	IsActive   bool      `json:"is_active"`
+	Children   []MenuDTO `json:"children,omitempty"`
+}
+
+type CreateMenuRequest struct {
+	ParentID   *uint  `json:"parent_id"`
+	Name       string `json:"name" binding:"required"`
+	Path       string `json:"path" binding:"required"`
+	Component  string `json:"component"`
+	Title      string `json:"title" binding:"required"`
+	I18nKey    string `json:"i18n_key"`
+	Icon       string `json:"icon"`
+	Order      int    `json:"order"`
+	Permission string `json:"permission"` // empty means every signed-in role
+	Constant   bool   `json:"constant"`
+	HideInMenu bool   `json:"hide_in_menu"`
+	KeepAlive  bool   `json:"keep_alive"`
+}
+
+type UpdateMenuRequest struct {
+	ParentID   *uint  `json:"parent_id"`
+	Name       string `json:"name" binding:"required"`
+	Path       string `json:"path" binding:"required"`
+	Component  string `json:"component"`
+	Title      string `json:"title" binding:"required"`
+	I18nKey    string `json:"i18n_key"`
+	Icon       string `json:"icon"`
+	Order      int    `json:"order"`
+	Permission string `json:"permission"`
+	Constant   bool   `json:"constant"`
+	HideInMenu bool   `json:"hide_in_menu"`
+	KeepAlive  bool   `json:"keep_alive"`
+	IsActive   *bool  `json:"is_active"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// PageQuery holds the paging query parameters shared by list endpoints
+type PageQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=25" binding:"min=1,max=100"`
+}
+
+// PageResponse wraps one page of a list endpoint
+type PageResponse struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
@@ -0,0 +1,86 @@
+package dtos
+
+import "time"
+
+type PaymentOrderDTO struct {
+	ID            uint                `json:"id"`
+	CustomerID    uint                `json:"customer_id"`
+	CustomerName  string              `json:"customer_name,omitempty"`
+	CrewID        int                 `json:"crew_id"`
+	Status        string              `json:"status"`
+	PlanID        uint                `json:"plan_id"`
+	PlanName      string              `json:"plan_name"`
+	DurationDays  int                 `json:"duration_days"`
+	PriceMinor    int64               `json:"price_minor"`
+	TaxMinor      int64               `json:"tax_minor"`
+	AmountMinor   int64               `json:"amount_minor"`
+	RefundedMinor int64               `json:"refunded_minor"`
+	Currency      string              `json:"currency"`
+	PaidAt        *time.Time          `json:"paid_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Attempts      []PaymentAttemptDTO `json:"attempts,omitempty"`
+	Refunds       []PaymentRefundDTO  `json:"refunds,omitempty"`
+}
+
+type PaymentAttemptDTO struct {
+	ID            uint       `json:"id"`
+	Reference     string     `json:"reference"`
+	Gateway       string     `json:"gateway"`
+	Method        string     `json:"method"`
+	AmountMinor   int64      `json:"amount_minor"`
+	Status        string     `json:"status"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CapturedAt    *time.Time `json:"captured_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type PaymentRefundDTO struct {
+	ID            uint       `json:"id"`
+	Reference     string     `json:"reference"`
+	AmountMinor   int64      `json:"amount_minor"`
+	Status        string     `json:"status"`
+	Reason        string     `json:"reason,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type LedgerEntryDTO struct {
+	TransactionRef string    `json:"transaction_ref"`
+	Account        string    `json:"account"`
+	DebitMinor     int64     `json:"debit_minor"`
+	CreditMinor    int64     `json:"credit_minor"`
+	Currency       string    `json:"currency"`
+	Description    string    `json:"description"`
+	PostedAt       time.Time `json:"posted_at"`
+}
+
+// PaymentOrderDetailDTO is an order with its ledger entries
+type PaymentOrderDetailDTO struct {
+	PaymentOrderDTO
+	Ledger []LedgerEntryDTO `json:"ledger"`
+}
+
+// CreateOrderRequest orders a membership term on a plan, the customer's current
+// one when plan_id is omitted, and pays it with the method
+type CreateOrderRequest struct {
+	PlanID *uint  `json:"plan_id"`
+	Method string `json:"method" binding:"required"`
+}
+
+// PayOrderRequest tries again to pay an order whose payments failed
+type PayOrderRequest struct {
+	Method string `json:"method" binding:"required"`
+}
+
+// RefundOrderRequest refunds a paid order; everything left is refunded when amount_minor is omitted
+type RefundOrderRequest struct {
+	AmountMinor *int64 `json:"amount_minor" binding:"omitempty,min=1"`
+	Reason      string `json:"reason" binding:"max=255"`
+}
+
+// OrderFilter holds the query parameters of order listings
+type OrderFilter struct {
+	PageQuery
+	Status string `form:"status"`
+}
@@ -0,0 +1,82 @@
+package dtos
+
+import "time"
+
+// SettlementStatementDTO is a commission settlement statement of an allie.
+// Amounts are in minor units; the period runs from period_start up to and
+// including period_end.
+type SettlementStatementDTO struct {
+	ID               uint       `json:"id"`
+	AllieID          int        `json:"allie_id"`
+	PeriodStart      string     `json:"period_start"`
+	PeriodEnd        string     `json:"period_end"`
+	Status           string     `json:"status"`
+	CommissionRate   int        `json:"commission_rate"`
+	Currency         string     `json:"currency"`
+	GrossMinor       int64      `json:"gross_minor"`
+	TaxMinor         int64      `json:"tax_minor"`
+	RevenueMinor     int64      `json:"revenue_minor"`
+	CommissionMinor  int64      `json:"commission_minor"`
+	PayoutMinor      int64      `json:"payout_minor"`
+	ApprovedAt       *time.Time `json:"approved_at,omitempty"`
+	PaidAt           *time.Time `json:"paid_at,omitempty"`
+	PaymentReference string     `json:"payment_reference,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// SettlementLineDTO is a captured payment or refund on a statement; refunds are negative
+type SettlementLineDTO struct {
+	CrewID          int       `json:"crew_id"`
+	OrderID         uint      `json:"order_id"`
+	TransactionRef  string    `json:"transaction_ref"`
+	Kind            string    `json:"kind"`
+	PostedAt        time.Time `json:"posted_at"`
+	CommissionRate  int       `json:"commission_rate"`
+	GrossMinor      int64     `json:"gross_minor"`
+	TaxMinor        int64     `json:"tax_minor"`
+	RevenueMinor    int64     `json:"revenue_minor"`
+	CommissionMinor int64     `json:"commission_minor"`
+	PayoutMinor     int64     `json:"payout_minor"`
+}
+
+// SettlementCrewDTO sums up the lines of a statement for one crew
+type SettlementCrewDTO struct {
+	CrewID          int   `json:"crew_id"`
+	Payments        int   `json:"payments"`
+	Refunds         int   `json:"refunds"`
+	GrossMinor      int64 `json:"gross_minor"`
+	TaxMinor        int64 `json:"tax_minor"`
+	RevenueMinor    int64 `json:"revenue_minor"`
+	CommissionMinor int64 `json:"commission_minor"`
+	PayoutMinor     int64 `json:"payout_minor"`
+}
+
+// SettlementStatementDetailDTO is a statement with its per crew summary and lines
+type SettlementStatementDetailDTO struct {
+	SettlementStatementDTO
+	Crews []SettlementCrewDTO `json:"crews"`
+	Lines []SettlementLineDTO `json:"lines"`
+}
+
+// GenerateSettlementRequest names the days, YYYY-MM-DD in Indian time, a statement covers
+type GenerateSettlementRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// MarkSettlementPaidRequest records the transfer an approved statement was paid out with
+type MarkSettlementPaidRequest struct {
+	Reference string `json:"reference" binding:"required,max=100"`
+}
+
+// SettlementFilter holds the query parameters of statement listings
+type SettlementFilter struct {
+	PageQuery
+	Status  string `form:"status"`
+	AllieID *uint  `form:"allie_id"`
+}
+
+// SettlementExportQuery picks the format of a statement export, csv or json
+type SettlementExportQuery struct {
+	Format string `form:"format"`
+}
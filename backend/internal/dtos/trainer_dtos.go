@@ -0,0 +1,82 @@
+package dtos
+
+import "time"
+
+type TrainerCertificationDTO struct {
+	ID           uint       `json:"id"`
+	Name         string     `json:"name"`
+	Issuer       string     `json:"issuer"`
+	CredentialID string     `json:"credential_id"`
+	IssuedOn     *time.Time `json:"issued_on,omitempty"`
+	ExpiresOn    *time.Time `json:"expires_on,omitempty"`
+	Expired      bool       `json:"expired"`
+}
+
+type TrainerDTO struct {
+	ID                uint                      `json:"id"`
+	CrewID            int                       `json:"crew_id"`
+	UserID            *uint                     `json:"user_id,omitempty"`
+	FullName          string                    `json:"full_name"`
+	Email             string                    `json:"email"`
+	Mobile            string                    `json:"mobile"`
+	AlternateMobile   string                    `json:"alternate_mobile"`
+	IsActive          bool                      `json:"is_active"`
+	Visible           bool                      `json:"visible"` // active and at an active crew
+	ExpStartedFrom    time.Time                 `json:"exp_started_from"`
+	YearsOfExperience int                       `json:"years_of_experience"`
+	Specializations   []FitServiceDTO           `json:"specializations"`
+	Certifications    []TrainerCertificationDTO `json:"certifications"`
+}
+
+// TrainerCertificationRequest describes a certification; dates are YYYY-MM-DD
+type TrainerCertificationRequest struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	Issuer       string `json:"issuer" binding:"max=100"`
+	CredentialID string `json:"credential_id" binding:"max=100"`
+	IssuedOn     string `json:"issued_on"`
+	ExpiresOn    string `json:"expires_on"`
+}
+
+// TrainerAccountRequest holds the login of the GYMSTAFF user created for a
+// trainer. The password is temporary and must be changed at first login.
+type TrainerAccountRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// CreateTrainerRequest creates a trainer; exp_started_from is YYYY-MM-DD. A login
+// is either an existing GYMSTAFF user_id or a new account, not both.
+type CreateTrainerRequest struct {
+	FullName          string                        `json:"full_name" binding:"required,max=50"`
+	Email             string                        `json:"email" binding:"omitempty,email"`
+	Mobile            string                        `json:"mobile" binding:"required"`
+	AlternateMobile   string                        `json:"alternate_mobile"`
+	ExpStartedFrom    string                        `json:"exp_started_from" binding:"required"`
+	SpecializationIDs []uint                        `json:"specialization_ids"`
+	Certifications    []TrainerCertificationRequest `json:"certifications" binding:"dive"`
+	UserID            *uint                         `json:"user_id"`
+	Account           *TrainerAccountRequest        `json:"account"`
+}
+
+type UpdateTrainerRequest struct {
+	FullName          string                        `json:"full_name" binding:"required,max=50"`
+	Email             string                        `json:"email" binding:"omitempty,email"`
+	Mobile            string                        `json:"mobile" binding:"required"`
+	AlternateMobile   string                        `json:"alternate_mobile"`
+	ExpStartedFrom    string                        `json:"exp_started_from" binding:"required"`
+	SpecializationIDs []uint                        `json:"specialization_ids"`
+	Certifications    []TrainerCertificationRequest `json:"certifications" binding:"dive"`
+	UserID            *uint                         `json:"user_id"` // links a GYMSTAFF user; 0 unlinks
+	IsActive          *bool                         `json:"is_active"`
+}
+
+// TrainerFilter holds the query parameters of the trainer list
+type TrainerFilter struct {
+	PageQuery
+	Search           string `form:"search"`
+	SpecializationID *uint  `form:"specialization_id"`
+	MinExperience    *int   `form:"min_experience" binding:"omitempty,min=0"`
+	MaxExperience    *int   `form:"max_experience" binding:"omitempty,min=0"`
+	IsActive         *bool  `form:"is_active"`
+	VisibleOnly      bool   `form:"visible_only"`
+}
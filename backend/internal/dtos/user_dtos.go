@@ -1,5 +1,9 @@
 package dtos
 
+import (
+	"time"
+)
+
 type UserDTO struct {
 	ID        int32  `json:"id"`
 	FirstName string `json:"first_name"`
@@ -18,6 +22,7 @@ type CreateUserRequest struct {
 	Email     string `json:"email" binding:"required,email"`
 	Username   string `json:"username" binding:"required"`
 	Password  string `json:"password" binding:"required,min=6"`
+	MustChangePassword bool `json:"must_change_password"` // set when Password is a temporary one
 }
 
 type UpdateUserRequest struct {
@@ -27,3 +32,13 @@ type UpdateUserRequest struct {
 	Mobile    string `json:"mobile" binding:"required"`
 	Password  string `json:"password" binding:"min=6"`
 }
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ImpersonationResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      UserDTO   `json:"user"`
+}
@@ -2,16 +2,27 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"backend/internal/dtos"
 	"backend/internal/middleware"
+	"backend/internal/models"
 	"backend/internal/services"
 	"backend/internal/resources/response"
+	. "backend/internal/resources/constants"
+	"backend/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-	userService services.UserService
+	userService     *services.UserService
+	authService     *services.AuthService
+	passwordService *services.PasswordService
+	otpService      *services.OTPService
+	mfaService      *services.MFAService
 }
 
 type LoginRequest struct {
@@ -19,9 +30,13 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func NewAuthHandler(userService services.UserService) Handler {
+func NewAuthHandler(userService *services.UserService, authService *services.AuthService, passwordService *services.PasswordService, otpService *services.OTPService, mfaService *services.MFAService) Handler {
 	return &AuthHandler{
-		userService: userService,
+		userService:     userService,
+		authService:     authService,
+		passwordService: passwordService,
+		otpService:      otpService,
+		mfaService:      mfaService,
 	}
 }
 
@@ -32,14 +47,31 @@ func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	{
 		authGroup.POST("/login", h.Login)
 		authGroup.POST("/refreshToken", h.RefreshToken)
+		authGroup.GET("/jwks.json", h.JWKS)
+		authGroup.POST("/password/forgot", h.ForgotPassword)
+		authGroup.POST("/password/reset", h.ResetPassword)
+		authGroup.POST("/otp/request", h.RequestOTP)
+		authGroup.POST("/otp/verify", h.VerifyOTP)
+		authGroup.POST("/mfa/verify", h.VerifyMFA)
 	}
 
+	// Routes still reachable while a password change or MFA enrollment is pending
+	pendingAuth := middleware.AuthMiddleware(middleware.AllowPasswordChangePending(), middleware.AllowMFAEnrollmentPending())
+	pending := rg.Group("/auth")
+	pending.Use(pendingAuth, middleware.DenyImpersonation())
+	{
+		pending.POST("/password/change", h.ChangePassword)
+		pending.POST("/mfa/enroll", h.BeginMFAEnrollment)
+		pending.POST("/mfa/enroll/confirm", h.ConfirmMFAEnrollment)
+	}
+	rg.POST("/logout", pendingAuth, h.Logout)
+
 	// Protected routes (after login)
 	authenticated := rg.Group("/")
 	authenticated.Use(middleware.AuthMiddleware())
 	{
 		authenticated.GET("/getUserInfo", h.GetUserInfo)
-		authenticated.POST("/logout", h.Logout)
+		authenticated.POST("/logoutAll", middleware.DenyImpersonation(), h.LogoutAll)
 	}
 }
 
@@ -51,52 +83,59 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Replace with actual authentication logic
-	if req.Username != "Pranshu" || req.Password != "123456" {
-		response.BadRequestError(c, "invalid credentials")
-		return
-	}
-
-	// Generate tokens
-	accessToken, refreshToken, err := middleware.GenerateTokens(req.Username)
+	user, err := h.authService.Login(c, req.Username, req.Password, c.ClientIP())
 	if err != nil {
-		response.InternalServerError(c, err)
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			response.SendErrorResponse(c, response.STATUS_TOO_MANY_REQUESTS, err.Error(), err.Error())
+		case errors.Is(err, services.ErrInvalidCredentials):
+			response.SendErrorResponse(c, response.STATUS_UNAUTHORIZED, INVALID_CREDENTIALS, err.Error())
+		case errors.Is(err, services.ErrAccountInactive):
+			response.SendErrorResponse(c, response.STATUS_FORBIDDEN, ACCOUNT_INACTIVE, err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": "0000",
-		"msg":  "success",
-		"data": gin.H{
-			"token":        accessToken,
-			"refreshToken": refreshToken,
-		},
-	})
+	h.completeLogin(c, user)
 }
 
-// GetUserInfo returns user information for authenticated users
+// GetUserInfo returns the signed-in user with their role and the permission
+// codes the admin panel uses to show buttons
 func (h *AuthHandler) GetUserInfo(c *gin.Context) {
-	// Extract username from context (set by AuthMiddleware)
-	username, exists := c.Get("user")
-	if !exists {
-		response.BadRequestError(c, "User not found")
-		// response.UnauthorizedError(c)
+	user, err := h.userService.GetUserByID(c, int32(middleware.CurrentUserID(c)))
+	if err != nil {
+		response.NotFoundError(c, USER_NOT_FOUND)
 		return
 	}
 
+	permissions := user.UserType.Permissions()
+	buttons := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		buttons = append(buttons, string(permission))
+	}
+
+	data := gin.H{
+		"userId":   strconv.FormatUint(uint64(user.ID), 10),
+		"userName": user.Username,
+		"roles":    []string{user.UserType.String()},
+		"buttons":  buttons,
+	}
+	// Let the admin panel show who is really signed in
+	if middleware.IsImpersonating(c) {
+		data["impersonatedBy"] = gin.H{
+			"userId":   strconv.FormatUint(uint64(middleware.CurrentActorID(c)), 10),
+			"userName": middleware.CurrentActorName(c),
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": "0000",
 		"msg":  "success",
-		"data": gin.H{
-			"userId":   "0",
-			"userName": username,
-			"roles":    []string{"R_SUPER"},
-			"buttons": []string{
-				"B_CODE1",
-				"B_CODE2",
-				"B_CODE3",
-			},
-		},
+		"data": data,
 	})
 }
 
@@ -112,39 +151,347 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Validate the refresh token
-	claims, err := middleware.ValidateRefreshToken(refreshRequest.RefreshToken)
+	tokens, err := h.authService.RefreshTokens(c, refreshRequest.RefreshToken)
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			response.SendErrorResponse(c, response.STATUS_UNAUTHORIZED, err.Error(), err.Error())
+		case errors.Is(err, services.ErrAccountInactive):
+			response.SendErrorResponse(c, response.STATUS_FORBIDDEN, ACCOUNT_INACTIVE, err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	sendTokenPair(c, tokens)
+}
+
+// Logout revokes the session the caller's access token belongs to.
+// The access token itself stays valid until it expires.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(c, middleware.CurrentSessionID(c)); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  LOGOUT_SUCCESSFUL,
+	})
+}
+
+// LogoutAll revokes every session of the caller on every device
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.authService.LogoutAll(c, middleware.CurrentUserID(c)); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  LOGOUT_SUCCESSFUL,
+	})
+}
+
+// ForgotPassword sends a reset token to the account matching the identifier
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dtos.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		response.BadRequestError(c, err.Error())
 		return
 	}
 
-	// Extract username from claims
-	username := claims.Data[0]["userName"]
+	if err := h.passwordService.RequestReset(c, req.Identifier); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  PASSWORD_RESET_REQUESTED,
+	})
+}
+
+// ResetPassword sets a new password using a reset token
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dtos.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(c, req.Token, req.Password); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			response.BadRequestError(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  PASSWORD_RESET_SUCCESSFUL,
+	})
+}
+
+// ChangePassword replaces the caller's password and starts a fresh session,
+// revoking every other session of the user
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req dtos.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := h.passwordService.ChangePassword(c, middleware.CurrentUserID(c), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIncorrectPassword), errors.Is(err, services.ErrPasswordUnchanged):
+			response.BadRequestError(c, err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
+		return
+	}
 
-	// Generate new access and refresh tokens
-	accessToken, refreshToken, err := middleware.GenerateTokens(username)
+	if err := h.authService.LogoutAll(c, user.ID); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+	tokens, err := h.authService.IssueTokens(c, user, deviceID(c))
 	if err != nil {
 		response.InternalServerError(c, err)
 		return
 	}
 
+	sendTokenPair(c, tokens)
+}
+
+// RequestOTP texts a one-time login code to a customer's mobile number
+func (h *AuthHandler) RequestOTP(c *gin.Context) {
+	var req dtos.OTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.otpService.RequestCode(c, req.Mobile); err != nil {
+		var resend *services.OTPResendError
+		if errors.As(err, &resend) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(resend.RetryAfter.Seconds()))))
+			response.SendErrorResponse(c, response.STATUS_TOO_MANY_REQUESTS, err.Error(), err.Error())
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  OTP_SENT,
+	})
+}
+
+// VerifyOTP exchanges a valid login code for the same token pair Login issues
+func (h *AuthHandler) VerifyOTP(c *gin.Context) {
+	var req dtos.OTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := h.otpService.VerifyCode(c, req.Mobile, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidOTP):
+			response.SendErrorResponse(c, response.STATUS_UNAUTHORIZED, err.Error(), err.Error())
+		case errors.Is(err, services.ErrAccountInactive):
+			response.SendErrorResponse(c, response.STATUS_FORBIDDEN, ACCOUNT_INACTIVE, err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// VerifyMFA completes a login that stopped at the second factor
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dtos.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		response.BadRequestError(c, MFA_REQUIRED)
+		return
+	}
+
+	user, err := h.mfaService.VerifyLogin(c, req.MfaToken, req.Code, req.RecoveryCode, c.ClientIP())
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			response.SendErrorResponse(c, response.STATUS_TOO_MANY_REQUESTS, err.Error(), err.Error())
+		case errors.Is(err, services.ErrInvalidMFACode), errors.Is(err, services.ErrInvalidMFAPending), errors.Is(err, services.ErrMFANotEnrolled):
+			response.SendErrorResponse(c, response.STATUS_UNAUTHORIZED, err.Error(), err.Error())
+		case errors.Is(err, services.ErrAccountInactive):
+			response.SendErrorResponse(c, response.STATUS_FORBIDDEN, ACCOUNT_INACTIVE, err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	tokens, err := h.authService.IssueTokens(c, user, deviceID(c))
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	sendTokenPair(c, tokens)
+}
+
+// BeginMFAEnrollment creates a TOTP secret for the caller to add to an authenticator app
+func (h *AuthHandler) BeginMFAEnrollment(c *gin.Context) {
+	enrollment, err := h.mfaService.BeginEnrollment(c, middleware.CurrentUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+			response.SendErrorResponse(c, response.STATUS_CONFLICT, err.Error(), err.Error())
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": "0000",
 		"msg":  "success",
 		"data": gin.H{
-			"token":        accessToken,
-			"refreshToken": refreshToken,
+			"secret":          enrollment.Secret,
+			"provisioningUri": enrollment.ProvisioningURI,
 		},
 	})
 }
 
-// Logout handles user logout
-func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a stateless JWT system, logout is typically handled client-side
-	// You might want to implement token blacklisting or other logout mechanisms
+// ConfirmMFAEnrollment enables MFA with a first code from the authenticator and
+// returns the recovery codes with a session no longer limited to enrollment
+func (h *AuthHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	var req dtos.MFAConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequestError(c, err.Error())
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	recoveryCodes, err := h.mfaService.ConfirmEnrollment(c, userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMFACode), errors.Is(err, services.ErrMFANotEnrolled):
+			response.BadRequestError(c, err.Error())
+		case errors.Is(err, services.ErrMFAAlreadyEnabled):
+			response.SendErrorResponse(c, response.STATUS_CONFLICT, err.Error(), err.Error())
+		default:
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c, int32(userID))
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+	if err := h.authService.Logout(c, middleware.CurrentSessionID(c)); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+	tokens, err := h.authService.IssueTokens(c, user, deviceID(c))
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  MFA_ENABLED,
+		"data": gin.H{
+			"recoveryCodes":      recoveryCodes,
+			"token":              tokens.AccessToken,
+			"refreshToken":       tokens.RefreshToken,
+			"mustChangePassword": tokens.MustChangePassword,
+		},
+	})
+}
+
+// JWKS publishes the public keys other services use to verify our tokens
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, jwt.JWKS())
+}
+
+// deviceID identifies the client device a session is bound to
+func deviceID(c *gin.Context) string {
+	if id := c.GetHeader("X-Device-ID"); id != "" {
+		return id
+	}
+	return "default"
+}
+
+// completeLogin issues a session once the first factor succeeded, or asks for
+// a TOTP code when the user has MFA enabled
+func (h *AuthHandler) completeLogin(c *gin.Context, user *models.User) {
+	enabled, err := h.mfaService.IsEnabled(c, user.ID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	if enabled {
+		mfaToken, err := h.mfaService.IssuePendingToken(c, user)
+		if err != nil {
+			response.InternalServerError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code": "0000",
+			"msg":  MFA_REQUIRED,
+			"data": gin.H{
+				"mfaRequired": true,
+				"mfaToken":    mfaToken,
+			},
+		})
+		return
+	}
+
+	tokens, err := h.authService.IssueTokens(c, user, deviceID(c))
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	sendTokenPair(c, tokens)
+}
+
+func sendTokenPair(c *gin.Context, tokens *services.TokenPair) {
+	msg := "success"
+	if tokens.MustChangePassword {
+		msg = PASSWORD_CHANGE_REQUIRED
+	} else if tokens.MFAEnrollmentRequired {
+		msg = MFA_ENROLLMENT_REQUIRED
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": "0000",
-		"msg":  "logout successful",
+		"msg":  msg,
+		"data": gin.H{
+			"token":                 tokens.AccessToken,
+			"refreshToken":          tokens.RefreshToken,
+			"mustChangePassword":    tokens.MustChangePassword,
+			"mfaEnrollmentRequired": tokens.MFAEnrollmentRequired,
+		},
 	})
 }
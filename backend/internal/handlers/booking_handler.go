@@ -0,0 +1,292 @@
+// internal/handlers/booking_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type BookingHandler struct {
+	service *services.BookingService
+}
+
+func NewBookingHandler(bookingService *services.BookingService) *BookingHandler {
+	return &BookingHandler{service: bookingService}
+}
+
+// RegisterRoutes sets up routes for class bookings made by crew staff and by
+// customers for themselves, and for the booking policy of an allie.
+func (h *BookingHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	bookings := rg.Group("/crews/:id/sessions/:sessionId/bookings")
+	bookings.Use(middleware.AuthMiddleware())
+	{
+		bookings.GET("", middleware.RequirePermission(PERM_BOOKING_READ), h.ListSessionBookings)
+		bookings.POST("", middleware.RequirePermission(PERM_BOOKING_MANAGE), h.BookForCustomer)
+		bookings.POST("/:bookingId/cancel", middleware.RequirePermission(PERM_BOOKING_MANAGE), h.CancelBooking)
+		bookings.POST("/:bookingId/attendance", middleware.RequirePermission(PERM_BOOKING_MANAGE), h.MarkAttendance)
+	}
+
+	policy := rg.Group("/allies/:id/booking-policy")
+	policy.Use(middleware.AuthMiddleware())
+	{
+		policy.GET("", middleware.RequirePermission(PERM_BOOKING_READ), h.GetPolicy)
+		policy.PUT("", middleware.RequirePermission(PERM_BOOKING_MANAGE), h.UpdatePolicy)
+	}
+
+	me := rg.Group("/me")
+	me.Use(middleware.AuthMiddleware(), middleware.RequirePermission(PERM_BOOKING_SELF))
+	{
+		me.GET("/bookings", h.ListOwnBookings)
+		me.POST("/bookings/:bookingId/cancel", h.CancelOwnBooking)
+		me.GET("/crews/:id/sessions", h.ListBookableSessions)
+		me.POST("/sessions/:sessionId/book", h.BookForSelf)
+	}
+}
+
+// ListSessionBookings handles retrieving the bookings and waitlist of a session.
+func (h *BookingHandler) ListSessionBookings(c *gin.Context) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.service.ListSessionBookings(c, requester(c), crewID, sessionID)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToClassBookingDTOs(bookings))
+}
+
+// BookForCustomer handles booking a session for a customer of the crew.
+func (h *BookingHandler) BookForCustomer(c *gin.Context) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.BookClassRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	booking, err := h.service.BookForCustomer(c, requester(c), crewID, sessionID, input)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	sendBooked(c, booking)
+}
+
+// CancelBooking handles cancelling a booking of a session on a customer's behalf.
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	crewID, sessionID, bookingID, ok := bookingParams(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.service.CancelBooking(c, requester(c), crewID, sessionID, bookingID)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CLASS_BOOKING_CANCELLED, mappers.ToClassBookingDTO(booking))
+}
+
+// MarkAttendance handles recording whether a booked customer came to a session.
+func (h *BookingHandler) MarkAttendance(c *gin.Context) {
+	crewID, sessionID, bookingID, ok := bookingParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.MarkAttendanceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	booking, err := h.service.MarkAttendance(c, requester(c), crewID, sessionID, bookingID, input)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, BOOKING_UPDATED, mappers.ToClassBookingDTO(booking))
+}
+
+// GetPolicy handles retrieving the booking policy of an allie.
+func (h *BookingHandler) GetPolicy(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	policy, err := h.service.GetPolicy(c, requester(c), uint(allieID))
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToBookingPolicyDTO(policy))
+}
+
+// UpdatePolicy handles setting the booking policy of an allie.
+func (h *BookingHandler) UpdatePolicy(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.UpdateBookingPolicyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(c, requester(c), uint(allieID), input)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, BOOKING_POLICY_UPDATED, mappers.ToBookingPolicyDTO(policy))
+}
+
+// ListOwnBookings handles retrieving a page of the signed-in customer's bookings.
+func (h *BookingHandler) ListOwnBookings(c *gin.Context) {
+	var filter dtos.MyBookingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	bookings, total, err := h.service.ListOwnBookings(c, requester(c), filter)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToMyBookingDTOs(bookings),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// CancelOwnBooking handles the signed-in customer cancelling one of their bookings.
+func (h *BookingHandler) CancelOwnBooking(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("bookingId"))
+	if err != nil {
+		BadRequestError(c, INVALID_BOOKING_INPUT)
+		return
+	}
+
+	booking, err := h.service.CancelOwnBooking(c, requester(c), uint(bookingID))
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CLASS_BOOKING_CANCELLED, mappers.ToClassBookingDTO(booking))
+}
+
+// ListBookableSessions handles retrieving the sessions the signed-in customer can book at a crew.
+func (h *BookingHandler) ListBookableSessions(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.BookableSessionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	sessions, occupancy, total, err := h.service.ListBookableSessions(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToBookableSessionDTOs(sessions, occupancy),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// BookForSelf handles the signed-in customer booking a session.
+func (h *BookingHandler) BookForSelf(c *gin.Context) {
+	sessionID, err := strconv.Atoi(c.Param("sessionId"))
+	if err != nil {
+		BadRequestError(c, INVALID_CLASS_INPUT)
+		return
+	}
+
+	booking, err := h.service.BookForSelf(c, requester(c), uint(sessionID))
+	if err != nil {
+		sendBookingError(c, err)
+		return
+	}
+
+	sendBooked(c, booking)
+}
+
+// sendBooked responds with a new booking, telling a spot from a waitlist place
+func sendBooked(c *gin.Context, booking *models.ClassBooking) {
+	message := CLASS_BOOKED
+	if booking.Status == BOOKING_WAITLISTED {
+		message = CLASS_WAITLISTED
+	}
+	SendSuccessResponse(c, message, mappers.ToClassBookingDTO(booking))
+}
+
+// bookingParams parses the crew, session and booking IDs of a booking route, responding on failure
+func bookingParams(c *gin.Context) (uint, uint, uint, bool) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	bookingID, err := strconv.Atoi(c.Param("bookingId"))
+	if err != nil {
+		BadRequestError(c, INVALID_BOOKING_INPUT)
+		return 0, 0, 0, false
+	}
+	return crewID, sessionID, uint(bookingID), true
+}
+
+func sendBookingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrSessionNotFound),
+		errors.Is(err, services.ErrCustomerNotFound), errors.Is(err, services.ErrBookingNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied), errors.Is(err, services.ErrBookingNotAllowed):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidBooking):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrAlreadyBooked), errors.Is(err, services.ErrBookingNotActive), errors.Is(err, services.ErrSessionNotScheduled):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,229 @@
+// internal/handlers/check_in_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"backend/pkg/qrcode"
+	"github.com/gin-gonic/gin"
+)
+
+// checkInCodeScale is the size in pixels of one module of a check-in QR image
+const checkInCodeScale = 8
+
+type CheckInHandler struct {
+	service *services.CheckInService
+}
+
+func NewCheckInHandler(checkInService *services.CheckInService) *CheckInHandler {
+	return &CheckInHandler{service: checkInService}
+}
+
+// RegisterRoutes sets up routes for the front desk of a crew: check-in,
+// check-out, live occupancy and the attendance history of its customers,
+// and for the QR codes customers scan at its kiosks.
+func (h *CheckInHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	desk := rg.Group("/crews/:id")
+	desk.Use(middleware.AuthMiddleware())
+	{
+		desk.POST("/check-ins", middleware.RequirePermission(PERM_CHECKIN_MANAGE), h.CheckIn)
+		desk.POST("/check-ins/scan", middleware.RequirePermission(PERM_CHECKIN_MANAGE), h.ScanCode)
+		desk.POST("/check-outs", middleware.RequirePermission(PERM_CHECKIN_MANAGE), h.CheckOut)
+		desk.GET("/occupancy", middleware.RequirePermission(PERM_CHECKIN_READ), h.Occupancy)
+		desk.GET("/customers/:customerId/visits", middleware.RequirePermission(PERM_CHECKIN_READ), h.CustomerVisits)
+	}
+
+	me := rg.Group("/me")
+	me.Use(middleware.AuthMiddleware(), middleware.RequirePermission(PERM_CHECKIN_SELF))
+	{
+		me.GET("/check-in-code", h.IssueCode)
+	}
+}
+
+// CheckIn handles letting a customer into a crew.
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CheckInRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	visit, err := h.service.CheckIn(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CHECKED_IN, mappers.ToCrewVisitDTO(visit))
+}
+
+// ScanCode handles a kiosk checking in the customer whose QR code it scanned.
+func (h *CheckInHandler) ScanCode(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.ScanCheckInCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	visit, err := h.service.ScanCode(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CHECKED_IN, mappers.ToCrewVisitDTO(visit))
+}
+
+// IssueCode handles issuing the signed-in customer a check-in code, as JSON or
+// rendered as a PNG or SVG QR image.
+func (h *CheckInHandler) IssueCode(c *gin.Context) {
+	var query dtos.CheckInCodeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(query.Format))
+	if format != "" && format != "json" && format != "png" && format != "svg" {
+		BadRequestError(c, INVALID_CHECKIN_INPUT)
+		return
+	}
+
+	code, expiresAt, err := h.service.IssueCode(c, requester(c))
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	// every code is single-use, so none may be served from a cache
+	c.Header("Cache-Control", "no-store")
+	if format == "" || format == "json" {
+		SendSuccessResponse(c, SUCCESS, dtos.CheckInCodeDTO{
+			Code:      code,
+			ExpiresAt: expiresAt,
+			ExpiresIn: int(time.Until(expiresAt).Round(time.Second).Seconds()),
+		})
+		return
+	}
+
+	qr, err := qrcode.Encode([]byte(code), qrcode.M)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+	c.Header("X-Code-Expires-At", expiresAt.UTC().Format(time.RFC3339))
+	if format == "svg" {
+		c.Data(http.StatusOK, "image/svg+xml", []byte(qr.SVG(checkInCodeScale)))
+		return
+	}
+	body, err := qr.PNG(checkInCodeScale)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", body)
+}
+
+// CheckOut handles a customer leaving a crew.
+func (h *CheckInHandler) CheckOut(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CheckOutRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	visit, err := h.service.CheckOut(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CHECKED_OUT, mappers.ToCrewVisitDTO(visit))
+}
+
+// Occupancy handles retrieving who is inside a crew right now.
+func (h *CheckInHandler) Occupancy(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	crew, visits, err := h.service.Occupancy(c, requester(c), uint(crewID))
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToCrewOccupancyDTO(crew, visits))
+}
+
+// CustomerVisits handles retrieving a page of a customer's attendance history.
+func (h *CheckInHandler) CustomerVisits(c *gin.Context) {
+	crewID, customerID, ok := customerParams(c)
+	if !ok {
+		return
+	}
+
+	var filter dtos.VisitHistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	visits, total, err := h.service.CustomerVisits(c, requester(c), crewID, customerID, filter)
+	if err != nil {
+		sendCheckInError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToCrewVisitDTOs(visits),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+func sendCheckInError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrCustomerNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied), errors.Is(err, services.ErrEntryRefused):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidCheckIn), errors.Is(err, services.ErrInvalidCheckInCode):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrCrewAtCapacity), errors.Is(err, services.ErrAlreadyCheckedIn), errors.Is(err, services.ErrNotCheckedIn),
+		errors.Is(err, services.ErrCheckInCodeUsed):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
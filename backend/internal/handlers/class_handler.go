@@ -0,0 +1,281 @@
+// internal/handlers/class_handler.go
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ClassHandler struct {
+	service *services.ClassService
+}
+
+func NewClassHandler(classService *services.ClassService) *ClassHandler {
+	return &ClassHandler{service: classService}
+}
+
+// RegisterRoutes sets up routes for the weekly classes of a crew and their sessions.
+func (h *ClassHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	classes := rg.Group("/crews/:id/classes")
+	classes.Use(middleware.AuthMiddleware())
+	{
+		classes.POST("", middleware.RequirePermission(PERM_CLASS_MANAGE), h.CreateClass)
+		classes.GET("", middleware.RequirePermission(PERM_CLASS_READ), h.ListClasses)
+		classes.GET("/:classId", middleware.RequirePermission(PERM_CLASS_READ), h.GetClass)
+		classes.PUT("/:classId", middleware.RequirePermission(PERM_CLASS_MANAGE), h.UpdateClass)
+		classes.DELETE("/:classId", middleware.RequirePermission(PERM_CLASS_MANAGE), h.DeleteClass)
+		classes.POST("/:classId/generate", middleware.RequirePermission(PERM_CLASS_MANAGE), h.GenerateSessions)
+	}
+
+	sessions := rg.Group("/crews/:id/sessions")
+	sessions.Use(middleware.AuthMiddleware())
+	{
+		sessions.GET("", middleware.RequirePermission(PERM_CLASS_READ), h.ListSessions)
+		sessions.GET("/:sessionId", middleware.RequirePermission(PERM_CLASS_READ), h.GetSession)
+		sessions.PUT("/:sessionId", middleware.RequirePermission(PERM_CLASS_MANAGE), h.UpdateSession)
+		sessions.POST("/:sessionId/cancel", middleware.RequirePermission(PERM_CLASS_MANAGE), h.CancelSession)
+	}
+}
+
+// CreateClass handles adding a weekly class to a crew.
+func (h *ClassHandler) CreateClass(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CreateClassTemplateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	template, err := h.service.CreateTemplate(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CLASS_CREATED, mappers.ToClassTemplateDTO(template))
+}
+
+// ListClasses handles retrieving the weekly classes of a crew.
+func (h *ClassHandler) ListClasses(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	templates, err := h.service.ListTemplates(c, requester(c), uint(crewID))
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToClassTemplateDTOs(templates))
+}
+
+// GetClass handles retrieving a weekly class by ID.
+func (h *ClassHandler) GetClass(c *gin.Context) {
+	crewID, classID, ok := classParams(c, "classId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	template, err := h.service.GetTemplate(c, requester(c), crewID, classID)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToClassTemplateDTO(template))
+}
+
+// UpdateClass handles changing a weekly class and its upcoming sessions.
+func (h *ClassHandler) UpdateClass(c *gin.Context) {
+	crewID, classID, ok := classParams(c, "classId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateClassTemplateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(c, requester(c), crewID, classID, input)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CLASS_UPDATED, mappers.ToClassTemplateDTO(template))
+}
+
+// DeleteClass handles removing a weekly class and its upcoming sessions.
+func (h *ClassHandler) DeleteClass(c *gin.Context) {
+	crewID, classID, ok := classParams(c, "classId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c, requester(c), crewID, classID); err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CLASS_DELETED, nil)
+}
+
+// GenerateSessions handles generating the sessions of a class further ahead.
+func (h *ClassHandler) GenerateSessions(c *gin.Context) {
+	crewID, classID, ok := classParams(c, "classId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.GenerateSessionsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	generated, err := h.service.GenerateSessions(c, requester(c), crewID, classID, input)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SESSIONS_GENERATED, dtos.GenerateSessionsDTO{Generated: generated})
+}
+
+// ListSessions handles retrieving a page of a crew's sessions between two dates.
+func (h *ClassHandler) ListSessions(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.ClassSessionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	sessions, total, err := h.service.ListSessions(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToClassSessionDTOs(sessions),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetSession handles retrieving a session by ID.
+func (h *ClassHandler) GetSession(c *gin.Context) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	session, err := h.service.GetSession(c, requester(c), crewID, sessionID)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToClassSessionDTO(session))
+}
+
+// UpdateSession handles editing a single session.
+func (h *ClassHandler) UpdateSession(c *gin.Context) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateClassSessionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	session, err := h.service.UpdateSession(c, requester(c), crewID, sessionID, input)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SESSION_UPDATED, mappers.ToClassSessionDTO(session))
+}
+
+// CancelSession handles cancelling a single session.
+func (h *ClassHandler) CancelSession(c *gin.Context) {
+	crewID, sessionID, ok := classParams(c, "sessionId", INVALID_CLASS_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.CancelClassSessionRequest
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	session, err := h.service.CancelSession(c, requester(c), crewID, sessionID, input)
+	if err != nil {
+		sendClassError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SESSION_CANCELLED, mappers.ToClassSessionDTO(session))
+}
+
+// classParams parses the crew ID and the named class or session ID of a route, responding on failure
+func classParams(c *gin.Context, param, invalid string) (uint, uint, bool) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		BadRequestError(c, invalid)
+		return 0, 0, false
+	}
+	return uint(crewID), uint(id), true
+}
+
+func sendClassError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrClassNotFound),
+		errors.Is(err, services.ErrSessionNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidClass):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrTrainerDoubleBooked), errors.Is(err, services.ErrSessionNotScheduled):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,164 @@
+// internal/handlers/crew_schedule_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CrewScheduleHandler struct {
+	service *services.CrewScheduleService
+}
+
+func NewCrewScheduleHandler(scheduleService *services.CrewScheduleService) *CrewScheduleHandler {
+	return &CrewScheduleHandler{service: scheduleService}
+}
+
+// RegisterRoutes sets up routes for the opening hours and holidays of a crew.
+// Reading the schedule is public so customers can see when a gym is open.
+func (h *CrewScheduleHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	crew := rg.Group("/crews/:id")
+	{
+		crew.GET("/schedule", h.GetSchedule)
+		crew.GET("/open", h.IsOpen)
+		crew.PUT("/schedule/hours", middleware.AuthMiddleware(), middleware.RequirePermission(PERM_CREW_UPDATE), h.SetOpeningHours)
+		crew.POST("/schedule/holidays", middleware.AuthMiddleware(), middleware.RequirePermission(PERM_CREW_UPDATE), h.AddHoliday)
+		crew.DELETE("/schedule/holidays/:holidayId", middleware.AuthMiddleware(), middleware.RequirePermission(PERM_CREW_UPDATE), h.RemoveHoliday)
+	}
+}
+
+// GetSchedule handles retrieving the weekly hours and upcoming holidays of a crew.
+func (h *CrewScheduleHandler) GetSchedule(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	crew, err := h.service.GetSchedule(uint(crewID))
+	if err != nil {
+		sendScheduleError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToCrewScheduleDTO(crew, time.Now()))
+}
+
+// IsOpen handles checking whether a crew is open now or at a given time.
+func (h *CrewScheduleHandler) IsOpen(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var query dtos.CrewOpenQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+	at := time.Now()
+	if query.At != "" {
+		if at, err = time.Parse(time.RFC3339, query.At); err != nil {
+			BadRequestError(c, INVALID_SCHEDULE_INPUT)
+			return
+		}
+	}
+
+	open, err := h.service.IsOpen(uint(crewID), at)
+	if err != nil {
+		sendScheduleError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.CrewOpenDTO{CrewID: uint(crewID), At: at, IsOpen: open})
+}
+
+// SetOpeningHours handles replacing the weekly hours of a crew.
+func (h *CrewScheduleHandler) SetOpeningHours(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.SetOpeningHoursRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	crew, err := h.service.SetOpeningHours(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendScheduleError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SCHEDULE_UPDATED, mappers.ToCrewScheduleDTO(crew, time.Now()))
+}
+
+// AddHoliday handles closing a crew, or giving it special hours, over some dates.
+func (h *CrewScheduleHandler) AddHoliday(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CreateCrewHolidayRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	holiday, err := h.service.AddHoliday(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendScheduleError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, HOLIDAY_ADDED, mappers.ToCrewHolidayDTO(holiday))
+}
+
+// RemoveHoliday handles deleting a holiday of a crew.
+func (h *CrewScheduleHandler) RemoveHoliday(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+	holidayID, err := strconv.Atoi(c.Param("holidayId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SCHEDULE_INPUT)
+		return
+	}
+
+	if err := h.service.RemoveHoliday(c, requester(c), uint(crewID), uint(holidayID)); err != nil {
+		sendScheduleError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, HOLIDAY_REMOVED, nil)
+}
+
+func sendScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrHolidayNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidSchedule):
+		BadRequestError(c, err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,144 @@
+// internal/handlers/fit_allie_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FitAllieHandler struct {
+	service *services.FitAllieService
+}
+
+func NewFitAllieHandler(allieService *services.FitAllieService) *FitAllieHandler {
+	return &FitAllieHandler{service: allieService}
+}
+
+// RegisterRoutes sets up routes for gym partner management.
+func (h *FitAllieHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	allies := rg.Group("/allies")
+	allies.Use(middleware.AuthMiddleware())
+	{
+		allies.POST("", middleware.RequirePermission(PERM_ALLIE_CREATE), h.CreateAllie)
+		allies.GET("", middleware.RequirePermission(PERM_ALLIE_READ), h.ListAllies)
+		allies.GET("/:id", middleware.RequirePermission(PERM_ALLIE_READ), h.GetAllie)
+		allies.PUT("/:id", middleware.RequirePermission(PERM_ALLIE_UPDATE), h.UpdateAllie)
+		allies.DELETE("/:id", middleware.RequirePermission(PERM_ALLIE_DELETE), h.DeleteAllie)
+	}
+}
+
+// CreateAllie handles onboarding a gym partner, optionally with its owner login.
+func (h *FitAllieHandler) CreateAllie(c *gin.Context) {
+	var input dtos.CreateFitAllieRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	allie, err := h.service.CreateAllie(c, input, middleware.CurrentUserID(c))
+	if err != nil {
+		sendAllieError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, ALLIE_CREATED, mappers.ToFitAllieDTO(allie))
+}
+
+// GetAllie handles retrieving an allie by ID.
+func (h *FitAllieHandler) GetAllie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	allie, err := h.service.GetAllie(c, uint(id))
+	if err != nil {
+		sendAllieError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToFitAllieDTO(allie))
+}
+
+// UpdateAllie handles updating an allie by ID.
+func (h *FitAllieHandler) UpdateAllie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.UpdateFitAllieRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	allie, err := h.service.UpdateAllie(c, uint(id), input, middleware.CurrentUserID(c))
+	if err != nil {
+		sendAllieError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, ALLIE_UPDATED, mappers.ToFitAllieDTO(allie))
+}
+
+// DeleteAllie handles deleting an allie by ID.
+func (h *FitAllieHandler) DeleteAllie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	if err := h.service.DeleteAllie(c, uint(id)); err != nil {
+		sendAllieError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, ALLIE_DELETED, nil)
+}
+
+// ListAllies handles retrieving a filtered page of allies.
+func (h *FitAllieHandler) ListAllies(c *gin.Context) {
+	var filter dtos.FitAllieFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	allies, total, err := h.service.ListAllies(c, filter)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToFitAllieDTOs(allies),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+func sendAllieError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrInvalidAllie):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrUserAlreadyRegistered):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,215 @@
+// internal/handlers/fit_crew_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FitCrewHandler struct {
+	service *services.FitCrewService
+}
+
+func NewFitCrewHandler(crewService *services.FitCrewService) *FitCrewHandler {
+	return &FitCrewHandler{service: crewService}
+}
+
+// RegisterRoutes sets up routes for the branches of an allie.
+func (h *FitCrewHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	crews := rg.Group("/allies/:id/crews")
+	crews.Use(middleware.AuthMiddleware())
+	{
+		crews.POST("", middleware.RequirePermission(PERM_CREW_CREATE), h.CreateCrew)
+		crews.GET("", middleware.RequirePermission(PERM_CREW_READ), h.ListCrews)
+		crews.GET("/:crewId", middleware.RequirePermission(PERM_CREW_READ), h.GetCrew)
+		crews.PUT("/:crewId", middleware.RequirePermission(PERM_CREW_UPDATE), h.UpdateCrew)
+		crews.DELETE("/:crewId", middleware.RequirePermission(PERM_CREW_DELETE), h.DeleteCrew)
+	}
+
+	// The gym directory is public so customers can look for gyms before signing up
+	rg.GET("/crews", h.SearchCrews)
+	rg.GET("/crews/nearby", h.NearbyCrews)
+}
+
+// CreateCrew handles opening a branch for an allie.
+func (h *FitCrewHandler) CreateCrew(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.CreateFitCrewRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	crew, err := h.service.CreateCrew(c, requester(c), uint(allieID), input)
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CREW_CREATED, mappers.ToFitCrewDTO(crew))
+}
+
+// ListCrews handles retrieving every branch of an allie.
+func (h *FitCrewHandler) ListCrews(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	crews, err := h.service.ListCrews(c, requester(c), uint(allieID))
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToFitCrewDTOs(crews))
+}
+
+// GetCrew handles retrieving a branch by ID.
+func (h *FitCrewHandler) GetCrew(c *gin.Context) {
+	allieID, crewID, ok := crewParams(c)
+	if !ok {
+		return
+	}
+
+	crew, err := h.service.GetCrew(c, requester(c), allieID, crewID)
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToFitCrewDTO(crew))
+}
+
+// UpdateCrew handles updating a branch by ID.
+func (h *FitCrewHandler) UpdateCrew(c *gin.Context) {
+	allieID, crewID, ok := crewParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateFitCrewRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	crew, err := h.service.UpdateCrew(c, requester(c), allieID, crewID, input)
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CREW_UPDATED, mappers.ToFitCrewDTO(crew))
+}
+
+// DeleteCrew handles deleting a branch by ID.
+func (h *FitCrewHandler) DeleteCrew(c *gin.Context) {
+	allieID, crewID, ok := crewParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCrew(c, requester(c), allieID, crewID); err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CREW_DELETED, nil)
+}
+
+// SearchCrews handles the public gym search, optionally filtered by an offered service.
+func (h *FitCrewHandler) SearchCrews(c *gin.Context) {
+	var filter dtos.FitCrewSearchFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	crews, total, err := h.service.SearchCrews(filter)
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToPublicFitCrewDTOs(crews),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// NearbyCrews handles the public search for gyms around a point, nearest first.
+func (h *FitCrewHandler) NearbyCrews(c *gin.Context) {
+	var filter dtos.NearbyFitCrewFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	crews, total, err := h.service.NearbyCrews(filter)
+	if err != nil {
+		sendCrewError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToNearbyFitCrewDTOs(crews),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// requester identifies the signed-in user for services that scope data by owner
+func requester(c *gin.Context) services.Requester {
+	return services.Requester{
+		UserID: middleware.CurrentUserID(c),
+		Role:   middleware.CurrentRole(c),
+	}
+}
+
+// crewParams parses the allie and crew IDs of a crew route, responding on failure
+func crewParams(c *gin.Context) (uint, uint, bool) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return 0, 0, false
+	}
+	crewID, err := strconv.Atoi(c.Param("crewId"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return 0, 0, false
+	}
+	return uint(allieID), uint(crewID), true
+}
+
+func sendCrewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidCrew):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrBranchLimitReached):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
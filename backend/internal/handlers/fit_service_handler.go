@@ -0,0 +1,275 @@
+// internal/handlers/fit_service_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FitServiceHandler struct {
+	service *services.FitServiceService
+}
+
+func NewFitServiceHandler(fitServiceService *services.FitServiceService) *FitServiceHandler {
+	return &FitServiceHandler{service: fitServiceService}
+}
+
+// RegisterRoutes sets up routes for the service catalog and the services allies offer.
+// Browsing the active catalog is public; changing it is reserved to SUPERADMIN.
+func (h *FitServiceHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	catalog := rg.Group("/services")
+	{
+		catalog.GET("", h.ListServices)
+		catalog.GET("/all", middleware.AuthMiddleware(), middleware.RequireRoles(SUPERADMIN), h.ListAllServices)
+		catalog.GET("/:serviceId", h.GetService)
+		catalog.POST("", middleware.AuthMiddleware(), middleware.RequireRoles(SUPERADMIN), h.CreateService)
+		catalog.PUT("/:serviceId", middleware.AuthMiddleware(), middleware.RequireRoles(SUPERADMIN), h.UpdateService)
+		catalog.DELETE("/:serviceId", middleware.AuthMiddleware(), middleware.RequireRoles(SUPERADMIN), h.DeleteService)
+	}
+
+	offerings := rg.Group("/allies/:id/services")
+	offerings.Use(middleware.AuthMiddleware())
+	{
+		offerings.POST("", middleware.RequirePermission(PERM_OFFERING_MANAGE), h.AddOffering)
+		offerings.GET("", middleware.RequirePermission(PERM_OFFERING_READ), h.ListOfferings)
+		offerings.GET("/:offeringId", middleware.RequirePermission(PERM_OFFERING_READ), h.GetOffering)
+		offerings.PUT("/:offeringId", middleware.RequirePermission(PERM_OFFERING_MANAGE), h.UpdateOffering)
+		offerings.DELETE("/:offeringId", middleware.RequirePermission(PERM_OFFERING_MANAGE), h.RemoveOffering)
+	}
+}
+
+// ListServices handles retrieving the active services of the catalog.
+func (h *FitServiceHandler) ListServices(c *gin.Context) {
+	h.listServices(c, false)
+}
+
+// ListAllServices handles retrieving every service of the catalog, inactive ones included.
+func (h *FitServiceHandler) ListAllServices(c *gin.Context) {
+	h.listServices(c, true)
+}
+
+func (h *FitServiceHandler) listServices(c *gin.Context, includeInactive bool) {
+	var filter dtos.FitServiceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	catalog, err := h.service.ListServices(filter, includeInactive)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToFitServiceDTOs(catalog))
+}
+
+// GetService handles retrieving an active catalog service by ID.
+func (h *FitServiceHandler) GetService(c *gin.Context) {
+	serviceID, err := strconv.Atoi(c.Param("serviceId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SERVICE_INPUT)
+		return
+	}
+
+	service, err := h.service.GetService(uint(serviceID), false)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToFitServiceDTO(service))
+}
+
+// CreateService handles adding a service to the catalog.
+func (h *FitServiceHandler) CreateService(c *gin.Context) {
+	var input dtos.CreateFitServiceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	service, err := h.service.CreateService(input)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SERVICE_CREATED, mappers.ToFitServiceDTO(service))
+}
+
+// UpdateService handles updating a catalog service by ID.
+func (h *FitServiceHandler) UpdateService(c *gin.Context) {
+	serviceID, err := strconv.Atoi(c.Param("serviceId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SERVICE_INPUT)
+		return
+	}
+
+	var input dtos.UpdateFitServiceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	service, err := h.service.UpdateService(uint(serviceID), input)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SERVICE_UPDATED, mappers.ToFitServiceDTO(service))
+}
+
+// DeleteService handles removing a service from the catalog.
+func (h *FitServiceHandler) DeleteService(c *gin.Context) {
+	serviceID, err := strconv.Atoi(c.Param("serviceId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SERVICE_INPUT)
+		return
+	}
+
+	if err := h.service.DeleteService(uint(serviceID)); err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SERVICE_DELETED, nil)
+}
+
+// AddOffering handles offering a catalog service at an allie's crews.
+func (h *FitServiceHandler) AddOffering(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.CreateOfferingRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	offering, err := h.service.AddOffering(c, requester(c), uint(allieID), input)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, OFFERING_ADDED, mappers.ToOfferingDTO(offering))
+}
+
+// ListOfferings handles retrieving the services an allie offers.
+func (h *FitServiceHandler) ListOfferings(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var filter dtos.OfferingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	offerings, err := h.service.ListOfferings(c, requester(c), uint(allieID), filter)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToOfferingDTOs(offerings))
+}
+
+// GetOffering handles retrieving an offering by ID.
+func (h *FitServiceHandler) GetOffering(c *gin.Context) {
+	allieID, offeringID, ok := offeringParams(c)
+	if !ok {
+		return
+	}
+
+	offering, err := h.service.GetOffering(c, requester(c), allieID, offeringID)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToOfferingDTO(offering))
+}
+
+// UpdateOffering handles changing the price or status of an offering.
+func (h *FitServiceHandler) UpdateOffering(c *gin.Context) {
+	allieID, offeringID, ok := offeringParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateOfferingRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	offering, err := h.service.UpdateOffering(c, requester(c), allieID, offeringID, input)
+	if err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, OFFERING_UPDATED, mappers.ToOfferingDTO(offering))
+}
+
+// RemoveOffering handles withdrawing an offering.
+func (h *FitServiceHandler) RemoveOffering(c *gin.Context) {
+	allieID, offeringID, ok := offeringParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveOffering(c, requester(c), allieID, offeringID); err != nil {
+		sendFitServiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, OFFERING_REMOVED, nil)
+}
+
+// offeringParams parses the allie and offering IDs of an offering route, responding on failure
+func offeringParams(c *gin.Context) (uint, uint, bool) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return 0, 0, false
+	}
+	offeringID, err := strconv.Atoi(c.Param("offeringId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SERVICE_INPUT)
+		return 0, 0, false
+	}
+	return uint(allieID), uint(offeringID), true
+}
+
+func sendFitServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrServiceNotFound), errors.Is(err, services.ErrOfferingNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidService):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrServiceNameTaken), errors.Is(err, services.ErrOfferingExists):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,193 @@
+// internal/handlers/invoice_handler.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	service *services.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: invoiceService}
+}
+
+// RegisterRoutes sets up routes for the GST invoices and credit notes of
+// orders, read by crew staff and by customers for themselves.
+func (h *InvoiceHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	crews := rg.Group("/crews/:id")
+	crews.Use(middleware.AuthMiddleware())
+	{
+		crews.GET("/invoices", middleware.RequirePermission(PERM_INVOICE_READ), h.ListInvoices)
+		crews.GET("/invoices/:invoiceId", middleware.RequirePermission(PERM_INVOICE_READ), h.GetInvoice)
+		crews.GET("/invoices/:invoiceId/pdf", middleware.RequirePermission(PERM_INVOICE_READ), h.InvoicePDF)
+		crews.POST("/orders/:orderId/invoices", middleware.RequirePermission(PERM_INVOICE_MANAGE), h.IssueForOrder)
+	}
+
+	me := rg.Group("/me")
+	me.Use(middleware.AuthMiddleware(), middleware.RequirePermission(PERM_INVOICE_SELF))
+	{
+		me.GET("/invoices", h.ListOwnInvoices)
+		me.GET("/invoices/:invoiceId/pdf", h.OwnInvoicePDF)
+	}
+}
+
+// ListInvoices handles retrieving a page of a crew's invoices and credit notes.
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.InvoiceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	invoices, total, err := h.service.ListInvoices(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToInvoiceDTOs(invoices),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetInvoice handles retrieving an invoice or credit note of a crew.
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	crewID, invoiceID, ok := classParams(c, "invoiceId", INVALID_INVOICE_INPUT)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.service.GetInvoice(c, requester(c), crewID, invoiceID)
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToInvoiceDTO(invoice))
+}
+
+// InvoicePDF handles downloading an invoice or credit note of a crew as a PDF.
+func (h *InvoiceHandler) InvoicePDF(c *gin.Context) {
+	crewID, invoiceID, ok := classParams(c, "invoiceId", INVALID_INVOICE_INPUT)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.service.GetInvoice(c, requester(c), crewID, invoiceID)
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	sendInvoicePDF(c, invoice)
+}
+
+// IssueForOrder handles issuing the invoice and credit notes a paid order of a
+// crew is missing, such as after the seller's state was corrected.
+func (h *InvoiceHandler) IssueForOrder(c *gin.Context) {
+	crewID, orderID, ok := classParams(c, "orderId", INVALID_PAYMENT_INPUT)
+	if !ok {
+		return
+	}
+
+	invoices, err := h.service.IssueForOrder(c, requester(c), crewID, orderID)
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, INVOICE_ISSUED, mappers.ToInvoiceDTOs(invoices))
+}
+
+// ListOwnInvoices handles retrieving a page of the signed-in customer's invoices and credit notes.
+func (h *InvoiceHandler) ListOwnInvoices(c *gin.Context) {
+	var filter dtos.InvoiceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	invoices, total, err := h.service.ListOwnInvoices(c, requester(c), filter)
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToInvoiceDTOs(invoices),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// OwnInvoicePDF handles the signed-in customer downloading one of their invoices or credit notes.
+func (h *InvoiceHandler) OwnInvoicePDF(c *gin.Context) {
+	invoiceID, err := strconv.Atoi(c.Param("invoiceId"))
+	if err != nil {
+		BadRequestError(c, INVALID_INVOICE_INPUT)
+		return
+	}
+
+	invoice, err := h.service.GetOwnInvoice(c, requester(c), uint(invoiceID))
+	if err != nil {
+		sendInvoiceError(c, err)
+		return
+	}
+
+	sendInvoicePDF(c, invoice)
+}
+
+// sendInvoicePDF renders an invoice and sends it to be shown inline, named
+// after its number
+func sendInvoicePDF(c *gin.Context, invoice *models.Invoice) {
+	body, err := invoicing.RenderPDF(invoice)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+	filename := strings.ReplaceAll(invoice.Number, "/", "-") + ".pdf"
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
+
+func sendInvoiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrOrderNotFound),
+		errors.Is(err, services.ErrInvoiceNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidInvoice):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrInvoiceNotIssuable):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
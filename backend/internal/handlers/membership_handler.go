@@ -0,0 +1,237 @@
+// internal/handlers/membership_handler.go
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type MembershipHandler struct {
+	service *services.MembershipService
+}
+
+func NewMembershipHandler(membershipService *services.MembershipService) *MembershipHandler {
+	return &MembershipHandler{service: membershipService}
+}
+
+// RegisterRoutes sets up routes for the customers of a crew and their memberships.
+func (h *MembershipHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	customers := rg.Group("/crews/:id/customers")
+	customers.Use(middleware.AuthMiddleware())
+	{
+		customers.POST("", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Enroll)
+		customers.GET("", middleware.RequirePermission(PERM_CUSTOMER_READ), h.ListCustomers)
+		customers.GET("/:customerId", middleware.RequirePermission(PERM_CUSTOMER_READ), h.GetCustomer)
+		customers.GET("/:customerId/history", middleware.RequirePermission(PERM_CUSTOMER_READ), h.History)
+		customers.POST("/:customerId/renew", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Renew)
+		customers.POST("/:customerId/extend", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Extend)
+		customers.POST("/:customerId/freeze", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Freeze)
+		customers.POST("/:customerId/unfreeze", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Unfreeze)
+		customers.POST("/:customerId/transfer", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Transfer)
+		customers.POST("/:customerId/cancel", middleware.RequirePermission(PERM_CUSTOMER_MANAGE), h.Cancel)
+	}
+}
+
+// Enroll handles enrolling a customer into a crew.
+func (h *MembershipHandler) Enroll(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.EnrollCustomerRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	customer, err := h.service.Enroll(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendMembershipError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, CUSTOMER_ENROLLED, mappers.ToCustomerDTO(customer, time.Now()))
+}
+
+// ListCustomers handles retrieving a filtered page of a crew's customers.
+func (h *MembershipHandler) ListCustomers(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.CustomerFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	customers, total, err := h.service.ListCustomers(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendMembershipError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToCustomerDTOs(customers, time.Now()),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetCustomer handles retrieving a customer of a crew by ID.
+func (h *MembershipHandler) GetCustomer(c *gin.Context) {
+	crewID, customerID, ok := customerParams(c)
+	if !ok {
+		return
+	}
+
+	customer, err := h.service.GetCustomer(c, requester(c), crewID, customerID)
+	if err != nil {
+		sendMembershipError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToCustomerDTO(customer, time.Now()))
+}
+
+// History handles retrieving the membership transitions of a customer.
+func (h *MembershipHandler) History(c *gin.Context) {
+	crewID, customerID, ok := customerParams(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.service.History(c, requester(c), crewID, customerID)
+	if err != nil {
+		sendMembershipError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToMembershipHistoryDTOs(history))
+}
+
+// Renew handles starting a new membership term.
+func (h *MembershipHandler) Renew(c *gin.Context) {
+	var input dtos.RenewMembershipRequest
+	h.transition(c, &input, MEMBERSHIP_RENEW_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Renew(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// Extend handles adding days to a running membership.
+func (h *MembershipHandler) Extend(c *gin.Context) {
+	var input dtos.ExtendMembershipRequest
+	h.transition(c, &input, MEMBERSHIP_EXTEND_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Extend(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// Freeze handles pausing a membership.
+func (h *MembershipHandler) Freeze(c *gin.Context) {
+	var input dtos.FreezeMembershipRequest
+	h.transition(c, &input, MEMBERSHIP_FREEZE_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Freeze(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// Unfreeze handles ending a freeze early.
+func (h *MembershipHandler) Unfreeze(c *gin.Context) {
+	var input dtos.MembershipNoteRequest
+	h.transition(c, &input, MEMBERSHIP_RESUME_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Unfreeze(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// Transfer handles moving a membership to another crew of the same allie.
+func (h *MembershipHandler) Transfer(c *gin.Context) {
+	var input dtos.TransferMembershipRequest
+	h.transition(c, &input, MEMBERSHIP_TRANSFER_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Transfer(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// Cancel handles cancelling a membership.
+func (h *MembershipHandler) Cancel(c *gin.Context) {
+	var input dtos.MembershipNoteRequest
+	h.transition(c, &input, MEMBERSHIP_CANCEL_SUCCESS, func(crewID, customerID uint) (*models.Customer, error) {
+		return h.service.Cancel(c, requester(c), crewID, customerID, input)
+	})
+}
+
+// transition parses the route and body shared by the membership transitions and
+// responds with the changed customer. The body is optional for transitions whose
+// fields are all optional.
+func (h *MembershipHandler) transition(c *gin.Context, input interface{}, message string, apply func(crewID, customerID uint) (*models.Customer, error)) {
+	crewID, customerID, ok := customerParams(c)
+	if !ok {
+		return
+	}
+	err := c.ShouldBindJSON(input)
+	if errors.Is(err, io.EOF) {
+		err = binding.Validator.ValidateStruct(input)
+	}
+	if err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	customer, err := apply(crewID, customerID)
+	if err != nil {
+		sendMembershipError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, message, mappers.ToCustomerDTO(customer, time.Now()))
+}
+
+// customerParams parses the crew and customer IDs of a customer route, responding on failure
+func customerParams(c *gin.Context) (uint, uint, bool) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return 0, 0, false
+	}
+	customerID, err := strconv.Atoi(c.Param("customerId"))
+	if err != nil {
+		BadRequestError(c, INVALID_MEMBERSHIP_INPUT)
+		return 0, 0, false
+	}
+	return uint(crewID), uint(customerID), true
+}
+
+func sendMembershipError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrCustomerNotFound),
+		errors.Is(err, services.ErrPlanNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidMembership), errors.Is(err, services.ErrPlanNotAvailable), errors.Is(err, services.ErrInvalidPayment),
+		errors.Is(err, services.ErrInvalidPaymentMethod):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrPaymentFailed):
+		SendErrorResponse(c, STATUS_PAYMENT_REQUIRED, err.Error(), err.Error())
+	case errors.Is(err, services.ErrMembershipState), errors.Is(err, services.ErrCustomerAlreadyEnrolled), errors.Is(err, services.ErrUserAlreadyRegistered),
+		errors.Is(err, services.ErrPaymentInProgress):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
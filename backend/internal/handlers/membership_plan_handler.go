@@ -0,0 +1,163 @@
+// internal/handlers/membership_plan_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MembershipPlanHandler struct {
+	service *services.MembershipPlanService
+}
+
+func NewMembershipPlanHandler(planService *services.MembershipPlanService) *MembershipPlanHandler {
+	return &MembershipPlanHandler{service: planService}
+}
+
+// RegisterRoutes sets up routes for the membership plans of an allie.
+func (h *MembershipPlanHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	plans := rg.Group("/allies/:id/plans")
+	plans.Use(middleware.AuthMiddleware())
+	{
+		plans.POST("", middleware.RequirePermission(PERM_PLAN_MANAGE), h.CreatePlan)
+		plans.GET("", middleware.RequirePermission(PERM_PLAN_READ), h.ListPlans)
+		plans.GET("/:planId", middleware.RequirePermission(PERM_PLAN_READ), h.GetPlan)
+		plans.PUT("/:planId", middleware.RequirePermission(PERM_PLAN_MANAGE), h.UpdatePlan)
+		plans.DELETE("/:planId", middleware.RequirePermission(PERM_PLAN_MANAGE), h.DeletePlan)
+	}
+}
+
+// CreatePlan handles adding a membership plan to an allie.
+func (h *MembershipPlanHandler) CreatePlan(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.CreateMembershipPlanRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	plan, err := h.service.CreatePlan(c, requester(c), uint(allieID), input)
+	if err != nil {
+		sendPlanError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, PLAN_CREATED, mappers.ToMembershipPlanDTO(plan))
+}
+
+// ListPlans handles retrieving an allie's plans, optionally those sold at one crew.
+func (h *MembershipPlanHandler) ListPlans(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var filter dtos.MembershipPlanFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	plans, err := h.service.ListPlans(c, requester(c), uint(allieID), filter)
+	if err != nil {
+		sendPlanError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToMembershipPlanDTOs(plans))
+}
+
+// GetPlan handles retrieving a plan by ID.
+func (h *MembershipPlanHandler) GetPlan(c *gin.Context) {
+	allieID, planID, ok := planParams(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.service.GetPlan(c, requester(c), allieID, planID)
+	if err != nil {
+		sendPlanError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToMembershipPlanDTO(plan))
+}
+
+// UpdatePlan handles updating a plan by ID.
+func (h *MembershipPlanHandler) UpdatePlan(c *gin.Context) {
+	allieID, planID, ok := planParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateMembershipPlanRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	plan, err := h.service.UpdatePlan(c, requester(c), allieID, planID, input)
+	if err != nil {
+		sendPlanError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, PLAN_UPDATED, mappers.ToMembershipPlanDTO(plan))
+}
+
+// DeletePlan handles deleting a plan by ID.
+func (h *MembershipPlanHandler) DeletePlan(c *gin.Context) {
+	allieID, planID, ok := planParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePlan(c, requester(c), allieID, planID); err != nil {
+		sendPlanError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, PLAN_DELETED, nil)
+}
+
+// planParams parses the allie and plan IDs of a plan route, responding on failure
+func planParams(c *gin.Context) (uint, uint, bool) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return 0, 0, false
+	}
+	planID, err := strconv.Atoi(c.Param("planId"))
+	if err != nil {
+		BadRequestError(c, INVALID_PLAN_INPUT)
+		return 0, 0, false
+	}
+	return uint(allieID), uint(planID), true
+}
+
+func sendPlanError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrPlanNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidPlan):
+		BadRequestError(c, err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,179 @@
+// internal/handlers/menu_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"github.com/gin-gonic/gin"
+)
+
+type MenuHandler struct {
+	service *services.MenuService
+}
+
+func NewMenuHandler(menuService *services.MenuService) *MenuHandler {
+	return &MenuHandler{service: menuService}
+}
+
+// RegisterRoutes sets up the admin panel route endpoints and menu management.
+func (h *MenuHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// Route endpoints called by the admin panel router
+	routes := rg.Group("/route")
+	{
+		routes.GET("/getConstantRoutes", h.GetConstantRoutes)
+		routes.GET("/getUserRoutes", middleware.AuthMiddleware(), h.GetUserRoutes)
+		routes.GET("/isRouteExist", middleware.AuthMiddleware(), h.IsRouteExist)
+	}
+
+	menus := rg.Group("/menus")
+	menus.Use(middleware.AuthMiddleware())
+	{
+		menus.GET("", middleware.RequirePermission(PERM_MENU_READ), h.ListMenus)
+		menus.POST("", middleware.RequirePermission(PERM_MENU_MANAGE), h.CreateMenu)
+		menus.PUT("/:id", middleware.RequirePermission(PERM_MENU_MANAGE), h.UpdateMenu)
+		menus.DELETE("/:id", middleware.RequirePermission(PERM_MENU_MANAGE), h.DeleteMenu)
+	}
+}
+
+// GetConstantRoutes returns the routes available before login
+func (h *MenuHandler) GetConstantRoutes(c *gin.Context) {
+	menus, err := h.service.ConstantRoutes(c)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  SUCCESS,
+		"data": mappers.ToMenuRouteTree(menus),
+	})
+}
+
+// GetUserRoutes returns the route tree the caller's role may see
+func (h *MenuHandler) GetUserRoutes(c *gin.Context) {
+	menus, err := h.service.UserRoutes(c, middleware.CurrentRole(c))
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  SUCCESS,
+		"data": gin.H{
+			"routes": mappers.ToMenuRouteTree(menus),
+			"home":   services.HomeRoute,
+		},
+	})
+}
+
+// IsRouteExist tells the admin panel whether an unknown route name is defined
+// at all, so it can show 403 rather than 404
+func (h *MenuHandler) IsRouteExist(c *gin.Context) {
+	routeName := c.Query("routeName")
+	if routeName == "" {
+		BadRequestError(c, BAD_REQUEST)
+		return
+	}
+
+	exists, err := h.service.RouteExists(c, routeName)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": "0000",
+		"msg":  SUCCESS,
+		"data": exists,
+	})
+}
+
+// ListMenus returns every menu entry as a tree.
+func (h *MenuHandler) ListMenus(c *gin.Context) {
+	menus, err := h.service.ListMenus(c)
+	if err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToMenuDTOTree(menus))
+}
+
+// CreateMenu handles adding a menu entry.
+func (h *MenuHandler) CreateMenu(c *gin.Context) {
+	var input dtos.CreateMenuRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	menu, err := h.service.CreateMenu(c, input)
+	if err != nil {
+		sendMenuError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, MENU_CREATED, mappers.ToMenuDTO(menu))
+}
+
+// UpdateMenu handles updating a menu entry by ID.
+func (h *MenuHandler) UpdateMenu(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, BAD_REQUEST)
+		return
+	}
+
+	var input dtos.UpdateMenuRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	menu, err := h.service.UpdateMenu(c, uint(id), input)
+	if err != nil {
+		sendMenuError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, MENU_UPDATED, mappers.ToMenuDTO(menu))
+}
+
+// DeleteMenu handles deleting a menu entry by ID.
+func (h *MenuHandler) DeleteMenu(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, BAD_REQUEST)
+		return
+	}
+
+	if err := h.service.DeleteMenu(c, uint(id)); err != nil {
+		sendMenuError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, MENU_DELETED, nil)
+}
+
+func sendMenuError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrMenuNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrMenuNameTaken), errors.Is(err, services.ErrMenuHasChildren):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	case errors.Is(err, services.ErrMenuInvalidParent), errors.Is(err, services.ErrInvalidPermission):
+		BadRequestError(c, err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
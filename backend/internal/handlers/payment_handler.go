@@ -0,0 +1,282 @@
+// internal/handlers/payment_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// webhookSignatureHeader carries the gateway's signature of a webhook body
+const webhookSignatureHeader = "X-Payment-Signature"
+
+type PaymentHandler struct {
+	service *services.PaymentService
+}
+
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{service: paymentService}
+}
+
+// RegisterRoutes sets up routes for membership orders taken by crew staff and
+// by customers for themselves, their refunds, and the gateway's webhooks.
+func (h *PaymentHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	crews := rg.Group("/crews/:id")
+	crews.Use(middleware.AuthMiddleware())
+	{
+		crews.GET("/orders", middleware.RequirePermission(PERM_PAYMENT_READ), h.ListOrders)
+		crews.GET("/orders/:orderId", middleware.RequirePermission(PERM_PAYMENT_READ), h.GetOrder)
+		crews.POST("/orders/:orderId/attempts", middleware.RequirePermission(PERM_PAYMENT_MANAGE), h.PayOrder)
+		crews.POST("/orders/:orderId/refunds", middleware.RequirePermission(PERM_PAYMENT_MANAGE), h.Refund)
+		crews.POST("/customers/:customerId/orders", middleware.RequirePermission(PERM_PAYMENT_MANAGE), h.CreateOrder)
+	}
+
+	me := rg.Group("/me")
+	me.Use(middleware.AuthMiddleware(), middleware.RequirePermission(PERM_PAYMENT_SELF))
+	{
+		me.GET("/orders", h.ListOwnOrders)
+		me.POST("/orders/:orderId/attempts", h.PayOwnOrder)
+		me.POST("/crews/:id/orders", h.CreateOwnOrder)
+	}
+
+	// Webhooks are authenticated by the gateway's signature, not a user token
+	rg.POST("/payments/webhooks/:gateway", h.Webhook)
+}
+
+// ListOrders handles retrieving a page of a crew's orders.
+func (h *PaymentHandler) ListOrders(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	orders, total, err := h.service.ListOrders(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToPaymentOrderDTOs(orders),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetOrder handles retrieving an order of a crew with its ledger entries.
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	crewID, orderID, ok := classParams(c, "orderId", INVALID_PAYMENT_INPUT)
+	if !ok {
+		return
+	}
+
+	order, entries, err := h.service.GetOrder(c, requester(c), crewID, orderID)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToPaymentOrderDetailDTO(order, entries))
+}
+
+// CreateOrder handles ordering and paying a membership term for a customer of the crew.
+func (h *PaymentHandler) CreateOrder(c *gin.Context) {
+	crewID, customerID, ok := customerParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.CreateOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	order, err := h.service.CreateOrder(c, requester(c), crewID, customerID, input)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	sendOrderPaid(c, order)
+}
+
+// PayOrder handles paying again an order of the crew whose payments failed.
+func (h *PaymentHandler) PayOrder(c *gin.Context) {
+	crewID, orderID, ok := classParams(c, "orderId", INVALID_PAYMENT_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.PayOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	order, err := h.service.PayOrder(c, requester(c), crewID, orderID, input)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	sendOrderPaid(c, order)
+}
+
+// Refund handles refunding part or all of a paid order of the crew.
+func (h *PaymentHandler) Refund(c *gin.Context) {
+	crewID, orderID, ok := classParams(c, "orderId", INVALID_PAYMENT_INPUT)
+	if !ok {
+		return
+	}
+
+	var input dtos.RefundOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	order, err := h.service.Refund(c, requester(c), crewID, orderID, input)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, REFUND_SUCCESSFUL, mappers.ToPaymentOrderDTO(order))
+}
+
+// ListOwnOrders handles retrieving a page of the signed-in customer's orders.
+func (h *PaymentHandler) ListOwnOrders(c *gin.Context) {
+	var filter dtos.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	orders, total, err := h.service.ListOwnOrders(c, requester(c), filter)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToPaymentOrderDTOs(orders),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// CreateOwnOrder handles the signed-in customer buying a term of their membership at a crew.
+func (h *PaymentHandler) CreateOwnOrder(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CreateOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	order, err := h.service.CreateOwnOrder(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	sendOrderPaid(c, order)
+}
+
+// PayOwnOrder handles the signed-in customer paying again one of their orders.
+func (h *PaymentHandler) PayOwnOrder(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("orderId"))
+	if err != nil {
+		BadRequestError(c, INVALID_PAYMENT_INPUT)
+		return
+	}
+
+	var input dtos.PayOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	order, err := h.service.PayOwnOrder(c, requester(c), uint(orderID), input)
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	sendOrderPaid(c, order)
+}
+
+// Webhook handles a notification from a payment gateway.
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	duplicate, err := h.service.HandleWebhook(c, c.Param("gateway"), payload, c.GetHeader(webhookSignatureHeader))
+	if err != nil {
+		sendPaymentError(c, err)
+		return
+	}
+
+	message := WEBHOOK_PROCESSED
+	if duplicate {
+		message = WEBHOOK_ALREADY_PROCESSED
+	}
+	SendSuccessResponse(c, message, nil)
+}
+
+// sendOrderPaid responds with an order after a payment, telling a captured
+// payment from one still awaiting the gateway
+func sendOrderPaid(c *gin.Context, order *models.PaymentOrder) {
+	message := PAYMENT_SUCCESSFUL
+	if order.Status == ORDER_STATUS_PENDING {
+		message = PAYMENT_PENDING
+	}
+	SendSuccessResponse(c, message, mappers.ToPaymentOrderDTO(order))
+}
+
+func sendPaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound), errors.Is(err, services.ErrCustomerNotFound),
+		errors.Is(err, services.ErrPlanNotFound), errors.Is(err, services.ErrOrderNotFound), errors.Is(err, services.ErrUnknownGateway):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidWebhook):
+		SendErrorResponse(c, STATUS_UNAUTHORIZED, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidPayment), errors.Is(err, services.ErrInvalidPaymentMethod), errors.Is(err, services.ErrPlanNotAvailable):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrPaymentFailed):
+		SendErrorResponse(c, STATUS_PAYMENT_REQUIRED, err.Error(), err.Error())
+	case errors.Is(err, services.ErrOrderNotPayable), errors.Is(err, services.ErrPaymentInProgress), errors.Is(err, services.ErrRefundNotAllowed),
+		errors.Is(err, services.ErrRefundFailed):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -36,8 +36,22 @@ func SetupRouter(services *services.Services) *gin.Engine {
 
 	// List of all handlers
 	handlers := []Handler{
-		NewAuthHandler(*services.UserService),
-		NewUserHandler(services.UserService),
+		NewAuthHandler(services.UserService, services.AuthService, services.PasswordService, services.OTPService, services.MFAService),
+		NewUserHandler(services.UserService, services.LockoutService, services.ImpersonationService),
+		NewMenuHandler(services.MenuService),
+		NewFitAllieHandler(services.FitAllieService),
+		NewFitCrewHandler(services.FitCrewService),
+		NewMembershipHandler(services.MembershipService),
+		NewMembershipPlanHandler(services.MembershipPlanService),
+		NewTrainerHandler(services.TrainerService),
+		NewFitServiceHandler(services.FitServiceService),
+		NewCrewScheduleHandler(services.CrewScheduleService),
+		NewClassHandler(services.ClassService),
+		NewBookingHandler(services.BookingService),
+		NewCheckInHandler(services.CheckInService),
+		NewPaymentHandler(services.PaymentService),
+		NewInvoiceHandler(services.InvoiceService),
+		NewSettlementHandler(services.SettlementService),
 
 		// Add new handlers here (e.g., NewAuthHandler, NewProductHandler, etc.)
 	}
@@ -0,0 +1,308 @@
+// internal/handlers/settlement_handler.go
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementHandler struct {
+	service *services.SettlementService
+}
+
+func NewSettlementHandler(settlementService *services.SettlementService) *SettlementHandler {
+	return &SettlementHandler{service: settlementService}
+}
+
+// RegisterRoutes sets up routes for the commission settlement statements of
+// allies, generated and paid out by the platform and read by the allies.
+func (h *SettlementHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	allies := rg.Group("/allies/:id/settlements")
+	allies.Use(middleware.AuthMiddleware())
+	{
+		allies.GET("", middleware.RequirePermission(PERM_SETTLEMENT_READ), h.ListStatements)
+		allies.GET("/:settlementId", middleware.RequirePermission(PERM_SETTLEMENT_READ), h.GetStatement)
+		allies.GET("/:settlementId/export", middleware.RequirePermission(PERM_SETTLEMENT_READ), h.ExportStatement)
+		allies.POST("", middleware.RequirePermission(PERM_SETTLEMENT_MANAGE), h.GenerateStatement)
+		allies.POST("/:settlementId/approve", middleware.RequirePermission(PERM_SETTLEMENT_MANAGE), h.ApproveStatement)
+		allies.POST("/:settlementId/paid", middleware.RequirePermission(PERM_SETTLEMENT_MANAGE), h.MarkStatementPaid)
+		allies.DELETE("/:settlementId", middleware.RequirePermission(PERM_SETTLEMENT_MANAGE), h.DiscardStatement)
+	}
+
+	settlements := rg.Group("/settlements")
+	settlements.Use(middleware.AuthMiddleware(), middleware.RequirePermission(PERM_SETTLEMENT_MANAGE))
+	{
+		settlements.GET("", h.ListAllStatements)
+	}
+}
+
+// GenerateStatement handles drafting an allie's statement for a period.
+func (h *SettlementHandler) GenerateStatement(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var input dtos.GenerateSettlementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	statement, err := h.service.GenerateStatement(c, requester(c), uint(allieID), input)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SETTLEMENT_CREATED, mappers.ToSettlementStatementDetailDTO(statement))
+}
+
+// ListStatements handles retrieving a page of an allie's statements.
+func (h *SettlementHandler) ListStatements(c *gin.Context) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return
+	}
+
+	var filter dtos.SettlementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	statements, total, err := h.service.ListStatements(c, requester(c), uint(allieID), filter)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToSettlementStatementDTOs(statements),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// ListAllStatements handles retrieving a page of the statements of every allie.
+func (h *SettlementHandler) ListAllStatements(c *gin.Context) {
+	var filter dtos.SettlementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	statements, total, err := h.service.ListAllStatements(c, filter)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToSettlementStatementDTOs(statements),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetStatement handles retrieving a statement of an allie with its lines.
+func (h *SettlementHandler) GetStatement(c *gin.Context) {
+	allieID, statementID, ok := settlementParams(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.service.GetStatement(c, requester(c), allieID, statementID)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToSettlementStatementDetailDTO(statement))
+}
+
+// ExportStatement handles downloading a statement of an allie for finance, as
+// CSV with a row per line or as JSON.
+func (h *SettlementHandler) ExportStatement(c *gin.Context) {
+	allieID, statementID, ok := settlementParams(c)
+	if !ok {
+		return
+	}
+
+	var query dtos.SettlementExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(query.Format))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		BadRequestError(c, fmt.Sprintf("%s: format must be csv or json", INVALID_SETTLEMENT_INPUT))
+		return
+	}
+
+	statement, err := h.service.GetStatement(c, requester(c), allieID, statementID)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	detail := mappers.ToSettlementStatementDetailDTO(statement)
+	filename := fmt.Sprintf("settlement-%d-%s-%s.%s", statement.ID, detail.PeriodStart, detail.PeriodEnd, format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == "json" {
+		c.JSON(http.StatusOK, detail)
+		return
+	}
+	sendSettlementCSV(c, statement)
+}
+
+// ApproveStatement handles approving a draft statement for payout.
+func (h *SettlementHandler) ApproveStatement(c *gin.Context) {
+	allieID, statementID, ok := settlementParams(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.service.ApproveStatement(c, requester(c), allieID, statementID)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SETTLEMENT_APPROVED, mappers.ToSettlementStatementDTO(statement))
+}
+
+// MarkStatementPaid handles recording the payout of an approved statement.
+func (h *SettlementHandler) MarkStatementPaid(c *gin.Context) {
+	allieID, statementID, ok := settlementParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.MarkSettlementPaidRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	statement, err := h.service.MarkStatementPaid(c, requester(c), allieID, statementID, input)
+	if err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SETTLEMENT_PAID, mappers.ToSettlementStatementDTO(statement))
+}
+
+// DiscardStatement handles deleting a draft statement.
+func (h *SettlementHandler) DiscardStatement(c *gin.Context) {
+	allieID, statementID, ok := settlementParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DiscardStatement(c, requester(c), allieID, statementID); err != nil {
+		sendSettlementError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SETTLEMENT_DISCARDED, nil)
+}
+
+// sendSettlementCSV writes a row per line of the statement with plain decimal
+// amounts, refunds negative, repeating the statement's columns on every row so
+// exports of several statements can be joined
+func sendSettlementCSV(c *gin.Context, statement *models.SettlementStatement) {
+	summary := mappers.ToSettlementStatementDTO(statement)
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{
+		"statement_id", "allie_id", "period_start", "period_end", "status",
+		"crew_id", "order_id", "transaction_ref", "kind", "posted_at", "currency",
+		"commission_rate", "gross", "tax", "revenue", "commission", "payout",
+	})
+	for _, line := range statement.Lines {
+		w.Write([]string{
+			strconv.FormatUint(uint64(statement.ID), 10),
+			strconv.Itoa(statement.AllieID),
+			summary.PeriodStart,
+			summary.PeriodEnd,
+			string(statement.Status),
+			strconv.Itoa(line.CrewID),
+			strconv.FormatUint(uint64(line.OrderID), 10),
+			line.TransactionRef,
+			string(line.Kind),
+			line.PostedAt.In(invoicing.IST).Format("2006-01-02T15:04:05-07:00"),
+			line.Currency,
+			strconv.Itoa(line.CommissionRate),
+			decimalMinor(line.GrossMinor),
+			decimalMinor(line.TaxMinor),
+			decimalMinor(line.RevenueMinor),
+			decimalMinor(line.CommissionMinor),
+			decimalMinor(line.PayoutMinor),
+		})
+	}
+	w.Flush()
+}
+
+// decimalMinor formats an amount in minor units as a plain decimal, -1234.50
+func decimalMinor(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// settlementParams parses the allie ID and the statement ID of a route, responding on failure
+func settlementParams(c *gin.Context) (uint, uint, bool) {
+	allieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_ALLIE_INPUT)
+		return 0, 0, false
+	}
+	statementID, err := strconv.Atoi(c.Param("settlementId"))
+	if err != nil {
+		BadRequestError(c, INVALID_SETTLEMENT_INPUT)
+		return 0, 0, false
+	}
+	return uint(allieID), uint(statementID), true
+}
+
+func sendSettlementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrSettlementNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidSettlement):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrSettlementConflict), errors.Is(err, services.ErrNothingToSettle):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
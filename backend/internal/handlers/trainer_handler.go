@@ -0,0 +1,172 @@
+// internal/handlers/trainer_handler.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/mappers"
+	"backend/internal/middleware"
+	. "backend/internal/resources/constants"
+	. "backend/internal/resources/response"
+	"backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TrainerHandler struct {
+	service *services.TrainerService
+}
+
+func NewTrainerHandler(trainerService *services.TrainerService) *TrainerHandler {
+	return &TrainerHandler{service: trainerService}
+}
+
+// RegisterRoutes sets up routes for the trainers of a crew.
+func (h *TrainerHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	trainers := rg.Group("/crews/:id/trainers")
+	trainers.Use(middleware.AuthMiddleware())
+	{
+		trainers.POST("", middleware.RequirePermission(PERM_TRAINER_MANAGE), h.CreateTrainer)
+		trainers.GET("", middleware.RequirePermission(PERM_TRAINER_READ), h.ListTrainers)
+		trainers.GET("/:trainerId", middleware.RequirePermission(PERM_TRAINER_READ), h.GetTrainer)
+		trainers.PUT("/:trainerId", middleware.RequirePermission(PERM_TRAINER_MANAGE), h.UpdateTrainer)
+		trainers.DELETE("/:trainerId", middleware.RequirePermission(PERM_TRAINER_MANAGE), h.DeleteTrainer)
+	}
+}
+
+// CreateTrainer handles adding a trainer to a crew.
+func (h *TrainerHandler) CreateTrainer(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var input dtos.CreateTrainerRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	trainer, err := h.service.CreateTrainer(c, requester(c), uint(crewID), input)
+	if err != nil {
+		sendTrainerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, TRAINER_CREATED, mappers.ToTrainerDTO(trainer, time.Now()))
+}
+
+// ListTrainers handles retrieving a filtered page of a crew's trainers.
+func (h *TrainerHandler) ListTrainers(c *gin.Context) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return
+	}
+
+	var filter dtos.TrainerFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	trainers, total, err := h.service.ListTrainers(c, requester(c), uint(crewID), filter)
+	if err != nil {
+		sendTrainerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, dtos.PageResponse{
+		Items: mappers.ToTrainerDTOs(trainers, time.Now()),
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	})
+}
+
+// GetTrainer handles retrieving a trainer by ID.
+func (h *TrainerHandler) GetTrainer(c *gin.Context) {
+	crewID, trainerID, ok := trainerParams(c)
+	if !ok {
+		return
+	}
+
+	trainer, err := h.service.GetTrainer(c, requester(c), crewID, trainerID)
+	if err != nil {
+		sendTrainerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, SUCCESS, mappers.ToTrainerDTO(trainer, time.Now()))
+}
+
+// UpdateTrainer handles updating a trainer by ID.
+func (h *TrainerHandler) UpdateTrainer(c *gin.Context) {
+	crewID, trainerID, ok := trainerParams(c)
+	if !ok {
+		return
+	}
+
+	var input dtos.UpdateTrainerRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	trainer, err := h.service.UpdateTrainer(c, requester(c), crewID, trainerID, input)
+	if err != nil {
+		sendTrainerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, TRAINER_UPDATED, mappers.ToTrainerDTO(trainer, time.Now()))
+}
+
+// DeleteTrainer handles deleting a trainer by ID.
+func (h *TrainerHandler) DeleteTrainer(c *gin.Context) {
+	crewID, trainerID, ok := trainerParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTrainer(c, requester(c), crewID, trainerID); err != nil {
+		sendTrainerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, TRAINER_DELETED, nil)
+}
+
+// trainerParams parses the crew and trainer IDs of a trainer route, responding on failure
+func trainerParams(c *gin.Context) (uint, uint, bool) {
+	crewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_CREW_INPUT)
+		return 0, 0, false
+	}
+	trainerID, err := strconv.Atoi(c.Param("trainerId"))
+	if err != nil {
+		BadRequestError(c, INVALID_TRAINER_INPUT)
+		return 0, 0, false
+	}
+	return uint(crewID), uint(trainerID), true
+}
+
+func sendTrainerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAllieNotFound), errors.Is(err, services.ErrCrewNotFound),
+		errors.Is(err, services.ErrTrainerNotFound), errors.Is(err, services.ErrUserNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, services.ErrAllieAccessDenied):
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+	case errors.Is(err, services.ErrInvalidTrainer):
+		BadRequestError(c, err.Error())
+	case errors.Is(err, services.ErrTrainerUserTaken), errors.Is(err, services.ErrUserAlreadyRegistered):
+		SendErrorResponse(c, STATUS_CONFLICT, err.Error(), err.Error())
+	default:
+		InternalServerError(c, err)
+	}
+}
@@ -2,11 +2,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"backend/internal/dtos"
 	"backend/internal/mappers"
+	"backend/internal/middleware"
 	"backend/internal/services"
 	"github.com/gin-gonic/gin"
 	. "backend/internal/resources/response"
@@ -14,22 +16,27 @@ import (
 )
 
 type UserHandler struct {
-	service *services.UserService
+	service              *services.UserService
+	lockoutService       *services.LockoutService
+	impersonationService *services.ImpersonationService
 }
 
-func NewUserHandler(userService *services.UserService) *UserHandler {
-	return &UserHandler{service: userService}
+func NewUserHandler(userService *services.UserService, lockoutService *services.LockoutService, impersonationService *services.ImpersonationService) *UserHandler {
+	return &UserHandler{service: userService, lockoutService: lockoutService, impersonationService: impersonationService}
 }
 
 // RegisterRoutes sets up routes for user-related operations.
 func (h *UserHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	users := rg.Group("/users")
+	users.Use(middleware.AuthMiddleware())
 	{
-		users.POST("", h.CreateUser)
-		users.GET("/:id", h.GetUserByID)
-		users.PUT("/:id", h.UpdateUser)
-		users.DELETE("/:id", h.DeleteUser)
-		users.GET("", h.GetUsers)
+		users.POST("", middleware.RequirePermission(PERM_USER_CREATE), h.CreateUser)
+		users.GET("/:id", middleware.RequirePermission(PERM_USER_READ), h.GetUserByID)
+		users.PUT("/:id", middleware.RequirePermission(PERM_USER_UPDATE), h.UpdateUser)
+		users.DELETE("/:id", middleware.RequirePermission(PERM_USER_DELETE), h.DeleteUser)
+		users.GET("", middleware.RequirePermission(PERM_USER_READ), h.GetUsers)
+		users.POST("/:id/unlock", middleware.RequirePermission(PERM_USER_UNLOCK), h.UnlockUser)
+		users.POST("/:id/impersonate", middleware.RequireRoles(SUPERADMIN), middleware.DenyImpersonation(), h.ImpersonateUser)
 	}
 }
 
@@ -41,7 +48,11 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
         return
     }
 
-    user, err := h.service.CreateUser(c, input)
+    user, err := h.service.CreateUser(c, middleware.CurrentRole(c), input)
+    if errors.Is(err, services.ErrUserAccessDenied) {
+        SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+        return
+    }
     if err != nil {
         InternalServerError(c, err)
         return
@@ -83,7 +94,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	// Call service to update the user
-	user, err := h.service.UpdateUser(c, int32(id), input.FirstName, "", input.LastName, input.Email, input.Password)
+	user, err := h.service.UpdateUser(c, middleware.CurrentRole(c), int32(id), input.FirstName, "", input.LastName, input.Email, input.Password)
+	if errors.Is(err, services.ErrUserAccessDenied) {
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+		return
+	}
 	if err != nil {
 		SendErrorResponse(c, STATUS_BAD_REQUEST, INVALID_USER_INPUT, err.Error())
 		return
@@ -100,7 +115,11 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	}
 
 	// Call service to delete the user
-	err = h.service.DeleteUser(c, int32(id))
+	err = h.service.DeleteUser(c, middleware.CurrentRole(c), int32(id))
+	if errors.Is(err, services.ErrUserAccessDenied) {
+		SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+		return
+	}
 	if err != nil {
 		SendErrorResponse(c, STATUS_BAD_REQUEST, USER_NOT_FOUND, err.Error())
 		return
@@ -121,3 +140,65 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	// Return the list of users as DTOs
   SendSuccessResponse(c, SUCCESS, mappers.ToUserDTOs(users))
 }
+
+// UnlockUser clears a brute-force lock on a user account. Only accounts the
+// caller's role may manage can be unlocked.
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_USER_INPUT)
+		return
+	}
+
+	user, err := h.service.GetUserByID(c, int32(id))
+	if err != nil {
+		NotFoundError(c, USER_NOT_FOUND)
+		return
+	}
+	if !middleware.CurrentRole(c).CanManage(user.UserType) {
+		SendErrorResponse(c, STATUS_FORBIDDEN, USER_ACCESS_DENIED, USER_ACCESS_DENIED)
+		return
+	}
+
+	if err := h.lockoutService.Unlock(c, uint(id), middleware.CurrentUserID(c)); err != nil {
+		InternalServerError(c, err)
+		return
+	}
+
+	SendSuccessResponse(c, ACCOUNT_UNLOCKED, nil)
+}
+
+// ImpersonateUser issues a short-lived token to act as another user. The
+// caller stays recorded as the actor in the token and in the audit trail.
+func (h *UserHandler) ImpersonateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadRequestError(c, INVALID_USER_INPUT)
+		return
+	}
+
+	var input dtos.ImpersonateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadRequestError(c, err.Error())
+		return
+	}
+
+	token, err := h.impersonationService.Impersonate(c, middleware.CurrentUserID(c), uint(id), input.Reason, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			NotFoundError(c, USER_NOT_FOUND)
+		case errors.Is(err, services.ErrImpersonationForbidden), errors.Is(err, services.ErrAccountInactive):
+			SendErrorResponse(c, STATUS_FORBIDDEN, err.Error(), err.Error())
+		default:
+			InternalServerError(c, err)
+		}
+		return
+	}
+
+	SendSuccessResponse(c, IMPERSONATION_STARTED, dtos.ImpersonationResponse{
+		Token:     token.AccessToken,
+		ExpiresAt: token.ExpiresAt,
+		User:      mappers.ToUserDTO(token.Target),
+	})
+}
@@ -0,0 +1,150 @@
+// internal/invoicing/gst.go
+// Package invoicing holds the GST rules of tax invoices and credit notes and
+// renders them as PDF documents.
+package invoicing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SACFitness is the services accounting code of health club and fitness
+// centre services, the supply a membership is invoiced as
+const SACFitness = "999723"
+
+// stateCodes maps the GST code of each state and union territory to its name
+var stateCodes = map[string]string{
+	"01": "Jammu and Kashmir",
+	"02": "Himachal Pradesh",
+	"03": "Punjab",
+	"04": "Chandigarh",
+	"05": "Uttarakhand",
+	"06": "Haryana",
+	"07": "Delhi",
+	"08": "Rajasthan",
+	"09": "Uttar Pradesh",
+	"10": "Bihar",
+	"11": "Sikkim",
+	"12": "Arunachal Pradesh",
+	"13": "Nagaland",
+	"14": "Manipur",
+	"15": "Mizoram",
+	"16": "Tripura",
+	"17": "Meghalaya",
+	"18": "Assam",
+	"19": "West Bengal",
+	"20": "Jharkhand",
+	"21": "Odisha",
+	"22": "Chhattisgarh",
+	"23": "Madhya Pradesh",
+	"24": "Gujarat",
+	"26": "Dadra and Nagar Haveli and Daman and Diu",
+	"27": "Maharashtra",
+	"29": "Karnataka",
+	"30": "Goa",
+	"31": "Lakshadweep",
+	"32": "Kerala",
+	"33": "Tamil Nadu",
+	"34": "Puducherry",
+	"35": "Andaman and Nicobar Islands",
+	"36": "Telangana",
+	"37": "Andhra Pradesh",
+	"38": "Ladakh",
+}
+
+// stateAliases are other names the states are commonly written with
+var stateAliases = map[string]string{
+	"orissa":                 "21",
+	"pondicherry":            "34",
+	"uttaranchal":            "05",
+	"new delhi":              "07",
+	"nct of delhi":           "07",
+	"daman and diu":          "26",
+	"dadra and nagar haveli": "26",
+	"andaman and nicobar":    "35",
+	"j and k":                "01",
+}
+
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// StateCode returns the GST code of a state given by name or by code. Case,
+// punctuation and '&' for 'and' do not matter.
+func StateCode(state string) (string, bool) {
+	key := strings.ReplaceAll(strings.ToLower(state), "&", " and ")
+	key = strings.TrimSpace(nonAlphanumeric.ReplaceAllString(key, " "))
+	if key == "" {
+		return "", false
+	}
+	if _, ok := stateCodes[key]; ok {
+		return key, true
+	}
+	if len(key) == 1 {
+		if _, ok := stateCodes["0"+key]; ok {
+			return "0" + key, true
+		}
+	}
+	for code, name := range stateCodes {
+		if strings.ToLower(name) == key {
+			return code, true
+		}
+	}
+	code, ok := stateAliases[key]
+	return code, ok
+}
+
+// StateName returns the name of the state with the GST code
+func StateName(code string) string {
+	return stateCodes[code]
+}
+
+// ValidGSTIN reports whether s has the form of a GST identification number
+// registered in a known state
+func ValidGSTIN(s string) bool {
+	if !gstinPattern.MatchString(s) {
+		return false
+	}
+	_, ok := stateCodes[s[:2]]
+	return ok
+}
+
+// TaxSplit is the tax of a supply split between the central and state taxes
+// charged within a state, or the integrated tax charged across states
+type TaxSplit struct {
+	Interstate bool
+	CGSTMinor  int64
+	SGSTMinor  int64
+	IGSTMinor  int64
+}
+
+// SplitTax splits tax for a supply from the seller's state to the place of
+// supply. Within a state it is shared equally by CGST and SGST, CGST taking the
+// odd paisa, so the parts always add up to the tax the buyer paid.
+func SplitTax(tax int64, sellerStateCode, placeOfSupplyCode string) TaxSplit {
+	if sellerStateCode != placeOfSupplyCode {
+		return TaxSplit{Interstate: true, IGSTMinor: tax}
+	}
+	cgst := (tax + 1) / 2
+	return TaxSplit{CGSTMinor: cgst, SGSTMinor: tax - cgst}
+}
+
+// IST is Indian Standard Time, the clock invoices are dated on
+var IST = time.FixedZone("IST", 5*60*60+30*60)
+
+// FiscalYear returns the Indian financial year, April to March, t falls in on
+// the Indian clock, named by the year it starts
+func FiscalYear(t time.Time) int {
+	t = t.In(IST)
+	if t.Month() < time.April {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// FiscalYearLabel names a financial year the way invoice numbers carry it, 2026-27
+func FiscalYearLabel(year int) string {
+	return fmt.Sprintf("%d-%02d", year, (year+1)%100)
+}
@@ -0,0 +1,101 @@
+package invoicing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitTax(t *testing.T) {
+	tests := []struct {
+		name          string
+		tax           int64
+		seller, place string
+		want          TaxSplit
+	}{
+		{"even intrastate", 1800, "29", "29", TaxSplit{CGSTMinor: 900, SGSTMinor: 900}},
+		{"odd paisa goes to CGST", 1801, "29", "29", TaxSplit{CGSTMinor: 901, SGSTMinor: 900}},
+		{"one paisa", 1, "29", "29", TaxSplit{CGSTMinor: 1, SGSTMinor: 0}},
+		{"no tax", 0, "29", "29", TaxSplit{}},
+		{"interstate", 1801, "29", "33", TaxSplit{Interstate: true, IGSTMinor: 1801}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitTax(tt.tax, tt.seller, tt.place)
+			if got != tt.want {
+				t.Fatalf("SplitTax(%d, %s, %s) = %+v, want %+v", tt.tax, tt.seller, tt.place, got, tt.want)
+			}
+			if sum := got.CGSTMinor + got.SGSTMinor + got.IGSTMinor; sum != tt.tax {
+				t.Errorf("parts add up to %d, want %d", sum, tt.tax)
+			}
+		})
+	}
+}
+
+func TestFiscalYear(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{"last instant of March in IST", time.Date(2026, time.March, 31, 23, 59, 59, 0, IST), 2025},
+		{"first instant of April in IST", time.Date(2026, time.April, 1, 0, 0, 0, 0, IST), 2026},
+		// 18:30 UTC on 31 March is already midnight of 1 April in India
+		{"April in IST but March in UTC", time.Date(2026, time.March, 31, 18, 30, 0, 0, time.UTC), 2026},
+		{"March in IST just before", time.Date(2026, time.March, 31, 18, 29, 59, 0, time.UTC), 2025},
+		{"January", time.Date(2027, time.January, 15, 12, 0, 0, 0, IST), 2026},
+		{"December", time.Date(2026, time.December, 31, 12, 0, 0, 0, IST), 2026},
+	}
+
+	for _, tt := range tests {
+		if got := FiscalYear(tt.at); got != tt.want {
+			t.Errorf("%s: FiscalYear(%v) = %d, want %d", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestFiscalYearLabel(t *testing.T) {
+	for year, want := range map[int]string{2026: "2026-27", 2099: "2099-00", 2009: "2009-10"} {
+		if got := FiscalYearLabel(year); got != want {
+			t.Errorf("FiscalYearLabel(%d) = %s, want %s", year, got, want)
+		}
+	}
+}
+
+func TestStateCode(t *testing.T) {
+	tests := []struct {
+		state string
+		code  string
+	}{
+		{"29", "29"},
+		{"7", "07"},
+		{"Karnataka", "29"},
+		{"  tamil   NADU ", "33"},
+		{"Orissa", "21"},
+		{"Pondicherry", "34"},
+		{"Uttaranchal", "05"},
+		{"New Delhi", "07"},
+		{"NCT of Delhi", "07"},
+		{"Daman & Diu", "26"},
+		{"Dadra and Nagar Haveli", "26"},
+		{"Andaman & Nicobar", "35"},
+		{"Andaman & Nicobar Islands", "35"},
+		{"J&K", "01"},
+		{"Jammu & Kashmir", "01"},
+	}
+
+	for _, tt := range tests {
+		code, ok := StateCode(tt.state)
+		if !ok || code != tt.code {
+			t.Errorf("StateCode(%q) = %q, %v; want %q", tt.state, code, ok, tt.code)
+		}
+	}
+}
+
+func TestStateCodeUnknown(t *testing.T) {
+	for _, state := range []string{"", " - ", "25", "28", "99", "0", "Atlantis"} {
+		if code, ok := StateCode(state); ok {
+			t.Errorf("StateCode(%q) = %q, want not found", state, code)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// internal/invoicing/render.go
+package invoicing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"backend/pkg/pdf"
+)
+
+// Page layout in points
+const (
+	margin     = 40.0
+	pageRight  = pdf.A4Width - margin
+	bodyWidth  = pageRight - margin
+	bodySize   = 9.5
+	lineHeight = 13.0
+)
+
+// RenderPDF lays out an invoice or credit note as a one page A4 PDF
+func RenderPDF(invoice *models.Invoice) ([]byte, error) {
+	title := "TAX INVOICE"
+	if invoice.Kind == INVOICE_KIND_CREDIT_NOTE {
+		title = "CREDIT NOTE"
+	}
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle(title + " " + invoice.Number)
+	page := doc.AddPage()
+
+	// heading with the document's number and date on the right
+	y := margin + 18
+	page.Text(margin, y, pdf.HelveticaBold, 18, title)
+	page.TextRight(pageRight, y-6, pdf.HelveticaBold, 10, invoice.Number)
+	page.TextRight(pageRight, y+7, pdf.Helvetica, bodySize, "Date: "+invoice.IssuedAt.In(IST).Format("02 Jan 2006"))
+	y += 14
+	page.Line(margin, y, pageRight, y, 1)
+
+	// seller on the left, buyer on the right
+	column := bodyWidth/2 - 10
+	buyerX := margin + bodyWidth/2 + 10
+	top := y + 20
+	seller := []string{}
+	seller = append(seller, pdf.Wrap(pdf.Helvetica, bodySize, invoice.SellerAddress, column)...)
+	if invoice.SellerGSTIN != "" {
+		seller = append(seller, "GSTIN: "+invoice.SellerGSTIN)
+	}
+	seller = append(seller, "State: "+stateLabel(invoice.SellerStateCode))
+	buyer := []string{}
+	if invoice.BuyerEmail != "" {
+		buyer = append(buyer, invoice.BuyerEmail)
+	}
+	if invoice.BuyerMobile != "" {
+		buyer = append(buyer, invoice.BuyerMobile)
+	}
+	buyer = append(buyer, "Place of supply: "+stateLabel(invoice.PlaceOfSupply))
+
+	page.Text(margin, top, pdf.HelveticaBold, 8, "SOLD BY")
+	page.Text(buyerX, top, pdf.HelveticaBold, 8, "BILLED TO")
+	page.Text(margin, top+lineHeight+2, pdf.HelveticaBold, 11, invoice.SellerName)
+	page.Text(buyerX, top+lineHeight+2, pdf.HelveticaBold, 11, invoice.BuyerName)
+	left := writeLines(page, margin, top+2*lineHeight+4, seller)
+	right := writeLines(page, buyerX, top+2*lineHeight+4, buyer)
+	y = max(left, right) + 6
+
+	if invoice.Kind == INVOICE_KIND_CREDIT_NOTE {
+		page.Text(margin, y, pdf.Helvetica, bodySize, "Issued against invoice "+invoice.OriginalNumber+" for a refund of order #"+strconv.FormatUint(uint64(invoice.OrderID), 10))
+	} else {
+		page.Text(margin, y, pdf.Helvetica, bodySize, "Order #"+strconv.FormatUint(uint64(invoice.OrderID), 10))
+	}
+	y += 2 * lineHeight
+
+	// the supply, one line
+	sacX := margin + bodyWidth*0.62
+	page.FillRect(margin, y-11, bodyWidth, 17, 0.9)
+	page.Text(margin+6, y, pdf.HelveticaBold, bodySize, "Description")
+	page.Text(sacX, y, pdf.HelveticaBold, bodySize, "SAC")
+	page.TextRight(pageRight-6, y, pdf.HelveticaBold, bodySize, "Taxable value")
+	y += lineHeight + 6
+	description := pdf.Wrap(pdf.Helvetica, bodySize, invoice.Description, sacX-margin-16)
+	page.Text(sacX, y, pdf.Helvetica, bodySize, invoice.SAC)
+	page.TextRight(pageRight-6, y, pdf.Helvetica, bodySize, FormatMinor(invoice.TaxableMinor, invoice.Currency))
+	y = writeLines(page, margin+6, y, description)
+	page.Line(margin, y, pageRight, y, 0.5)
+	y += lineHeight + 4
+
+	// totals, right aligned under the amount column
+	labelX := margin + bodyWidth*0.55
+	total := func(label, amount string, font pdf.Font) {
+		page.Text(labelX, y, font, bodySize, label)
+		page.TextRight(pageRight-6, y, font, bodySize, amount)
+		y += lineHeight + 2
+	}
+	total("Taxable value", FormatMinor(invoice.TaxableMinor, invoice.Currency), pdf.Helvetica)
+	if invoice.Interstate() {
+		total("IGST @ "+rate(invoice.TaxRateBps, 1), FormatMinor(invoice.IGSTMinor, invoice.Currency), pdf.Helvetica)
+	} else {
+		total("CGST @ "+rate(invoice.TaxRateBps, 2), FormatMinor(invoice.CGSTMinor, invoice.Currency), pdf.Helvetica)
+		total("SGST @ "+rate(invoice.TaxRateBps, 2), FormatMinor(invoice.SGSTMinor, invoice.Currency), pdf.Helvetica)
+	}
+	page.Line(labelX, y-9, pageRight, y-9, 0.5)
+	y += 3
+	label := "Total"
+	if invoice.Kind == INVOICE_KIND_CREDIT_NOTE {
+		label = "Total credited"
+	}
+	total(label, FormatMinor(invoice.TotalMinor, invoice.Currency), pdf.HelveticaBold)
+
+	page.Line(margin, pdf.A4Height-margin-14, pageRight, pdf.A4Height-margin-14, 0.5)
+	page.Text(margin, pdf.A4Height-margin, pdf.Helvetica, 8, "This is a computer generated document and needs no signature.")
+	return doc.Bytes()
+}
+
+// FormatMinor formats an amount in minor units with its currency, grouping
+// the rupees the Indian way: INR 1,23,456.78
+func FormatMinor(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	whole := strconv.FormatInt(amount/100, 10)
+	if len(whole) > 3 {
+		head, tail := whole[:len(whole)-3], whole[len(whole)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		whole = strings.Join(append(append([]string{head}, groups...), tail), ",")
+	}
+	return fmt.Sprintf("%s %s%s.%02d", currency, sign, whole, amount%100)
+}
+
+// rate formats a share of a tax rate given in basis points, 9% or 2.5%
+func rate(bps, parts int) string {
+	return strconv.FormatFloat(float64(bps)/float64(100*parts), 'f', -1, 64) + "%"
+}
+
+// stateLabel names a state with its GST code, Karnataka (29)
+func stateLabel(code string) string {
+	if name := StateName(code); name != "" {
+		return name + " (" + code + ")"
+	}
+	return code
+}
+
+// writeLines writes lines downwards from y and returns the y below the last
+func writeLines(page *pdf.Page, x, y float64, lines []string) float64 {
+	for _, line := range lines {
+		page.Text(x, y, pdf.Helvetica, bodySize, line)
+		y += lineHeight
+	}
+	return y
+}
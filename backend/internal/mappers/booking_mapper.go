@@ -0,0 +1,75 @@
+// internal/mappers/booking_mapper.go
+package mappers
+
+import (
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToClassBookingDTO - Converts a class booking to a booking DTO.
+func ToClassBookingDTO(booking *models.ClassBooking) dtos.ClassBookingDTO {
+	return dtos.ClassBookingDTO{
+		ID:           booking.ID,
+		SessionID:    booking.SessionID,
+		CustomerID:   booking.CustomerID,
+		CustomerName: strings.TrimSpace(booking.Customer.FirstName + " " + booking.Customer.LastName),
+		Status:       string(booking.Status),
+		BookedAt:     booking.BookedAt,
+		WaitlistedAt: booking.WaitlistedAt,
+		CancelledAt:  booking.CancelledAt,
+		CreatedAt:    booking.CreatedAt,
+	}
+}
+
+// ToClassBookingDTOs - Converts a slice of class bookings to booking DTOs.
+func ToClassBookingDTOs(bookings []models.ClassBooking) []dtos.ClassBookingDTO {
+	bookingDTOs := make([]dtos.ClassBookingDTO, 0, len(bookings))
+	for i := range bookings {
+		bookingDTOs = append(bookingDTOs, ToClassBookingDTO(&bookings[i]))
+	}
+	return bookingDTOs
+}
+
+// ToMyBookingDTOs - Converts a slice of bookings with their sessions to customer booking DTOs.
+func ToMyBookingDTOs(bookings []models.ClassBooking) []dtos.MyBookingDTO {
+	bookingDTOs := make([]dtos.MyBookingDTO, 0, len(bookings))
+	for i := range bookings {
+		bookingDTOs = append(bookingDTOs, dtos.MyBookingDTO{
+			ClassBookingDTO: ToClassBookingDTO(&bookings[i]),
+			Session:         ToClassSessionDTO(&bookings[i].Session),
+		})
+	}
+	return bookingDTOs
+}
+
+// ToBookableSessionDTOs - Converts sessions and their occupancy to bookable session DTOs.
+func ToBookableSessionDTOs(sessions []models.ClassSession, occupancy map[uint]models.SessionOccupancy) []dtos.BookableSessionDTO {
+	sessionDTOs := make([]dtos.BookableSessionDTO, 0, len(sessions))
+	for i := range sessions {
+		taken := occupancy[sessions[i].ID]
+		spotsLeft := sessions[i].Capacity - taken.Booked
+		if spotsLeft < 0 {
+			spotsLeft = 0
+		}
+		sessionDTOs = append(sessionDTOs, dtos.BookableSessionDTO{
+			ClassSessionDTO: ToClassSessionDTO(&sessions[i]),
+			SpotsLeft:       spotsLeft,
+			Waitlisted:      taken.Waitlisted,
+		})
+	}
+	return sessionDTOs
+}
+
+// ToBookingPolicyDTO - Converts a booking policy to a policy DTO.
+func ToBookingPolicyDTO(policy *models.BookingPolicy) dtos.BookingPolicyDTO {
+	return dtos.BookingPolicyDTO{
+		AllieID:             policy.AllieID,
+		BookingWindowDays:   policy.BookingWindowDays,
+		CancelCutoffMinutes: policy.CancelCutoffMinutes,
+		MaxStrikes:          policy.MaxStrikes,
+		StrikeWindowDays:    policy.StrikeWindowDays,
+		IsDefault:           policy.ID == 0,
+	}
+}
@@ -0,0 +1,52 @@
+// internal/mappers/check_in_mapper.go
+package mappers
+
+import (
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToCrewVisitDTO - Converts a crew visit to a visit DTO.
+func ToCrewVisitDTO(visit *models.CrewVisit) dtos.CrewVisitDTO {
+	return dtos.CrewVisitDTO{
+		ID:           visit.ID,
+		CrewID:       visit.CrewID,
+		CustomerID:   visit.CustomerID,
+		CustomerName: strings.TrimSpace(visit.Customer.FirstName + " " + visit.Customer.LastName),
+		VisitDate:    visit.VisitDate.Format(time.DateOnly),
+		CheckedInAt:  visit.CheckedInAt,
+		CheckedOutAt: visit.CheckedOutAt,
+		Method:       string(visit.Method),
+		AutoClosed:   visit.AutoClosed,
+	}
+}
+
+// ToCrewVisitDTOs - Converts a slice of crew visits to visit DTOs.
+func ToCrewVisitDTOs(visits []models.CrewVisit) []dtos.CrewVisitDTO {
+	visitDTOs := make([]dtos.CrewVisitDTO, 0, len(visits))
+	for i := range visits {
+		visitDTOs = append(visitDTOs, ToCrewVisitDTO(&visits[i]))
+	}
+	return visitDTOs
+}
+
+// ToCrewOccupancyDTO - Converts a crew and the visits inside it to an occupancy DTO.
+func ToCrewOccupancyDTO(crew *models.FitCrew, visits []models.CrewVisit) dtos.CrewOccupancyDTO {
+	occupancyDTO := dtos.CrewOccupancyDTO{
+		CrewID:    crew.ID,
+		Capacity:  crew.Capacity,
+		Occupancy: len(visits),
+		Visitors:  ToCrewVisitDTOs(visits),
+	}
+	if crew.Capacity > 0 {
+		spotsLeft := crew.Capacity - len(visits)
+		if spotsLeft < 0 {
+			spotsLeft = 0
+		}
+		occupancyDTO.SpotsLeft = &spotsLeft
+	}
+	return occupancyDTO
+}
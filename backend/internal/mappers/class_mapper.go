@@ -0,0 +1,77 @@
+// internal/mappers/class_mapper.go
+package mappers
+
+import (
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToClassTemplateDTO - Converts a class template to a class DTO.
+func ToClassTemplateDTO(template *models.ClassTemplate) dtos.ClassTemplateDTO {
+	weekdays := make([]string, 0, 7)
+	for _, day := range template.Days() {
+		weekdays = append(weekdays, string(day))
+	}
+
+	templateDTO := dtos.ClassTemplateDTO{
+		ID:              template.ID,
+		CrewID:          template.CrewID,
+		Service:         ToFitServiceDTO(&template.FitService),
+		TrainerID:       template.TrainerID,
+		TrainerName:     template.Trainer.FullName,
+		Name:            template.Name,
+		Description:     template.Description,
+		Weekdays:        weekdays,
+		StartTime:       template.StartTime,
+		DurationMinutes: template.DurationMinutes,
+		Room:            template.Room,
+		Capacity:        template.Capacity,
+		StartsOn:        template.StartsOn.Format(time.DateOnly),
+		IsActive:        template.IsActive,
+	}
+	if template.EndsOn != nil {
+		endsOn := template.EndsOn.Format(time.DateOnly)
+		templateDTO.EndsOn = &endsOn
+	}
+	return templateDTO
+}
+
+// ToClassTemplateDTOs - Converts a slice of class templates to class DTOs.
+func ToClassTemplateDTOs(templates []models.ClassTemplate) []dtos.ClassTemplateDTO {
+	templateDTOs := make([]dtos.ClassTemplateDTO, 0, len(templates))
+	for i := range templates {
+		templateDTOs = append(templateDTOs, ToClassTemplateDTO(&templates[i]))
+	}
+	return templateDTOs
+}
+
+// ToClassSessionDTO - Converts a class session to a session DTO.
+func ToClassSessionDTO(session *models.ClassSession) dtos.ClassSessionDTO {
+	return dtos.ClassSessionDTO{
+		ID:           session.ID,
+		TemplateID:   session.TemplateID,
+		CrewID:       session.CrewID,
+		Service:      ToFitServiceDTO(&session.FitService),
+		TrainerID:    session.TrainerID,
+		TrainerName:  session.Trainer.FullName,
+		Name:         session.Name,
+		Room:         session.Room,
+		Capacity:     session.Capacity,
+		StartsAt:     session.StartsAt,
+		EndsAt:       session.EndsAt,
+		Status:       string(session.Status),
+		Modified:     session.Modified,
+		CancelReason: session.CancelReason,
+	}
+}
+
+// ToClassSessionDTOs - Converts a slice of class sessions to session DTOs.
+func ToClassSessionDTOs(sessions []models.ClassSession) []dtos.ClassSessionDTO {
+	sessionDTOs := make([]dtos.ClassSessionDTO, 0, len(sessions))
+	for i := range sessions {
+		sessionDTOs = append(sessionDTOs, ToClassSessionDTO(&sessions[i]))
+	}
+	return sessionDTOs
+}
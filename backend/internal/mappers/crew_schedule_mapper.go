@@ -0,0 +1,46 @@
+// internal/mappers/crew_schedule_mapper.go
+package mappers
+
+import (
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToCrewScheduleDTO - Converts a crew with its loaded hours and holidays to a schedule DTO.
+func ToCrewScheduleDTO(crew *models.FitCrew, now time.Time) dtos.CrewScheduleDTO {
+	hours := make([]dtos.OpeningHourDTO, 0, len(crew.OpeningHours))
+	for _, hour := range crew.OpeningHours {
+		hours = append(hours, dtos.OpeningHourDTO{
+			Weekday:  string(hour.Weekday),
+			OpensAt:  hour.OpensAt,
+			ClosesAt: hour.ClosesAt,
+		})
+	}
+
+	holidays := make([]dtos.CrewHolidayDTO, 0, len(crew.Holidays))
+	for i := range crew.Holidays {
+		holidays = append(holidays, ToCrewHolidayDTO(&crew.Holidays[i]))
+	}
+
+	return dtos.CrewScheduleDTO{
+		CrewID:       crew.ID,
+		Timezone:     crew.Timezone,
+		IsOpenNow:    crew.OpenAt(now),
+		OpeningHours: hours,
+		Holidays:     holidays,
+	}
+}
+
+// ToCrewHolidayDTO - Converts a crew holiday to a holiday DTO.
+func ToCrewHolidayDTO(holiday *models.CrewHoliday) dtos.CrewHolidayDTO {
+	return dtos.CrewHolidayDTO{
+		ID:       holiday.ID,
+		FromDate: holiday.FromDate.Format(time.DateOnly),
+		ToDate:   holiday.ToDate.Format(time.DateOnly),
+		Name:     holiday.Name,
+		OpensAt:  holiday.OpensAt,
+		ClosesAt: holiday.ClosesAt,
+	}
+}
@@ -0,0 +1,75 @@
+// internal/mappers/customer_mapper.go
+package mappers
+
+import (
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToCustomerDTO - Converts a customer model to a customer DTO. The activity
+// fields are derived from the membership dates at now.
+func ToCustomerDTO(customer *models.Customer, now time.Time) dtos.CustomerDTO {
+	customerDTO := dtos.CustomerDTO{
+		ID:               customer.ID,
+		UserID:           customer.UserID,
+		CrewID:           customer.CrewID,
+		FirstName:        customer.FirstName,
+		MiddleName:       customer.MiddleName,
+		LastName:         customer.LastName,
+		Email:            customer.Email,
+		Mobile:           customer.Mobile,
+		AlternateMobile:  customer.AlternateMobile,
+		State:            customer.State,
+		MembershipStart:  customer.MembershipStart,
+		MembershipEnd:    customer.MembershipEnd,
+		FrozenFrom:       customer.FrozenFrom,
+		FrozenUntil:      customer.FrozenUntil,
+		CancelledAt:      customer.CancelledAt,
+		PlanID:           customer.PlanID,
+		FreezeDaysLeft:   customer.FreezeDaysAllowed - customer.FreezeDaysUsed,
+		VisitLimit:       customer.VisitLimit,
+		VisitsPerDay:     customer.VisitsPerDay,
+		MembershipStatus: string(customer.MembershipStatusAt(now)),
+		IsActive:         customer.MembershipActiveAt(now),
+	}
+	if !customer.DateOfBirth.IsZero() {
+		customerDTO.DateOfBirth = &customer.DateOfBirth
+	}
+	return customerDTO
+}
+
+// ToCustomerDTOs - Converts a slice of customer models to customer DTOs.
+func ToCustomerDTOs(customers []models.Customer, now time.Time) []dtos.CustomerDTO {
+	customerDTOs := make([]dtos.CustomerDTO, 0, len(customers))
+	for i := range customers {
+		customerDTOs = append(customerDTOs, ToCustomerDTO(&customers[i], now))
+	}
+	return customerDTOs
+}
+
+// ToMembershipHistoryDTOs - Converts membership history entries to DTOs.
+func ToMembershipHistoryDTOs(history []models.MembershipHistory) []dtos.MembershipHistoryDTO {
+	historyDTOs := make([]dtos.MembershipHistoryDTO, 0, len(history))
+	for _, entry := range history {
+		historyDTOs = append(historyDTOs, dtos.MembershipHistoryDTO{
+			ID:          entry.ID,
+			Action:      string(entry.Action),
+			FromCrewID:  entry.FromCrewID,
+			ToCrewID:    entry.ToCrewID,
+			PreviousEnd: entry.PreviousEnd,
+			NewEnd:      entry.NewEnd,
+			Days:        entry.Days,
+			Note:        entry.Note,
+			PlanID:      entry.PlanID,
+			PlanName:    entry.PlanName,
+			PriceMinor:  entry.PriceMinor,
+			TaxMinor:    entry.TaxMinor,
+			Currency:    entry.Currency,
+			PerformedBy: entry.PerformedBy,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	return historyDTOs
+}
@@ -0,0 +1,43 @@
+// internal/mappers/fit_allie_mapper.go
+package mappers
+
+import (
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToFitAllieDTO - Converts an allie model to an allie DTO.
+func ToFitAllieDTO(allie *models.FitAllie) dtos.FitAllieDTO {
+	return dtos.FitAllieDTO{
+		ID:                allie.ID,
+		UserID:            allie.UserID,
+		BusinessName:      allie.BusinessName,
+		OwnerFirstName:    allie.OwnerFirstName,
+		OwnerMiddleName:   allie.OwnerMiddleName,
+		OwnerLastName:     allie.OwnerLastName,
+		CoOwnerFirstName:  allie.CoOwnerFirstName,
+		CoOwnerMiddleName: allie.CoOwnerMiddleName,
+		CoOwnerLastName:   allie.CoOwnerLastName,
+		Email:             allie.Email,
+		Mobile:            allie.Mobile,
+		AlternateMobile:   allie.AlternateMobile,
+		CommissionRate:    allie.CommissionRate,
+		NoOfBranch:        allie.NoOfBranch,
+		IsActive:          allie.IsActive,
+		Address:           allie.Address,
+		City:              allie.City,
+		State:             allie.State,
+		PinCode:           allie.PinCode,
+		GSTIN:             allie.GSTIN,
+		CreatedAt:         allie.CreatedAt,
+	}
+}
+
+// ToFitAllieDTOs - Converts a slice of allie models to allie DTOs.
+func ToFitAllieDTOs(allies []models.FitAllie) []dtos.FitAllieDTO {
+	allieDTOs := make([]dtos.FitAllieDTO, 0, len(allies))
+	for i := range allies {
+		allieDTOs = append(allieDTOs, ToFitAllieDTO(&allies[i]))
+	}
+	return allieDTOs
+}
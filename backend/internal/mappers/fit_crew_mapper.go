@@ -0,0 +1,80 @@
+// internal/mappers/fit_crew_mapper.go
+package mappers
+
+import (
+	"math"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToFitCrewDTO - Converts a crew model to a crew DTO.
+func ToFitCrewDTO(crew *models.FitCrew) dtos.FitCrewDTO {
+	return dtos.FitCrewDTO{
+		ID:                crew.ID,
+		AllieID:           crew.AllieID,
+		GymName:           crew.GymName,
+		ManagerFirstName:  crew.ManagerFirstName,
+		ManagerMiddleName: crew.ManagerMiddleName,
+		ManagerLastName:   crew.ManagerLastName,
+		Email:             crew.Email,
+		Mobile:            crew.Mobile,
+		AlternateMobile:   crew.AlternateMobile,
+		IsActive:          crew.IsActive,
+		Address:           crew.Address,
+		City:              crew.City,
+		State:             crew.State,
+		PinCode:           crew.PinCode,
+		Lat:               crew.Lat,
+		Long:              crew.Long,
+		Capacity:          crew.Capacity,
+		Timezone:          crew.Timezone,
+	}
+}
+
+// ToFitCrewDTOs - Converts a slice of crew models to crew DTOs.
+func ToFitCrewDTOs(crews []models.FitCrew) []dtos.FitCrewDTO {
+	crewDTOs := make([]dtos.FitCrewDTO, 0, len(crews))
+	for i := range crews {
+		crewDTOs = append(crewDTOs, ToFitCrewDTO(&crews[i]))
+	}
+	return crewDTOs
+}
+
+// ToPublicFitCrewDTO - Converts a crew to the view shown to customers.
+func ToPublicFitCrewDTO(crew *models.FitCrew) dtos.PublicFitCrewDTO {
+	return dtos.PublicFitCrewDTO{
+		ID:       crew.ID,
+		AllieID:  crew.AllieID,
+		GymName:  crew.GymName,
+		Mobile:   crew.Mobile,
+		Address:  crew.Address,
+		City:     crew.City,
+		State:    crew.State,
+		PinCode:  crew.PinCode,
+		Lat:      crew.Lat,
+		Long:     crew.Long,
+		Timezone: crew.Timezone,
+	}
+}
+
+// ToPublicFitCrewDTOs - Converts crews to the view shown to customers.
+func ToPublicFitCrewDTOs(crews []models.FitCrew) []dtos.PublicFitCrewDTO {
+	crewDTOs := make([]dtos.PublicFitCrewDTO, 0, len(crews))
+	for i := range crews {
+		crewDTOs = append(crewDTOs, ToPublicFitCrewDTO(&crews[i]))
+	}
+	return crewDTOs
+}
+
+// ToNearbyFitCrewDTOs - Converts crews found by the nearby search, keeping their distance.
+func ToNearbyFitCrewDTOs(crews []models.FitCrewDistance) []dtos.NearbyFitCrewDTO {
+	crewDTOs := make([]dtos.NearbyFitCrewDTO, 0, len(crews))
+	for i := range crews {
+		crewDTOs = append(crewDTOs, dtos.NearbyFitCrewDTO{
+			PublicFitCrewDTO: ToPublicFitCrewDTO(&crews[i].FitCrew),
+			DistanceKm:       math.Round(crews[i].DistanceKm*100) / 100,
+		})
+	}
+	return crewDTOs
+}
@@ -0,0 +1,50 @@
+// internal/mappers/fit_service_mapper.go
+package mappers
+
+import (
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToFitServiceDTO - Converts a catalog service model to a service DTO.
+func ToFitServiceDTO(service *models.FitService) dtos.FitServiceDTO {
+	return dtos.FitServiceDTO{
+		ID:          service.ID,
+		Name:        service.Name,
+		Category:    service.Category,
+		Icon:        service.Icon,
+		Description: service.Description,
+		IsActive:    service.IsActive,
+	}
+}
+
+// ToFitServiceDTOs - Converts service models to service DTOs.
+func ToFitServiceDTOs(services []models.FitService) []dtos.FitServiceDTO {
+	serviceDTOs := make([]dtos.FitServiceDTO, 0, len(services))
+	for i := range services {
+		serviceDTOs = append(serviceDTOs, ToFitServiceDTO(&services[i]))
+	}
+	return serviceDTOs
+}
+
+// ToOfferingDTO - Converts an allie offering to an offering DTO.
+func ToOfferingDTO(offering *models.FitAllieService) dtos.OfferingDTO {
+	return dtos.OfferingDTO{
+		ID:         offering.ID,
+		AllieID:    offering.FitAllieID,
+		CrewID:     offering.CrewID,
+		Service:    ToFitServiceDTO(&offering.FitService),
+		PriceMinor: offering.PriceMinor,
+		Currency:   offering.Currency,
+		IsActive:   offering.IsActive,
+	}
+}
+
+// ToOfferingDTOs - Converts a slice of allie offerings to offering DTOs.
+func ToOfferingDTOs(offerings []models.FitAllieService) []dtos.OfferingDTO {
+	offeringDTOs := make([]dtos.OfferingDTO, 0, len(offerings))
+	for i := range offerings {
+		offeringDTOs = append(offeringDTOs, ToOfferingDTO(&offerings[i]))
+	}
+	return offeringDTOs
+}
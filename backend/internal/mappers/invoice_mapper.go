@@ -0,0 +1,48 @@
+// internal/mappers/invoice_mapper.go
+package mappers
+
+import (
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/models"
+)
+
+// ToInvoiceDTO - Converts an invoice or credit note to an invoice DTO.
+func ToInvoiceDTO(invoice *models.Invoice) dtos.InvoiceDTO {
+	return dtos.InvoiceDTO{
+		ID:              invoice.ID,
+		Number:          invoice.Number,
+		Kind:            string(invoice.Kind),
+		FiscalYear:      invoicing.FiscalYearLabel(invoice.FiscalYear),
+		IssuedAt:        invoice.IssuedAt,
+		OrderID:         invoice.OrderID,
+		RefundID:        invoice.RefundID,
+		OriginalNumber:  invoice.OriginalNumber,
+		CrewID:          invoice.CrewID,
+		CustomerID:      invoice.CustomerID,
+		SellerName:      invoice.SellerName,
+		SellerAddress:   invoice.SellerAddress,
+		SellerGSTIN:     invoice.SellerGSTIN,
+		SellerStateCode: invoice.SellerStateCode,
+		BuyerName:       invoice.BuyerName,
+		PlaceOfSupply:   invoice.PlaceOfSupply,
+		Description:     invoice.Description,
+		SAC:             invoice.SAC,
+		TaxRateBps:      invoice.TaxRateBps,
+		TaxableMinor:    invoice.TaxableMinor,
+		CGSTMinor:       invoice.CGSTMinor,
+		SGSTMinor:       invoice.SGSTMinor,
+		IGSTMinor:       invoice.IGSTMinor,
+		TotalMinor:      invoice.TotalMinor,
+		Currency:        invoice.Currency,
+	}
+}
+
+// ToInvoiceDTOs - Converts a slice of invoices and credit notes to invoice DTOs.
+func ToInvoiceDTOs(invoices []models.Invoice) []dtos.InvoiceDTO {
+	invoiceDTOs := make([]dtos.InvoiceDTO, 0, len(invoices))
+	for i := range invoices {
+		invoiceDTOs = append(invoiceDTOs, ToInvoiceDTO(&invoices[i]))
+	}
+	return invoiceDTOs
+}
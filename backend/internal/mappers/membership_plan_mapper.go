@@ -0,0 +1,37 @@
+// internal/mappers/membership_plan_mapper.go
+package mappers
+
+import (
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToMembershipPlanDTO - Converts a plan model to a plan DTO.
+func ToMembershipPlanDTO(plan *models.MembershipPlan) dtos.MembershipPlanDTO {
+	return dtos.MembershipPlanDTO{
+		ID:           plan.ID,
+		AllieID:      plan.AllieID,
+		CrewID:       plan.CrewID,
+		Name:         plan.Name,
+		Description:  plan.Description,
+		DurationDays: plan.DurationDays,
+		PriceMinor:   plan.PriceMinor,
+		TaxMinor:     plan.TaxMinor(),
+		Currency:     plan.Currency,
+		TaxRateBps:   plan.TaxRateBps,
+		FreezeDays:   plan.FreezeDays,
+		VisitLimit:   plan.VisitLimit,
+		VisitsPerDay: plan.VisitsPerDay,
+		IsActive:     plan.IsActive,
+		Services:     ToFitServiceDTOs(plan.Services),
+	}
+}
+
+// ToMembershipPlanDTOs - Converts a slice of plan models to plan DTOs.
+func ToMembershipPlanDTOs(plans []models.MembershipPlan) []dtos.MembershipPlanDTO {
+	planDTOs := make([]dtos.MembershipPlanDTO, 0, len(plans))
+	for i := range plans {
+		planDTOs = append(planDTOs, ToMembershipPlanDTO(&plans[i]))
+	}
+	return planDTOs
+}
@@ -0,0 +1,96 @@
+// internal/mappers/menu_mapper.go
+package mappers
+
+import (
+	"strconv"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToMenuRouteTree builds the admin panel route tree. Menus must be in display
+// order; entries whose parent is not in the slice are left out.
+func ToMenuRouteTree(menus []models.Menu) []dtos.MenuRouteDTO {
+	children := make(map[uint][]models.Menu)
+	var roots []models.Menu
+	for _, menu := range menus {
+		if menu.ParentID == nil {
+			roots = append(roots, menu)
+		} else {
+			children[*menu.ParentID] = append(children[*menu.ParentID], menu)
+		}
+	}
+
+	var build func(level []models.Menu) []dtos.MenuRouteDTO
+	build = func(level []models.Menu) []dtos.MenuRouteDTO {
+		routes := make([]dtos.MenuRouteDTO, 0, len(level))
+		for _, menu := range level {
+			routes = append(routes, dtos.MenuRouteDTO{
+				ID:        strconv.FormatUint(uint64(menu.ID), 10),
+				Name:      menu.Name,
+				Path:      menu.Path,
+				Component: menu.Component,
+				Meta: dtos.MenuRouteMeta{
+					Title:      menu.Title,
+					I18nKey:    menu.I18nKey,
+					Icon:       menu.Icon,
+					Order:      menu.Order,
+					Constant:   menu.Constant,
+					HideInMenu: menu.HideInMenu,
+					KeepAlive:  menu.KeepAlive,
+				},
+				Children: build(children[menu.ID]),
+			})
+		}
+		if len(routes) == 0 {
+			return nil
+		}
+		return routes
+	}
+	return build(roots)
+}
+
+// ToMenuDTO - Converts a menu model to a menu DTO without children.
+func ToMenuDTO(menu *models.Menu) dtos.MenuDTO {
+	return dtos.MenuDTO{
+		ID:         menu.ID,
+		ParentID:   menu.ParentID,
+		Name:       menu.Name,
+		Path:       menu.Path,
+		Component:  menu.Component,
+		Title:      menu.Title,
+		I18nKey:    menu.I18nKey,
+		Icon:       menu.Icon,
+		Order:      menu.Order,
+		Permission: menu.Permission,
+		Constant:   menu.Constant,
+		HideInMenu: menu.HideInMenu,
+		KeepAlive:  menu.KeepAlive,
+		IsActive:   menu.IsActive,
+	}
+}
+
+// ToMenuDTOTree - Converts menus in display order to a tree of menu DTOs.
+func ToMenuDTOTree(menus []models.Menu) []dtos.MenuDTO {
+	children := make(map[uint][]models.Menu)
+	var roots []models.Menu
+	for _, menu := range menus {
+		if menu.ParentID == nil {
+			roots = append(roots, menu)
+		} else {
+			children[*menu.ParentID] = append(children[*menu.ParentID], menu)
+		}
+	}
+
+	var build func(level []models.Menu) []dtos.MenuDTO
+	build = func(level []models.Menu) []dtos.MenuDTO {
+		var nodes []dtos.MenuDTO
+		for i := range level {
+			node := ToMenuDTO(&level[i])
+			node.Children = build(children[level[i].ID])
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(roots)
+}
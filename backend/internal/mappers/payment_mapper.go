@@ -0,0 +1,85 @@
+// internal/mappers/payment_mapper.go
+package mappers
+
+import (
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToPaymentOrderDTO - Converts a payment order, with any loaded attempts and refunds, to an order DTO.
+func ToPaymentOrderDTO(order *models.PaymentOrder) dtos.PaymentOrderDTO {
+	orderDTO := dtos.PaymentOrderDTO{
+		ID:            order.ID,
+		CustomerID:    order.CustomerID,
+		CustomerName:  strings.TrimSpace(order.Customer.FirstName + " " + order.Customer.LastName),
+		CrewID:        order.CrewID,
+		Status:        string(order.Status),
+		PlanID:        order.PlanID,
+		PlanName:      order.PlanName,
+		DurationDays:  order.DurationDays,
+		PriceMinor:    order.PriceMinor,
+		TaxMinor:      order.TaxMinor,
+		AmountMinor:   order.AmountMinor,
+		RefundedMinor: order.RefundedMinor,
+		Currency:      order.Currency,
+		PaidAt:        order.PaidAt,
+		CreatedAt:     order.CreatedAt,
+	}
+	for _, attempt := range order.Attempts {
+		orderDTO.Attempts = append(orderDTO.Attempts, dtos.PaymentAttemptDTO{
+			ID:            attempt.ID,
+			Reference:     attempt.Reference,
+			Gateway:       attempt.Gateway,
+			Method:        attempt.Method,
+			AmountMinor:   attempt.AmountMinor,
+			Status:        string(attempt.Status),
+			FailureReason: attempt.FailureReason,
+			CapturedAt:    attempt.CapturedAt,
+			CreatedAt:     attempt.CreatedAt,
+		})
+	}
+	for _, refund := range order.Refunds {
+		orderDTO.Refunds = append(orderDTO.Refunds, dtos.PaymentRefundDTO{
+			ID:            refund.ID,
+			Reference:     refund.Reference,
+			AmountMinor:   refund.AmountMinor,
+			Status:        string(refund.Status),
+			Reason:        refund.Reason,
+			FailureReason: refund.FailureReason,
+			RefundedAt:    refund.RefundedAt,
+			CreatedAt:     refund.CreatedAt,
+		})
+	}
+	return orderDTO
+}
+
+// ToPaymentOrderDTOs - Converts a slice of payment orders to order DTOs.
+func ToPaymentOrderDTOs(orders []models.PaymentOrder) []dtos.PaymentOrderDTO {
+	orderDTOs := make([]dtos.PaymentOrderDTO, 0, len(orders))
+	for i := range orders {
+		orderDTOs = append(orderDTOs, ToPaymentOrderDTO(&orders[i]))
+	}
+	return orderDTOs
+}
+
+// ToPaymentOrderDetailDTO - Converts a payment order and its ledger entries to an order detail DTO.
+func ToPaymentOrderDetailDTO(order *models.PaymentOrder, entries []models.LedgerEntry) dtos.PaymentOrderDetailDTO {
+	ledger := make([]dtos.LedgerEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		ledger = append(ledger, dtos.LedgerEntryDTO{
+			TransactionRef: entry.TransactionRef,
+			Account:        string(entry.Account),
+			DebitMinor:     entry.DebitMinor,
+			CreditMinor:    entry.CreditMinor,
+			Currency:       entry.Currency,
+			Description:    entry.Description,
+			PostedAt:       entry.PostedAt,
+		})
+	}
+	return dtos.PaymentOrderDetailDTO{
+		PaymentOrderDTO: ToPaymentOrderDTO(order),
+		Ledger:          ledger,
+	}
+}
@@ -0,0 +1,93 @@
+// internal/mappers/settlement_mapper.go
+package mappers
+
+import (
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+)
+
+// ToSettlementStatementDTO - Converts a settlement statement to a statement DTO.
+func ToSettlementStatementDTO(statement *models.SettlementStatement) dtos.SettlementStatementDTO {
+	return dtos.SettlementStatementDTO{
+		ID:               statement.ID,
+		AllieID:          statement.AllieID,
+		PeriodStart:      statement.PeriodStart.In(invoicing.IST).Format(time.DateOnly),
+		PeriodEnd:        statement.PeriodEnd.In(invoicing.IST).AddDate(0, 0, -1).Format(time.DateOnly),
+		Status:           string(statement.Status),
+		CommissionRate:   statement.CommissionRate,
+		Currency:         statement.Currency,
+		GrossMinor:       statement.GrossMinor,
+		TaxMinor:         statement.TaxMinor,
+		RevenueMinor:     statement.RevenueMinor,
+		CommissionMinor:  statement.CommissionMinor,
+		PayoutMinor:      statement.PayoutMinor,
+		ApprovedAt:       statement.ApprovedAt,
+		PaidAt:           statement.PaidAt,
+		PaymentReference: statement.PaymentReference,
+		CreatedAt:        statement.CreatedAt,
+	}
+}
+
+// ToSettlementStatementDTOs - Converts a slice of settlement statements to statement DTOs.
+func ToSettlementStatementDTOs(statements []models.SettlementStatement) []dtos.SettlementStatementDTO {
+	statementDTOs := make([]dtos.SettlementStatementDTO, 0, len(statements))
+	for i := range statements {
+		statementDTOs = append(statementDTOs, ToSettlementStatementDTO(&statements[i]))
+	}
+	return statementDTOs
+}
+
+// ToSettlementLineDTO - Converts a settlement line to a line DTO.
+func ToSettlementLineDTO(line *models.SettlementLine) dtos.SettlementLineDTO {
+	return dtos.SettlementLineDTO{
+		CrewID:          line.CrewID,
+		OrderID:         line.OrderID,
+		TransactionRef:  line.TransactionRef,
+		Kind:            string(line.Kind),
+		PostedAt:        line.PostedAt,
+		CommissionRate:  line.CommissionRate,
+		GrossMinor:      line.GrossMinor,
+		TaxMinor:        line.TaxMinor,
+		RevenueMinor:    line.RevenueMinor,
+		CommissionMinor: line.CommissionMinor,
+		PayoutMinor:     line.PayoutMinor,
+	}
+}
+
+// ToSettlementStatementDetailDTO - Converts a settlement statement with its lines
+// to a detail DTO, summing the lines up per crew in the order crews first appear.
+func ToSettlementStatementDetailDTO(statement *models.SettlementStatement) dtos.SettlementStatementDetailDTO {
+	detail := dtos.SettlementStatementDetailDTO{
+		SettlementStatementDTO: ToSettlementStatementDTO(statement),
+		Crews:                  []dtos.SettlementCrewDTO{},
+		Lines:                  make([]dtos.SettlementLineDTO, 0, len(statement.Lines)),
+	}
+	crews := make(map[int]int)
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		detail.Lines = append(detail.Lines, ToSettlementLineDTO(line))
+
+		index, ok := crews[line.CrewID]
+		if !ok {
+			index = len(detail.Crews)
+			crews[line.CrewID] = index
+			detail.Crews = append(detail.Crews, dtos.SettlementCrewDTO{CrewID: line.CrewID})
+		}
+		crew := &detail.Crews[index]
+		if line.Kind == SETTLEMENT_LINE_REFUND {
+			crew.Refunds++
+		} else {
+			crew.Payments++
+		}
+		crew.GrossMinor += line.GrossMinor
+		crew.TaxMinor += line.TaxMinor
+		crew.RevenueMinor += line.RevenueMinor
+		crew.CommissionMinor += line.CommissionMinor
+		crew.PayoutMinor += line.PayoutMinor
+	}
+	return detail
+}
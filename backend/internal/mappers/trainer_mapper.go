@@ -0,0 +1,52 @@
+// internal/mappers/trainer_mapper.go
+package mappers
+
+import (
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+)
+
+// ToTrainerDTO - Converts a trainer model to a trainer DTO. Experience and
+// certification expiry are computed at now.
+func ToTrainerDTO(trainer *models.TrainerProfile, now time.Time) dtos.TrainerDTO {
+	certifications := make([]dtos.TrainerCertificationDTO, 0, len(trainer.Certifications))
+	for i := range trainer.Certifications {
+		certification := &trainer.Certifications[i]
+		certifications = append(certifications, dtos.TrainerCertificationDTO{
+			ID:           certification.ID,
+			Name:         certification.Name,
+			Issuer:       certification.Issuer,
+			CredentialID: certification.CredentialID,
+			IssuedOn:     certification.IssuedOn,
+			ExpiresOn:    certification.ExpiresOn,
+			Expired:      certification.ExpiredAt(now),
+		})
+	}
+
+	return dtos.TrainerDTO{
+		ID:                trainer.ID,
+		CrewID:            trainer.CrewID,
+		UserID:            trainer.UserID,
+		FullName:          trainer.FullName,
+		Email:             trainer.Email,
+		Mobile:            trainer.Mobile,
+		AlternateMobile:   trainer.AlternateMobile,
+		IsActive:          trainer.IsActive,
+		Visible:           trainer.IsVisible(),
+		ExpStartedFrom:    trainer.ExpStartedFrom,
+		YearsOfExperience: trainer.YearsOfExperienceAt(now),
+		Specializations:   ToFitServiceDTOs(trainer.Specializations),
+		Certifications:    certifications,
+	}
+}
+
+// ToTrainerDTOs - Converts a slice of trainer models to trainer DTOs.
+func ToTrainerDTOs(trainers []models.TrainerProfile, now time.Time) []dtos.TrainerDTO {
+	trainerDTOs := make([]dtos.TrainerDTO, 0, len(trainers))
+	for i := range trainers {
+		trainerDTOs = append(trainerDTOs, ToTrainerDTO(&trainers[i], now))
+	}
+	return trainerDTOs
+}
@@ -3,6 +3,7 @@ package middleware
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 	 "net/http"
@@ -10,17 +11,57 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"backend/internal/logging"
 	"backend/internal/resources/response"
+	. "backend/internal/resources/constants"
+	tokens "backend/pkg/jwt"
 )
 
-var jwtSecret = []byte("PRAN1231SINGH") // Replace with a strong, environment-configured secret
-
 type JWTClaims struct {
-	Data []map[string]string `json:"data"`
+	Data      []map[string]string `json:"data"`
+	TokenType string              `json:"tokenType"`
+	Act       *ActorClaim         `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func AuthMiddleware() gin.HandlerFunc {
+// ActorClaim names the administrator acting on behalf of the token's subject
+// (RFC 8693 "act" claim)
+type ActorClaim struct {
+	Subject  string `json:"sub"`
+	Username string `json:"userName,omitempty"`
+}
+
+// AuthOption relaxes a restriction AuthMiddleware applies by default
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	allowPasswordChangePending bool
+	allowMFAEnrollmentPending  bool
+}
+
+// AllowPasswordChangePending lets through sessions that must change their password
+// first. Use it only on routes such a user needs to comply, like change-password.
+func AllowPasswordChangePending() AuthOption {
+	return func(o *authOptions) {
+		o.allowPasswordChangePending = true
+	}
+}
+
+// AllowMFAEnrollmentPending lets through sessions whose role requires MFA but
+// that have not enrolled yet. Use it only on the enrollment routes.
+func AllowMFAEnrollmentPending() AuthOption {
+	return func(o *authOptions) {
+		o.allowMFAEnrollmentPending = true
+	}
+}
+
+func AuthMiddleware(opts ...AuthOption) gin.HandlerFunc {
+	options := authOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -44,11 +85,53 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// You might want to set user info in the context for later use
+		// Expose the authenticated identity to downstream handlers
 		if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
 			if len(claims.Data) > 0 {
+				userID, err := claims.UserID()
+				if err != nil {
+					response.BadRequestError(c, err.Error())
+					c.Abort()
+					return
+				}
 				c.Set("user", claims.Data[0]["userName"])
+				c.Set("userId", userID)
+				c.Set("role", claims.Role())
+				c.Set("sessionId", claims.SessionID())
+
+				if claims.Act != nil {
+					actorID, err := strconv.ParseUint(claims.Act.Subject, 10, 64)
+					if err != nil {
+						response.BadRequestError(c, "token carries an invalid actor id")
+						c.Abort()
+						return
+					}
+					c.Set("actorId", uint(actorID))
+					c.Set("actorName", claims.Act.Username)
+				}
+
+				if claims.MustChangePassword() && !options.allowPasswordChangePending {
+					response.SendErrorResponse(c, response.STATUS_FORBIDDEN, PASSWORD_CHANGE_REQUIRED, PASSWORD_CHANGE_REQUIRED)
+					c.Abort()
+					return
+				}
+				if claims.MFAEnrollmentRequired() && !options.allowMFAEnrollmentPending {
+					response.SendErrorResponse(c, response.STATUS_FORBIDDEN, MFA_ENROLLMENT_REQUIRED, MFA_ENROLLMENT_REQUIRED)
+					c.Abort()
+					return
+				}
 				c.Next()
+
+				if claims.Act != nil {
+					logging.Log.Info("Impersonated request",
+						zap.String("actor_id", claims.Act.Subject),
+						zap.String("actor_name", claims.Act.Username),
+						zap.Uint("user_id", userID),
+						zap.String("method", c.Request.Method),
+						zap.String("path", c.FullPath()),
+						zap.Int("status", c.Writer.Status()),
+					)
+				}
 				return
 			}
 		}
@@ -58,48 +141,143 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// Token types carried in JWTClaims.TokenType
+const (
+	AccessTokenType     = "access"
+	RefreshTokenType    = "refresh"
+	MFAPendingTokenType = "mfa_pending"
+	CheckInTokenType    = "check_in"
+)
+
 func validateToken(tokenString string) (*jwt.Token, error) {
-	return jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid token signing method")
-		}
-		return jwtSecret, nil
-	})
+	token, err := tokens.ParseWithClaims(tokenString, &JWTClaims{})
+	if err != nil {
+		return nil, err
+	}
+	if claims, ok := token.Claims.(*JWTClaims); !ok || claims.TokenType != AccessTokenType {
+		return nil, errors.New("token is not an access token")
+	}
+	return token, nil
+}
+
+// UserID returns the numeric user ID embedded in the claims
+func (c *JWTClaims) UserID() (uint, error) {
+	if len(c.Data) == 0 {
+		return 0, errors.New("token carries no user data")
+	}
+	id, err := strconv.ParseUint(c.Data[0]["userId"], 10, 64)
+	if err != nil {
+		return 0, errors.New("token carries an invalid user id")
+	}
+	return uint(id), nil
+}
+
+// Role returns the user role embedded in the claims
+func (c *JWTClaims) Role() USERROLE {
+	if len(c.Data) == 0 {
+		return INVALID
+	}
+	return GetUserType(c.Data[0]["role"])
+}
+
+// SessionID returns the refresh token family the claims were issued under
+func (c *JWTClaims) SessionID() string {
+	if len(c.Data) == 0 {
+		return ""
+	}
+	return c.Data[0]["sessionId"]
+}
+
+// MustChangePassword reports whether the session is limited to changing the password
+func (c *JWTClaims) MustChangePassword() bool {
+	return len(c.Data) > 0 && c.Data[0]["mustChangePassword"] == "true"
+}
+
+// MFAEnrollmentRequired reports whether the session is limited to MFA enrollment
+func (c *JWTClaims) MFAEnrollmentRequired() bool {
+	return len(c.Data) > 0 && c.Data[0]["mfaEnrollmentRequired"] == "true"
 }
 
-func GenerateTokens(username string) (string, string, error) {
+// TokenSubject identifies who a token pair is issued to
+type TokenSubject struct {
+	UserID                uint
+	Username              string
+	Role                  USERROLE
+	SessionID             string // refresh token family, so logout can revoke it
+	MustChangePassword    bool
+	MFAEnrollmentRequired bool
+	Actor                 *TokenActor   // set when an administrator acts as the subject
+	AccessTTL             time.Duration // overrides the configured access token lifetime when set
+}
+
+// TokenActor is the real user behind an impersonated token
+type TokenActor struct {
+	UserID   uint
+	Username string
+}
+
+// GenerateTokens mints an access token and a refresh token for the subject. The
+// refresh token carries refreshID as its jti. An empty refreshID mints only the
+// access token, for sessions that must not be extended such as impersonation.
+func GenerateTokens(subject TokenSubject, refreshID string) (string, string, error) {
 	now := time.Now()
+	accessTTL := tokens.AccessTTL()
+	if subject.AccessTTL > 0 {
+		accessTTL = subject.AccessTTL
+	}
+	var act *ActorClaim
+	if subject.Actor != nil {
+		act = &ActorClaim{
+			Subject:  strconv.FormatUint(uint64(subject.Actor.UserID), 10),
+			Username: subject.Actor.Username,
+		}
+	}
+	data := []map[string]string{{
+		"userId":    strconv.FormatUint(uint64(subject.UserID), 10),
+		"userName":  subject.Username,
+		"role":      subject.Role.String(),
+		"sessionId": subject.SessionID,
+	}}
+	if subject.MustChangePassword {
+		data[0]["mustChangePassword"] = "true"
+	}
+	if subject.MFAEnrollmentRequired {
+		data[0]["mfaEnrollmentRequired"] = "true"
+	}
 
-	// Access Token (1 hour expiration)
 	accessTokenClaims := JWTClaims{
-		Data: []map[string]string{{"userName": username}},
+		Data:      data,
+		TokenType: AccessTokenType,
+		Act:       act,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)), // 1 hour expiration
-			Issuer:    "Pranshu",
-			Subject:   username,
-			Audience:  jwt.ClaimStrings{"flexio-admin"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
+			Issuer:    tokens.Issuer(),
+			Subject:   data[0]["userId"],
+			Audience:  jwt.ClaimStrings{tokens.Audience()},
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
-	accessTokenString, err := accessToken.SignedString(jwtSecret)
+	accessTokenString, err := tokens.Sign(accessTokenClaims)
 	if err != nil {
 		return "", "", err
 	}
+	if refreshID == "" {
+		return accessTokenString, "", nil
+	}
 
-	// Refresh Token (1 day expiration)
 	refreshTokenClaims := JWTClaims{
-		Data: []map[string]string{{"userName": username}},
+		Data:      data,
+		TokenType: RefreshTokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)), // 1 day expiration
-			Issuer:    "Pranshu",
-			Subject:   username,
-			Audience:  jwt.ClaimStrings{"flexio-admin"},
+			ID:        refreshID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(tokens.RefreshTTL())),
+			Issuer:    tokens.Issuer(),
+			Subject:   data[0]["userId"],
+			Audience:  jwt.ClaimStrings{tokens.Audience()},
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshTokenClaims)
-	refreshTokenString, err := refreshToken.SignedString(jwtSecret)
+	refreshTokenString, err := tokens.Sign(refreshTokenClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -107,31 +285,94 @@ func GenerateTokens(username string) (string, string, error) {
 	return accessTokenString, refreshTokenString, nil
 }
 
-// New method for validating refresh tokens
+// ValidateRefreshToken verifies the signature and expiry of a refresh token and
+// rejects access tokens. Revocation is checked by the caller against the store.
 func ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid token signing method")
-		}
-		return jwtSecret, nil
-	})
-
+	token, err := tokens.ParseWithClaims(tokenString, &JWTClaims{})
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if claims.TokenType != RefreshTokenType || claims.ID == "" || claims.Act != nil {
+			return nil, errors.New("token is not a refresh token")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid refresh token")
 }
 
+// GenerateMFAPendingToken mints a short-lived token proving the user passed the
+// password step. It is only accepted by the second login step.
+func GenerateMFAPendingToken(userID uint, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		Data:      []map[string]string{{"userId": strconv.FormatUint(uint64(userID), 10)}},
+		TokenType: MFAPendingTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			Issuer:    tokens.Issuer(),
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			Audience:  jwt.ClaimStrings{tokens.Audience()},
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	return tokens.Sign(claims)
+}
+
+// ValidateMFAPendingToken verifies an MFA pending token and returns its user ID
+func ValidateMFAPendingToken(tokenString string) (uint, error) {
+	token, err := tokens.ParseWithClaims(tokenString, &JWTClaims{})
+	if err != nil {
+		return 0, err
+	}
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || claims.TokenType != MFAPendingTokenType {
+		return 0, errors.New("token is not an MFA pending token")
+	}
+	return claims.UserID()
+}
+
+// GenerateCheckInToken mints a short-lived token a customer shows as a QR code
+// at the kiosk. The ID lets the kiosk accept each token only once.
+func GenerateCheckInToken(userID uint, tokenID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := JWTClaims{
+		Data:      []map[string]string{{"userId": strconv.FormatUint(uint64(userID), 10)}},
+		TokenType: CheckInTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			Issuer:    tokens.Issuer(),
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			Audience:  jwt.ClaimStrings{tokens.Audience()},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        tokenID,
+		},
+	}
+	token, err := tokens.Sign(claims)
+	return token, expiresAt, err
+}
+
+// ValidateCheckInToken verifies a check-in token and returns its claims
+func ValidateCheckInToken(tokenString string) (*JWTClaims, error) {
+	token, err := tokens.ParseWithClaims(tokenString, &JWTClaims{})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || claims.TokenType != CheckInTokenType || claims.ID == "" || claims.Act != nil {
+		return nil, errors.New("token is not a check-in token")
+	}
+	return claims, nil
+}
+
 func CORSMiddleware() gin.HandlerFunc {
     return func(c *gin.Context) {
         c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:1234") // Set to the frontend's origin
         c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, x-request-id")
+        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, x-request-id, X-Device-ID")
         c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 
         if c.Request.Method == http.MethodOptions {
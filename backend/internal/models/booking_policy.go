@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Defaults of the booking policy of an allie that has not set one
+const (
+	DefaultBookingWindowDays   = 14
+	DefaultCancelCutoffMinutes = 120
+	DefaultMaxStrikes          = 3
+	DefaultStrikeWindowDays    = 30
+)
+
+// BookingPolicy holds an allie's class booking rules. Late cancellations and
+// no-shows count as strikes; a customer with MaxStrikes strikes on sessions
+// of the last StrikeWindowDays days cannot book until they age out.
+type BookingPolicy struct {
+	BaseModel
+	AllieID             int `gorm:"column:allie_id;not null;uniqueIndex"`
+	BookingWindowDays   int `gorm:"column:booking_window_days;not null;default:14"`    // how far ahead sessions can be booked
+	CancelCutoffMinutes int `gorm:"column:cancel_cutoff_minutes;not null;default:120"` // cancelling later than this before the start is late
+	MaxStrikes          int `gorm:"column:max_strikes;not null;default:3"`             // 0 never blocks booking
+	StrikeWindowDays    int `gorm:"column:strike_window_days;not null;default:30"`
+	UpdatedBy           int `gorm:"column:updated_by"`
+}
+
+// DefaultBookingPolicy returns the policy that applies to an allie that has not set one
+func DefaultBookingPolicy(allieID int) *BookingPolicy {
+	return &BookingPolicy{
+		AllieID:             allieID,
+		BookingWindowDays:   DefaultBookingWindowDays,
+		CancelCutoffMinutes: DefaultCancelCutoffMinutes,
+		MaxStrikes:          DefaultMaxStrikes,
+		StrikeWindowDays:    DefaultStrikeWindowDays,
+	}
+}
+
+// LateCancellationAt reports whether cancelling a spot in a session starting
+// at start is late at now
+func (p *BookingPolicy) LateCancellationAt(start, now time.Time) bool {
+	return now.After(start.Add(-time.Duration(p.CancelCutoffMinutes) * time.Minute))
+}
+
+// OpensBookingAt reports whether a session starting at start can be booked at now
+func (p *BookingPolicy) OpensBookingAt(start, now time.Time) bool {
+	return now.Before(start) && !start.After(now.AddDate(0, 0, p.BookingWindowDays))
+}
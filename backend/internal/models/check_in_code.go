@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RedeemedCheckInCode records a check-in code accepted by a kiosk so it cannot
+// be replayed. Rows are only needed until the code expires.
+type RedeemedCheckInCode struct {
+	BaseModel
+	TokenID   string    `gorm:"column:token_id;size:64;not null;uniqueIndex"`
+	UserID    uint      `gorm:"column:user_id;not null"`
+	CrewID    int       `gorm:"column:crew_id;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// ClassBooking is a customer's spot in a class session, or their place on its
+// waitlist. Waitlisted bookings are promoted in the order they were made.
+type ClassBooking struct {
+	BaseModel
+	SessionID    uint          `gorm:"column:session_id;not null;index:idx_class_bookings_session_status,priority:1"`
+	CustomerID   uint          `gorm:"column:customer_id;not null;index"`
+	Status       BOOKINGSTATUS `gorm:"column:status;size:20;not null;index:idx_class_bookings_session_status,priority:2"`
+	BookedAt     *time.Time    `gorm:"column:booked_at"` // when the spot was taken, on booking or on promotion
+	WaitlistedAt *time.Time    `gorm:"column:waitlisted_at"`
+	CancelledAt  *time.Time    `gorm:"column:cancelled_at"`
+	CreatedBy    int           `gorm:"column:created_by"`
+	UpdatedBy    int           `gorm:"column:updated_by"`
+
+	Session  ClassSession `gorm:"foreignKey:SessionID"`
+	Customer Customer     `gorm:"foreignKey:CustomerID"`
+}
+
+// IsActive reports whether the booking still holds a spot or a waitlist place
+func (b *ClassBooking) IsActive() bool {
+	return b.Status == BOOKING_BOOKED || b.Status == BOOKING_WAITLISTED
+}
+
+// SessionOccupancy counts the booked and waitlisted customers of a session. It
+// is a query result, not a table.
+type SessionOccupancy struct {
+	SessionID  uint `gorm:"column:session_id"`
+	Booked     int  `gorm:"column:booked"`
+	Waitlisted int  `gorm:"column:waitlisted"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// ClassSession is one occurrence of a class. Sessions generated from a
+// template keep TemplateID and the slot they were generated for in OccursAt,
+// so a slot is generated once even when its session is moved or cancelled. A
+// session edited on its own is marked Modified so that later template changes
+// leave it alone.
+type ClassSession struct {
+	BaseModel
+	TemplateID   *uint              `gorm:"column:template_id;uniqueIndex:idx_class_sessions_template_slot"`
+	OccursAt     *time.Time         `gorm:"column:occurs_at;uniqueIndex:idx_class_sessions_template_slot"`
+	CrewID       int                `gorm:"column:crew_id;not null;index"`
+	ServiceID    uint               `gorm:"column:fit_service_id;not null"`
+	TrainerID    uint               `gorm:"column:trainer_id;not null;index:idx_class_sessions_trainer_time,priority:1"`
+	Name         string             `gorm:"column:name;size:100;not null"`
+	Room         string             `gorm:"column:room;size:50"`
+	Capacity     int                `gorm:"column:capacity;not null"`
+	StartsAt     time.Time          `gorm:"column:starts_at;not null;index:idx_class_sessions_trainer_time,priority:2"`
+	EndsAt       time.Time          `gorm:"column:ends_at;not null"`
+	Status       CLASSSESSIONSTATUS `gorm:"column:status;size:20;not null;default:'SCHEDULED'"`
+	Modified     bool               `gorm:"column:modified;default:false"`
+	CancelReason string             `gorm:"column:cancel_reason;size:255"`
+	UpdatedBy    int                `gorm:"column:updated_by"`
+
+	FitService FitService     `gorm:"foreignKey:ServiceID"`
+	Trainer    TrainerProfile `gorm:"foreignKey:TrainerID"`
+}
+
+// OverlapsWith reports whether the session runs at any moment of start..end
+func (s *ClassSession) OverlapsWith(start, end time.Time) bool {
+	return s.StartsAt.Before(end) && start.Before(s.EndsAt)
+}
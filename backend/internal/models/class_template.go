@@ -0,0 +1,99 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"strings"
+	"time"
+)
+
+// ClassTemplate is a group class that repeats every week on its weekdays at a
+// start time on the crew's clock. Its sessions are generated from it.
+type ClassTemplate struct {
+	BaseModel
+	CrewID          int        `gorm:"column:crew_id;not null;index"`
+	ServiceID       uint       `gorm:"column:fit_service_id;not null;index"`
+	TrainerID       uint       `gorm:"column:trainer_id;not null;index"`
+	Name            string     `gorm:"column:name;size:100;not null"`
+	Description     string     `gorm:"column:description;size:500"`
+	Weekdays        string     `gorm:"column:weekdays;size:80;not null"`  // comma-separated WEEKDAY values
+	StartTime       string     `gorm:"column:start_time;size:5;not null"` // HH:MM
+	DurationMinutes int        `gorm:"column:duration_minutes;not null"`
+	Room            string     `gorm:"column:room;size:50"`
+	Capacity        int        `gorm:"column:capacity;not null"`
+	StartsOn        time.Time  `gorm:"column:starts_on;type:date;not null"`
+	EndsOn          *time.Time `gorm:"column:ends_on;type:date"` // nil when the class runs until stopped
+	IsActive        bool       `gorm:"column:is_active;default:true"`
+	CreatedBy       int        `gorm:"column:created_by"`
+	UpdatedBy       int        `gorm:"column:updated_by"`
+
+	FitService FitService     `gorm:"foreignKey:ServiceID"`
+	Trainer    TrainerProfile `gorm:"foreignKey:TrainerID"`
+}
+
+// Days returns the weekdays the class runs on
+func (t *ClassTemplate) Days() []WEEKDAY {
+	days := make([]WEEKDAY, 0, 7)
+	for _, day := range strings.Split(t.Weekdays, ",") {
+		if day != "" {
+			days = append(days, WEEKDAY(day))
+		}
+	}
+	return days
+}
+
+// SetDays stores the weekdays the class runs on
+func (t *ClassTemplate) SetDays(days []WEEKDAY) {
+	names := make([]string, 0, len(days))
+	for _, day := range days {
+		names = append(names, string(day))
+	}
+	t.Weekdays = strings.Join(names, ",")
+}
+
+// RunsOn reports whether the class runs on the weekday
+func (t *ClassTemplate) RunsOn(day WEEKDAY) bool {
+	for _, d := range t.Days() {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrences returns the start times of the class from..until, read on the
+// clock of location. StartsOn and EndsOn are calendar dates and bound the
+// dates the class runs on.
+func (t *ClassTemplate) Occurrences(from, until time.Time, location *time.Location) []time.Time {
+	clock, err := time.Parse(ClockLayout, t.StartTime)
+	if err != nil {
+		return nil
+	}
+
+	day := calendarDay(from.In(location), location)
+	if startsOn := calendarDay(t.StartsOn, location); startsOn.After(day) {
+		day = startsOn
+	}
+	lastDay := calendarDay(until.In(location), location)
+	if t.EndsOn != nil {
+		if endsOn := calendarDay(*t.EndsOn, location); endsOn.Before(lastDay) {
+			lastDay = endsOn
+		}
+	}
+
+	var starts []time.Time
+	for ; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		if !t.RunsOn(WeekdayOf(day.Weekday())) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+		if !start.Before(from) && !start.After(until) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// calendarDay returns midnight in location of the date t shows
+func calendarDay(t time.Time, location *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// ClockLayout is the layout of the opening and closing times of a crew, read
+// on the crew's local clock. "24:00" closes at the end of the day.
+const ClockLayout = "15:04"
+
+// EndOfDay is the closing time of an interval that runs until midnight
+const EndOfDay = "24:00"
+
+// CrewOpeningHour is one opening interval of a crew on a day of the week. A day
+// may have several intervals; a day without any is closed.
+type CrewOpeningHour struct {
+	BaseModel
+	CrewID   int     `gorm:"column:crew_id;not null;index"`
+	Weekday  WEEKDAY `gorm:"column:weekday;size:10;not null"`
+	OpensAt  string  `gorm:"column:opens_at;size:5;not null"`  // HH:MM
+	ClosesAt string  `gorm:"column:closes_at;size:5;not null"` // HH:MM, after OpensAt
+}
+
+// Contains reports whether the local clock time hhmm falls in the interval
+func (h *CrewOpeningHour) Contains(hhmm string) bool {
+	return h.OpensAt <= hhmm && hhmm < h.ClosesAt
+}
+
+// CrewHoliday overrides the weekly hours of a crew between two dates, both
+// included. Without hours the crew is closed on those days; with hours it is
+// open only then.
+type CrewHoliday struct {
+	BaseModel
+	CrewID   int       `gorm:"column:crew_id;not null;index"`
+	FromDate time.Time `gorm:"column:from_date;type:date;not null"`
+	ToDate   time.Time `gorm:"column:to_date;type:date;not null"`
+	Name     string    `gorm:"column:name;size:100;not null"`
+	OpensAt  *string   `gorm:"column:opens_at;size:5"`
+	ClosesAt *string   `gorm:"column:closes_at;size:5"`
+}
+
+// Covers reports whether the holiday applies on the calendar date, given as YYYY-MM-DD
+func (h *CrewHoliday) Covers(date string) bool {
+	return h.FromDate.Format(time.DateOnly) <= date && date <= h.ToDate.Format(time.DateOnly)
+}
+
+// Location returns the crew's timezone, or UTC when it is not a known zone
+func (c *FitCrew) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// OpenAt reports whether the crew is open at t, reading t on the crew's local
+// clock. Holidays covering the date replace the weekly hours of that day. The
+// crew's OpeningHours and Holidays must be loaded.
+func (c *FitCrew) OpenAt(t time.Time) bool {
+	local := t.In(c.Location())
+	date, hhmm := local.Format(time.DateOnly), local.Format(ClockLayout)
+
+	covered := false
+	for _, holiday := range c.Holidays {
+		if !holiday.Covers(date) {
+			continue
+		}
+		covered = true
+		if holiday.OpensAt != nil && holiday.ClosesAt != nil && *holiday.OpensAt <= hhmm && hhmm < *holiday.ClosesAt {
+			return true
+		}
+	}
+	if covered {
+		return false
+	}
+
+	weekday := WeekdayOf(local.Weekday())
+	for _, hour := range c.OpeningHours {
+		if hour.Weekday == weekday && hour.Contains(hhmm) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// MaxVisitDuration is how long a visit without a check-out counts as inside.
+// Members who leave without checking out stop counting towards the live
+// occupancy after it.
+const MaxVisitDuration = 6 * time.Hour
+
+// CrewVisit is one stay of a customer at a crew, from check-in to check-out.
+// VisitDate is the day of the check-in on the crew's clock.
+type CrewVisit struct {
+	BaseModel
+	CrewID       int           `gorm:"column:crew_id;not null;index:idx_crew_visits_crew_open,priority:1"`
+	CustomerID   uint          `gorm:"column:customer_id;not null;index:idx_crew_visits_customer_time,priority:1"`
+	VisitDate    time.Time     `gorm:"column:visit_date;type:date;not null"`
+	CheckedInAt  time.Time     `gorm:"column:checked_in_at;not null;index:idx_crew_visits_customer_time,priority:2"`
+	CheckedOutAt *time.Time    `gorm:"column:checked_out_at;index:idx_crew_visits_crew_open,priority:2"`
+	Method       CHECKINMETHOD `gorm:"column:method;size:20;not null"`
+	AutoClosed   bool          `gorm:"column:auto_closed;default:false"` // closed without a check-out when the customer came back
+	CheckedInBy  int           `gorm:"column:checked_in_by"`
+	CheckedOutBy int           `gorm:"column:checked_out_by"`
+
+	Customer Customer `gorm:"foreignKey:CustomerID"`
+}
+
+// InsideAt reports whether the visit counts towards the crew's occupancy at t
+func (v *CrewVisit) InsideAt(t time.Time) bool {
+	return v.CheckedOutAt == nil && t.Sub(v.CheckedInAt) < MaxVisitDuration
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	. "backend/internal/resources/constants"
 	"time"
 )
 
@@ -15,10 +16,53 @@ type Customer struct {
     Mobile           string    `gorm:"column:mobile;size:20"`
     AlternateMobile  string    `gorm:"column:alternate_mobile;size:20"`
     DateOfBirth      time.Time `gorm:"column:date_of_birth"`
+    State            string    `gorm:"column:state;size:100"`       // Billing state, the place of supply of the customer's invoices
     MembershipStart  time.Time `gorm:"column:membership_start"`     // Membership start date
     MembershipEnd    time.Time `gorm:"column:membership_end"`       // Membership end date
-    IsActive         bool      `gorm:"column:is_active"`            // Whether the membership is active
+    FrozenFrom       *time.Time `gorm:"column:frozen_from"`         // Start of the latest freeze
+    FrozenUntil      *time.Time `gorm:"column:frozen_until"`        // End of the latest freeze; the end date was pushed by its length
+    CancelledAt      *time.Time `gorm:"column:cancelled_at"`
+    PlanID           *uint     `gorm:"column:plan_id"`              // Plan of the current term
+    FreezeDaysAllowed int      `gorm:"column:freeze_days_allowed;default:0"` // Entitlements snapshotted from the plan when bought
+    FreezeDaysUsed   int       `gorm:"column:freeze_days_used;default:0"`
+    VisitLimit       int       `gorm:"column:visit_limit;default:0"` // 0 is unlimited
+    VisitsPerDay     int       `gorm:"column:visits_per_day;default:0"`
+    AwaitingPayment  bool      `gorm:"column:awaiting_payment;default:false"` // Enrolled but the first term is not paid yet; the dates hold no term
+    IsActive         bool      `gorm:"column:is_active"`            // Derived from the dates by MembershipActiveAt; never set by hand
 
     FitCrew FitCrew `gorm:"foreignKey:CrewID"` // Each Customer belongs to one GymBranch
     User      User      `gorm:"foreignKey:UserID"`   // Relationship to User
+    Plan      *MembershipPlan `gorm:"foreignKey:PlanID"`
+}
+
+// MembershipFrozenAt reports whether a freeze is in effect at t
+func (c *Customer) MembershipFrozenAt(t time.Time) bool {
+    return c.FrozenFrom != nil && c.FrozenUntil != nil &&
+        !t.Before(*c.FrozenFrom) && t.Before(*c.FrozenUntil)
+}
+
+// MembershipActiveAt reports whether the customer may use the crew at t: the
+// membership is paid, has started, has not ended or been cancelled, and is not frozen.
+func (c *Customer) MembershipActiveAt(t time.Time) bool {
+    return c.CancelledAt == nil && !c.AwaitingPayment &&
+        !t.Before(c.MembershipStart) && t.Before(c.MembershipEnd) &&
+        !c.MembershipFrozenAt(t)
+}
+
+// MembershipStatusAt reports where the membership stands at t
+func (c *Customer) MembershipStatusAt(t time.Time) MEMBERSHIPSTATUS {
+    switch {
+    case c.CancelledAt != nil:
+        return MEMBERSHIP_STATUS_CANCELLED
+    case c.AwaitingPayment:
+        return MEMBERSHIP_STATUS_UNPAID
+    case !t.Before(c.MembershipEnd):
+        return MEMBERSHIP_STATUS_EXPIRED
+    case t.Before(c.MembershipStart):
+        return MEMBERSHIP_STATUS_PENDING
+    case c.MembershipFrozenAt(t):
+        return MEMBERSHIP_STATUS_FROZEN
+    default:
+        return MEMBERSHIP_STATUS_ACTIVE
+    }
 }
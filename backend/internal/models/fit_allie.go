@@ -21,9 +21,10 @@ type FitAllie struct {
     City               string `gorm:"column:city;size:100"`
     State              string `gorm:"column:state;size:100"`
     PinCode            string `gorm:"column:pin_code;size:20"`
+    GSTIN              string `gorm:"column:gstin;size:15"`            // GST registration printed on the allie's invoices
     CreatedBy          int    `gorm:"column:created_by"`
     UpdatedBy          int    `gorm:"column:updated_by"`
 
     FitCrews []FitCrew `gorm:"foreignKey:AllieID"`   // One GymPartner has many GymBranches
-		FitService []FitService  `gorm:"many2many:fit_allie_services;"`
+    Offerings []FitAllieService `gorm:"foreignKey:FitAllieID"` // Services the allie offers
 }
@@ -1,6 +1,23 @@
 package models
 
+// FitAllieService is a service an allie offers, at all of its crews or at one
+// crew when CrewID is set. It replaces the plain allie/service join, which could
+// not carry a crew or a price. PriceMinor is the per-visit price in minor units;
+// nil means the service is included with memberships.
 type FitAllieService struct {
-    FitAllieID int `gorm:"primaryKey"`
-    ServiceID  int `gorm:"primaryKey"`
+	BaseModel
+	FitAllieID int    `gorm:"column:fit_allie_id;not null;index"`
+	ServiceID  int    `gorm:"column:fit_service_id;not null;index"`
+	CrewID     *int   `gorm:"column:crew_id;index"`
+	PriceMinor *int64 `gorm:"column:price_minor"`
+	Currency   string `gorm:"column:currency;size:3"`
+	IsActive   bool   `gorm:"column:is_active;default:true"`
+
+	FitAllie   FitAllie   `gorm:"foreignKey:FitAllieID"`
+	FitService FitService `gorm:"foreignKey:ServiceID"`
+}
+
+// TableName keeps offerings apart from the join table the old many-to-many created
+func (FitAllieService) TableName() string {
+	return "fit_allie_offerings"
 }
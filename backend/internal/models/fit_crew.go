@@ -14,15 +14,25 @@ type FitCrew struct {
     GymName           string `gorm:"column:gym_name;size:50"`
     Address           string `gorm:"column:address;size:255"`
     City              string `gorm:"column:city;size:100"`
-		Lat               string `gorm:"column:lat;size:30"`
-		Long              string `gorm:"column:long;size:30"`
+    Lat               *float64 `gorm:"column:latitude;index:idx_fit_crews_location,priority:1"`  // degrees, nil when not located
+    Long              *float64 `gorm:"column:longitude;index:idx_fit_crews_location,priority:2"`
     State             string `gorm:"column:state;size:100"`
     PinCode           string `gorm:"column:pin_code;size:20"`
     Capacity          int    `gorm:"column:capacity"`
+    Timezone          string `gorm:"column:timezone;size:64;not null;default:'Asia/Kolkata'"` // IANA zone the opening hours are read in
     CreatedBy         int    `gorm:"column:created_by"`
     UpdatedBy         int    `gorm:"column:updated_by"`
 
     FitAllie FitAllie `gorm:"foreignKey:AllieID"`  // Each GymBranch belongs to one GymPartner
     Customers  []Customer `gorm:"foreignKey:CrewID"`   // Each GymBranch has many Customers
 		TrainerProfiles []TrainerProfile `gorm:"foreignKey:CrewID"`
+    OpeningHours    []CrewOpeningHour `gorm:"foreignKey:CrewID"`
+    Holidays        []CrewHoliday     `gorm:"foreignKey:CrewID"`
+}
+
+// FitCrewDistance is a crew read by the nearby search with its distance from
+// the searched point. It is a query result, not a table.
+type FitCrewDistance struct {
+    FitCrew
+    DistanceKm float64 `gorm:"column:distance_km"`
 }
@@ -1,11 +1,14 @@
-
 package models
 
-import (
-)
-
+// FitService is an entry of the global catalog of activities (yoga, CrossFit,
+// swimming...) that allies offer and trainers specialise in
 type FitService struct {
 	BaseModel
-	Name      string `gorm:"column:name;size:100;not null"`
-	FitAllies []FitAllie `gorm:"many2many:fit_allie_services;"` // Many-to-many relationship with FitAllie
+	Name        string `gorm:"column:name;size:100;not null"`
+	Category    string `gorm:"column:category;size:50;index"`
+	Icon        string `gorm:"column:icon;size:100"`
+	Description string `gorm:"column:description;size:500"`
+	IsActive    bool   `gorm:"column:is_active;default:true"`
+
+	Offerings []FitAllieService `gorm:"foreignKey:ServiceID"` // Allies offering the service
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// Impersonation records an administrator being issued a token to act as another
+// user. Requests made with the token are tagged in the logs with ActorID.
+type Impersonation struct {
+	BaseModel
+	ActorID   uint      `gorm:"column:actor_id;not null;index"`
+	TargetID  uint      `gorm:"column:target_id;not null;index"`
+	Reason    string    `gorm:"column:reason;size:255;not null"`
+	ClientIP  string    `gorm:"column:client_ip;size:64"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+
+	Actor  User `gorm:"foreignKey:ActorID"`
+	Target User `gorm:"foreignKey:TargetID"`
+}
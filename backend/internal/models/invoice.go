@@ -0,0 +1,94 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvoiceImmutable is returned when an issued invoice or credit note would be changed
+var ErrInvoiceImmutable = errors.New("issued invoices and credit notes cannot be changed")
+
+// Invoice is a GST tax invoice issued for a paid order, or a credit note issued
+// for a refund of one. The seller, buyer and amounts are copied when it is
+// issued and the record is never changed afterwards.
+type Invoice struct {
+	BaseModel
+	AllieID        int         `gorm:"column:allie_id;not null;uniqueIndex:idx_invoices_allie_number,priority:1"`
+	Number         string      `gorm:"column:number;size:16;not null;uniqueIndex:idx_invoices_allie_number,priority:2"`
+	Kind           INVOICEKIND `gorm:"column:kind;size:20;not null"`
+	FiscalYear     int         `gorm:"column:fiscal_year;not null"` // year the April to March financial year starts
+	Sequence       int64       `gorm:"column:sequence;not null"`
+	IssuedAt       time.Time   `gorm:"column:issued_at;not null"`
+	CrewID         int         `gorm:"column:crew_id;not null;index"`
+	CustomerID     uint        `gorm:"column:customer_id;not null;index"`
+	OrderID        uint        `gorm:"column:order_id;not null;index"`
+	RefundID       *uint       `gorm:"column:refund_id;uniqueIndex"` // the refund a credit note is issued for
+	OriginalID     *uint       `gorm:"column:original_id"`           // the invoice a credit note reduces
+	OriginalNumber string      `gorm:"column:original_number;size:16"`
+
+	SellerName      string `gorm:"column:seller_name;size:100;not null"`
+	SellerAddress   string `gorm:"column:seller_address;size:500"`
+	SellerGSTIN     string `gorm:"column:seller_gstin;size:15"`
+	SellerStateCode string `gorm:"column:seller_state_code;size:2;not null"`
+	BuyerName       string `gorm:"column:buyer_name;size:200;not null"`
+	BuyerEmail      string `gorm:"column:buyer_email;size:100"`
+	BuyerMobile     string `gorm:"column:buyer_mobile;size:20"`
+	PlaceOfSupply   string `gorm:"column:place_of_supply;size:2;not null"` // GST state code
+
+	Description  string `gorm:"column:description;size:255;not null"`
+	SAC          string `gorm:"column:sac;size:8"`
+	TaxRateBps   int    `gorm:"column:tax_rate_bps;not null;default:0"`
+	TaxableMinor int64  `gorm:"column:taxable_minor;not null"`
+	CGSTMinor    int64  `gorm:"column:cgst_minor;not null;default:0"`
+	SGSTMinor    int64  `gorm:"column:sgst_minor;not null;default:0"`
+	IGSTMinor    int64  `gorm:"column:igst_minor;not null;default:0"`
+	TotalMinor   int64  `gorm:"column:total_minor;not null"`
+	Currency     string `gorm:"column:currency;size:3;not null"`
+	CreatedBy    uint   `gorm:"column:created_by"`
+}
+
+// InvoiceSequence hands out the document numbers of an allie, one series per
+// kind of document and financial year. Its row is locked while a number is
+// taken, so the numbers of a series follow each other without gaps.
+type InvoiceSequence struct {
+	BaseModel
+	AllieID    int         `gorm:"column:allie_id;not null;uniqueIndex:idx_invoice_sequences_series,priority:1"`
+	Kind       INVOICEKIND `gorm:"column:kind;size:20;not null;uniqueIndex:idx_invoice_sequences_series,priority:2"`
+	FiscalYear int         `gorm:"column:fiscal_year;not null;uniqueIndex:idx_invoice_sequences_series,priority:3"`
+	LastNumber int64       `gorm:"column:last_number;not null;default:0"`
+}
+
+// AssignNumber gives the invoice the sequence number of its series, formatted
+// like INV/26-27/000042 to fit the 16 characters GST allows
+func (i *Invoice) AssignNumber(sequence int64) {
+	prefix := "INV"
+	if i.Kind == INVOICE_KIND_CREDIT_NOTE {
+		prefix = "CN"
+	}
+	i.Sequence = sequence
+	i.Number = fmt.Sprintf("%s/%02d-%02d/%06d", prefix, i.FiscalYear%100, (i.FiscalYear+1)%100, sequence)
+}
+
+// Interstate reports whether the supply crossed states and was taxed with IGST
+func (i *Invoice) Interstate() bool {
+	return i.SellerStateCode != i.PlaceOfSupply
+}
+
+// TaxMinor returns the total tax of the invoice
+func (i *Invoice) TaxMinor() int64 {
+	return i.CGSTMinor + i.SGSTMinor + i.IGSTMinor
+}
+
+// BeforeUpdate keeps issued invoices from being changed
+func (i *Invoice) BeforeUpdate(tx *gorm.DB) error {
+	return ErrInvoiceImmutable
+}
+
+// BeforeDelete keeps issued invoices from being deleted
+func (i *Invoice) BeforeDelete(tx *gorm.DB) error {
+	return ErrInvoiceImmutable
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	. "backend/internal/resources/constants"
+)
+
+func TestInvoiceAssignNumber(t *testing.T) {
+	tests := []struct {
+		kind       INVOICEKIND
+		fiscalYear int
+		sequence   int64
+		want       string
+	}{
+		{INVOICE_KIND_TAX_INVOICE, 2026, 42, "INV/26-27/000042"},
+		{INVOICE_KIND_CREDIT_NOTE, 2026, 7, "CN/26-27/000007"},
+		{INVOICE_KIND_TAX_INVOICE, 2099, 1, "INV/99-00/000001"},
+		{INVOICE_KIND_TAX_INVOICE, 2008, 1, "INV/08-09/000001"},
+		{INVOICE_KIND_TAX_INVOICE, 2026, 999999, "INV/26-27/999999"},
+	}
+
+	for _, tt := range tests {
+		invoice := Invoice{Kind: tt.kind, FiscalYear: tt.fiscalYear}
+		invoice.AssignNumber(tt.sequence)
+		if invoice.Number != tt.want {
+			t.Errorf("%s %d #%d: number %s, want %s", tt.kind, tt.fiscalYear, tt.sequence, invoice.Number, tt.want)
+		}
+		if len(invoice.Number) > 16 {
+			t.Errorf("number %s is longer than the 16 characters GST allows", invoice.Number)
+		}
+		if invoice.Sequence != tt.sequence {
+			t.Errorf("sequence %d, want %d", invoice.Sequence, tt.sequence)
+		}
+	}
+}
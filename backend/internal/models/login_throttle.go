@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for one account or one client IP
+type LoginThrottle struct {
+	BaseModel
+	Scope        string     `gorm:"column:scope;size:20;not null;uniqueIndex:idx_login_throttles_scope_key"` // "account" or "ip"
+	Key          string     `gorm:"column:key;size:100;not null;uniqueIndex:idx_login_throttles_scope_key"`  // user ID or IP address
+	FailedCount  int        `gorm:"column:failed_count;not null;default:0"`
+	LastFailedAt *time.Time `gorm:"column:last_failed_at"`
+	LockedUntil  *time.Time `gorm:"column:locked_until"`
+}
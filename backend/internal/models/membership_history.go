@@ -0,0 +1,29 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// MembershipHistory records one state transition of a customer's membership
+type MembershipHistory struct {
+	BaseModel
+	CustomerID  uint             `gorm:"column:customer_id;not null;index"`
+	Action      MEMBERSHIPACTION `gorm:"column:action;size:20;not null"`
+	FromCrewID  int              `gorm:"column:from_crew_id"`
+	ToCrewID    int              `gorm:"column:to_crew_id"`
+	PreviousEnd *time.Time       `gorm:"column:previous_end"`
+	NewEnd      *time.Time       `gorm:"column:new_end"`
+	Days        int              `gorm:"column:days"`
+	Note        string           `gorm:"column:note;size:255"`
+	PerformedBy uint             `gorm:"column:performed_by"`
+
+	// Price snapshot of the plan bought by an enrollment or renewal
+	PlanID     *uint  `gorm:"column:plan_id"`
+	PlanName   string `gorm:"column:plan_name;size:100"`
+	PriceMinor int64  `gorm:"column:price_minor"`
+	TaxMinor   int64  `gorm:"column:tax_minor"`
+	Currency   string `gorm:"column:currency;size:3"`
+
+	Customer Customer `gorm:"foreignKey:CustomerID"`
+}
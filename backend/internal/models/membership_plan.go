@@ -0,0 +1,35 @@
+package models
+
+// MembershipPlan is something a customer can buy: a term of DurationDays at an
+// allie, or at one of its crews when CrewID is set. Prices are in minor units
+// (paise for INR) and exclude tax.
+type MembershipPlan struct {
+	BaseModel
+	AllieID      int    `gorm:"column:allie_id;not null;index"`
+	CrewID       *int   `gorm:"column:crew_id;index"` // nil when the plan is sold at every crew of the allie
+	Name         string `gorm:"column:name;size:100;not null"`
+	Description  string `gorm:"column:description;size:500"`
+	DurationDays int    `gorm:"column:duration_days;not null"`
+	PriceMinor   int64  `gorm:"column:price_minor;not null"`
+	Currency     string `gorm:"column:currency;size:3;not null;default:INR"`
+	TaxRateBps   int    `gorm:"column:tax_rate_bps;not null;default:0"` // basis points; 1800 is 18%
+	FreezeDays   int    `gorm:"column:freeze_days;not null;default:0"`  // total days a term may be frozen
+	VisitLimit   int    `gorm:"column:visit_limit;not null;default:0"`  // visits per term; 0 is unlimited
+	VisitsPerDay int    `gorm:"column:visits_per_day;not null;default:0"`
+	IsActive     bool   `gorm:"column:is_active;default:true"`
+	CreatedBy    int    `gorm:"column:created_by"`
+	UpdatedBy    int    `gorm:"column:updated_by"`
+
+	FitAllie FitAllie     `gorm:"foreignKey:AllieID"`
+	Services []FitService `gorm:"many2many:membership_plan_services;"`
+}
+
+// TaxMinor returns the tax charged on the plan's price, rounded half up
+func (p *MembershipPlan) TaxMinor() int64 {
+	return (p.PriceMinor*int64(p.TaxRateBps) + 5000) / 10000
+}
+
+// AvailableAt reports whether the plan may be sold at the crew
+func (p *MembershipPlan) AvailableAt(crew *FitCrew) bool {
+	return p.IsActive && p.AllieID == crew.AllieID && (p.CrewID == nil || *p.CrewID == int(crew.ID))
+}
@@ -0,0 +1,20 @@
+package models
+
+// Menu is an admin panel route. Entries form a tree through ParentID, and a
+// role sees an entry when it holds the entry's Permission or none is set.
+type Menu struct {
+	BaseModel
+	ParentID   *uint  `gorm:"column:parent_id;index"`
+	Name       string `gorm:"column:name;size:100;index;not null"` // route name the frontend resolves components by
+	Path       string `gorm:"column:path;size:200;not null"`
+	Component  string `gorm:"column:component;size:200"`
+	Title      string `gorm:"column:title;size:100;not null"`
+	I18nKey    string `gorm:"column:i18n_key;size:100"`
+	Icon       string `gorm:"column:icon;size:100"`
+	Order      int    `gorm:"column:sort_order;default:0"`
+	Permission string `gorm:"column:permission;size:50"`
+	Constant   bool   `gorm:"column:constant;default:false"` // served before login, e.g. login and error pages
+	HideInMenu bool   `gorm:"column:hide_in_menu;default:false"`
+	KeepAlive  bool   `gorm:"column:keep_alive;default:false"`
+	IsActive   bool   `gorm:"column:is_active;default:true"`
+}
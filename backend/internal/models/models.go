@@ -4,6 +4,9 @@ package models
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
@@ -11,11 +14,39 @@ import (
 var RegisterModels = []interface{}{
 	&User{},
 	&Customer{},
+	&MembershipHistory{},
+	&MembershipPlan{},
 	&FitCrew{},
+	&CrewOpeningHour{},
+	&CrewHoliday{},
 	&FitService{},
 	&FitAllie{},
 	&FitAllieService{},
 	&TrainerProfile{},
+	&TrainerCertification{},
+	&ClassTemplate{},
+	&ClassSession{},
+	&ClassBooking{},
+	&BookingPolicy{},
+	&CrewVisit{},
+	&RedeemedCheckInCode{},
+	&PaymentOrder{},
+	&PaymentAttempt{},
+	&PaymentRefund{},
+	&LedgerEntry{},
+	&PaymentWebhookEvent{},
+	&Invoice{},
+	&InvoiceSequence{},
+	&SettlementStatement{},
+	&SettlementLine{},
+	&RefreshToken{},
+	&LoginThrottle{},
+	&PasswordResetToken{},
+	&OTPCode{},
+	&UserMFA{},
+	&MFARecoveryCode{},
+	&Menu{},
+	&Impersonation{},
 }
 
 // AutoMigrateDB handles the auto-migration of all registered models
@@ -25,6 +56,44 @@ func AutoMigrateDB(db *gorm.DB) error {
 			return fmt.Errorf("failed to migrate %T: %w", model, err)
 		}
 	}
+	return migrateCrewCoordinates(db)
+}
+
+// migrateCrewCoordinates moves crew coordinates from the old lat and long text
+// columns into the numeric latitude and longitude columns, then drops the old
+// columns. Values that do not parse as in-range degrees are left out.
+func migrateCrewCoordinates(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&FitCrew{}, "lat") || !migrator.HasColumn(&FitCrew{}, "long") {
+		return nil
+	}
+
+	var rows []struct {
+		ID   uint
+		Lat  string
+		Long string
+	}
+	if err := db.Table("fit_crews").Select("id, lat, long").Where("latitude IS NULL").Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to migrate crew coordinates: %w", err)
+	}
+	for _, row := range rows {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(row.Lat), 64)
+		long, longErr := strconv.ParseFloat(strings.TrimSpace(row.Long), 64)
+		if latErr != nil || longErr != nil || lat < -90 || lat > 90 || long < -180 || long > 180 {
+			continue
+		}
+		err := db.Table("fit_crews").Where("id = ?", row.ID).
+			Updates(map[string]interface{}{"latitude": lat, "longitude": long}).Error
+		if err != nil {
+			return fmt.Errorf("failed to migrate crew coordinates: %w", err)
+		}
+	}
+	if err := migrator.DropColumn(&FitCrew{}, "lat"); err != nil {
+		return fmt.Errorf("failed to migrate crew coordinates: %w", err)
+	}
+	if err := migrator.DropColumn(&FitCrew{}, "long"); err != nil {
+		return fmt.Errorf("failed to migrate crew coordinates: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// OTPCode is a one-time login code sent to a mobile number. Only a bcrypt hash
+// of the code is stored. Requests for numbers without an account store a code
+// with no user that is consumed at once, so they are throttled the same way.
+type OTPCode struct {
+	BaseModel
+	UserID     *uint      `gorm:"column:user_id;index"`
+	Mobile     string     `gorm:"column:mobile;size:20;not null;index"`
+	CodeHash   string     `gorm:"column:code_hash;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	Attempts   int        `gorm:"column:attempts;not null;default:0"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordResetToken is a single-use password reset grant. Only a SHA-256 hash of
+// the token handed to the user is stored.
+type PasswordResetToken struct {
+	BaseModel
+	UserID    uint       `gorm:"column:user_id;not null;index"`
+	TokenHash string     `gorm:"column:token_hash;size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// PaymentOrder is a membership term a customer is buying. The plan's price and
+// entitlements are copied when the order is placed, and the term is added to
+// the membership when a payment for it is captured.
+type PaymentOrder struct {
+	BaseModel
+	CustomerID    uint        `gorm:"column:customer_id;not null;index"`
+	CrewID        int         `gorm:"column:crew_id;not null;index"`
+	AllieID       int         `gorm:"column:allie_id;not null;index"`
+	Status        ORDERSTATUS `gorm:"column:status;size:20;not null"`
+	PlanID        uint        `gorm:"column:plan_id;not null"`
+	PlanName      string      `gorm:"column:plan_name;size:100"`
+	DurationDays  int         `gorm:"column:duration_days;not null"`
+	FreezeDays    int         `gorm:"column:freeze_days;not null;default:0"`
+	VisitLimit    int         `gorm:"column:visit_limit;not null;default:0"`
+	VisitsPerDay  int         `gorm:"column:visits_per_day;not null;default:0"`
+	PriceMinor    int64       `gorm:"column:price_minor;not null"`
+	TaxRateBps    int         `gorm:"column:tax_rate_bps;not null;default:0"`
+	TaxMinor      int64       `gorm:"column:tax_minor;not null"`
+	AmountMinor   int64       `gorm:"column:amount_minor;not null"` // price plus tax
+	RefundedMinor int64       `gorm:"column:refunded_minor;not null;default:0"`
+	Currency      string      `gorm:"column:currency;size:3;not null"`
+	PaidAt        *time.Time  `gorm:"column:paid_at"`
+	CreatedBy     uint        `gorm:"column:created_by"`
+
+	Customer Customer         `gorm:"foreignKey:CustomerID"`
+	Attempts []PaymentAttempt `gorm:"foreignKey:OrderID"`
+	Refunds  []PaymentRefund  `gorm:"foreignKey:OrderID"`
+}
+
+// Plan returns the plan as it was sold by the order
+func (o *PaymentOrder) Plan() *MembershipPlan {
+	plan := &MembershipPlan{
+		AllieID:      o.AllieID,
+		Name:         o.PlanName,
+		DurationDays: o.DurationDays,
+		PriceMinor:   o.PriceMinor,
+		Currency:     o.Currency,
+		TaxRateBps:   o.TaxRateBps,
+		FreezeDays:   o.FreezeDays,
+		VisitLimit:   o.VisitLimit,
+		VisitsPerDay: o.VisitsPerDay,
+		IsActive:     true,
+	}
+	plan.ID = o.PlanID
+	return plan
+}
+
+// RefundableMinor returns what is left to refund of the captured amount
+func (o *PaymentOrder) RefundableMinor() int64 {
+	if o.PaidAt == nil {
+		return 0
+	}
+	return o.AmountMinor - o.RefundedMinor
+}
+
+// PaymentAttempt is one try at paying an order through the gateway. Reference
+// is ours and unique; ProviderRef is the gateway's reference of the charge.
+type PaymentAttempt struct {
+	BaseModel
+	OrderID       uint          `gorm:"column:order_id;not null;index"`
+	Gateway       string        `gorm:"column:gateway;size:30;not null"`
+	Reference     string        `gorm:"column:reference;size:64;not null;uniqueIndex"`
+	ProviderRef   string        `gorm:"column:provider_ref;size:100;index"`
+	Method        string        `gorm:"column:method;size:30"`
+	AmountMinor   int64         `gorm:"column:amount_minor;not null"`
+	Currency      string        `gorm:"column:currency;size:3;not null"`
+	Status        ATTEMPTSTATUS `gorm:"column:status;size:20;not null"`
+	FailureReason string        `gorm:"column:failure_reason;size:255"`
+	CapturedAt    *time.Time    `gorm:"column:captured_at"`
+	CreatedBy     uint          `gorm:"column:created_by"`
+}
+
+// PaymentRefund returns part or all of a captured payment
+type PaymentRefund struct {
+	BaseModel
+	OrderID       uint         `gorm:"column:order_id;not null;index"`
+	AttemptID     uint         `gorm:"column:attempt_id;not null"`
+	Gateway       string       `gorm:"column:gateway;size:30;not null"`
+	Reference     string       `gorm:"column:reference;size:64;not null;uniqueIndex"`
+	ProviderRef   string       `gorm:"column:provider_ref;size:100"`
+	AmountMinor   int64        `gorm:"column:amount_minor;not null"`
+	Currency      string       `gorm:"column:currency;size:3;not null"`
+	Status        REFUNDSTATUS `gorm:"column:status;size:20;not null"`
+	Reason        string       `gorm:"column:reason;size:255"`
+	FailureReason string       `gorm:"column:failure_reason;size:255"`
+	RefundedAt    *time.Time   `gorm:"column:refunded_at"`
+	RequestedBy   uint         `gorm:"column:requested_by"`
+}
+
+// LedgerEntry is one line of the double-entry payments ledger. The entries
+// sharing a TransactionRef always balance: their debits equal their credits.
+type LedgerEntry struct {
+	BaseModel
+	TransactionRef string        `gorm:"column:transaction_ref;size:80;not null;uniqueIndex:idx_ledger_entries_transaction_account,priority:1"`
+	Account        LEDGERACCOUNT `gorm:"column:account;size:30;not null;uniqueIndex:idx_ledger_entries_transaction_account,priority:2"`
+	OrderID        uint          `gorm:"column:order_id;not null;index"`
+	AllieID        int           `gorm:"column:allie_id;not null;index"`
+	CrewID         int           `gorm:"column:crew_id;not null"`
+	DebitMinor     int64         `gorm:"column:debit_minor;not null;default:0"`
+	CreditMinor    int64         `gorm:"column:credit_minor;not null;default:0"`
+	Currency       string        `gorm:"column:currency;size:3;not null"`
+	Description    string        `gorm:"column:description;size:255"`
+	PostedAt       time.Time     `gorm:"column:posted_at;not null"`
+}
+
+// PaymentWebhookEvent records a webhook from the gateway so a redelivery is
+// processed once
+type PaymentWebhookEvent struct {
+	BaseModel
+	Gateway     string     `gorm:"column:gateway;size:30;not null;uniqueIndex:idx_payment_webhook_events_event,priority:1"`
+	EventID     string     `gorm:"column:event_id;size:100;not null;uniqueIndex:idx_payment_webhook_events_event,priority:2"`
+	Type        string     `gorm:"column:type;size:50;not null"`
+	Payload     string     `gorm:"column:payload;type:text"`
+	ProcessedAt *time.Time `gorm:"column:processed_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken is a server-side record of an issued refresh token. Tokens issued by
+// rotation share the FamilyID of the login that started the session.
+type RefreshToken struct {
+	BaseModel
+	UserID     uint       `gorm:"column:user_id;not null;index:idx_refresh_tokens_user_device"`
+	DeviceID   string     `gorm:"column:device_id;size:100;not null;index:idx_refresh_tokens_user_device"`
+	JTI        string     `gorm:"column:jti;size:64;uniqueIndex;not null"`
+	FamilyID   string     `gorm:"column:family_id;size:64;index;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	ReplacedBy string     `gorm:"column:replaced_by;size:64"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	. "backend/internal/resources/constants"
+	"time"
+)
+
+// SettlementStatement settles what the platform and an allie are owed for the
+// payments captured and refunded over a period. The allie's commission rate is
+// copied when the statement is generated, so changing the rate later leaves
+// past statements as they were.
+type SettlementStatement struct {
+	BaseModel
+	AllieID          int              `gorm:"column:allie_id;not null;index"`
+	PeriodStart      time.Time        `gorm:"column:period_start;not null"` // inclusive
+	PeriodEnd        time.Time        `gorm:"column:period_end;not null"`   // exclusive
+	Status           SETTLEMENTSTATUS `gorm:"column:status;size:20;not null;default:'DRAFT'"`
+	CommissionRate   int              `gorm:"column:commission_rate;not null"` // percent of revenue, as it was when generated
+	Currency         string           `gorm:"column:currency;size:3;not null"`
+	GrossMinor       int64            `gorm:"column:gross_minor;not null;default:0"`
+	TaxMinor         int64            `gorm:"column:tax_minor;not null;default:0"`
+	RevenueMinor     int64            `gorm:"column:revenue_minor;not null;default:0"`
+	CommissionMinor  int64            `gorm:"column:commission_minor;not null;default:0"`
+	PayoutMinor      int64            `gorm:"column:payout_minor;not null;default:0"`
+	CreatedBy        uint             `gorm:"column:created_by"`
+	ApprovedBy       *uint            `gorm:"column:approved_by"`
+	ApprovedAt       *time.Time       `gorm:"column:approved_at"`
+	PaidBy           *uint            `gorm:"column:paid_by"`
+	PaidAt           *time.Time       `gorm:"column:paid_at"`
+	PaymentReference string           `gorm:"column:payment_reference;size:100"` // bank transfer the payout was sent with
+
+	Lines []SettlementLine `gorm:"foreignKey:StatementID"`
+}
+
+// SettlementLine is one captured payment or refund on a statement. Refunds
+// carry negative amounts. Each ledger transaction is settled on one line only.
+type SettlementLine struct {
+	BaseModel
+	StatementID     uint               `gorm:"column:statement_id;not null;index"`
+	CrewID          int                `gorm:"column:crew_id;not null"`
+	OrderID         uint               `gorm:"column:order_id;not null;index"`
+	TransactionRef  string             `gorm:"column:transaction_ref;size:80;not null;uniqueIndex"`
+	Kind            SETTLEMENTLINEKIND `gorm:"column:kind;size:20;not null"`
+	PostedAt        time.Time          `gorm:"column:posted_at;not null"`
+	CommissionRate  int                `gorm:"column:commission_rate;not null"`
+	GrossMinor      int64              `gorm:"column:gross_minor;not null"`      // paid by or refunded to the customer
+	TaxMinor        int64              `gorm:"column:tax_minor;not null"`        // tax collected, passed through to the allie
+	RevenueMinor    int64              `gorm:"column:revenue_minor;not null"`    // price excluding tax, which commission is charged on
+	CommissionMinor int64              `gorm:"column:commission_minor;not null"` // kept by the platform
+	PayoutMinor     int64              `gorm:"column:payout_minor;not null"`     // owed to the allie; negative when the allie took it at the desk
+	Currency        string             `gorm:"column:currency;size:3;not null"`
+}
+
+// Total adds up the statement's lines into its totals
+func (s *SettlementStatement) Total() {
+	s.GrossMinor, s.TaxMinor, s.RevenueMinor, s.CommissionMinor, s.PayoutMinor = 0, 0, 0, 0, 0
+	for _, line := range s.Lines {
+		s.GrossMinor += line.GrossMinor
+		s.TaxMinor += line.TaxMinor
+		s.RevenueMinor += line.RevenueMinor
+		s.CommissionMinor += line.CommissionMinor
+		s.PayoutMinor += line.PayoutMinor
+	}
+}
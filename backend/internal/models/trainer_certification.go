@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// TrainerCertification is a qualification held by a trainer
+type TrainerCertification struct {
+	BaseModel
+	TrainerID    uint       `gorm:"column:trainer_id;not null;index"`
+	Name         string     `gorm:"column:name;size:100;not null"`
+	Issuer       string     `gorm:"column:issuer;size:100"`
+	CredentialID string     `gorm:"column:credential_id;size:100"`
+	IssuedOn     *time.Time `gorm:"column:issued_on;type:date"`
+	ExpiresOn    *time.Time `gorm:"column:expires_on;type:date"` // nil when the certification does not expire
+}
+
+// ExpiredAt reports whether the certification has lapsed at t
+func (c *TrainerCertification) ExpiredAt(t time.Time) bool {
+	return c.ExpiresOn != nil && !t.Before(*c.ExpiresOn)
+}
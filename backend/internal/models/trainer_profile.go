@@ -6,11 +6,34 @@ import (
 type TrainerProfile struct {
     BaseModel
     CrewID          int       `gorm:"column:crew_id"`
+    UserID          *uint     `gorm:"column:user_id"`      // optional GYMSTAFF login of the trainer
     FullName         string    `gorm:"column:full_name;size:50;not null"`
     Email            string    `gorm:"column:email;size:100"`
     Mobile           string    `gorm:"column:mobile;size:20;not null"`
     AlternateMobile  string    `gorm:"column:alternate_mobile;size:20"`
     IsActive         bool      `gorm:"column:is_active;default:true"`
+    CrewInactive     bool      `gorm:"column:crew_inactive;default:false"` // set while the crew is deactivated; hides the trainer without touching IsActive
     ExpStartedFrom   time.Time `gorm:"column:exp_started_from;type:date;not null"`
     FitCrew         FitCrew  `gorm:"foreignKey:CrewID"` // Each TrainerProfile belongs to one FitCrew
+    User            *User    `gorm:"foreignKey:UserID"`
+    Specializations []FitService `gorm:"many2many:trainer_specializations;"`
+    Certifications  []TrainerCertification `gorm:"foreignKey:TrainerID"`
+}
+
+// IsVisible reports whether customers may see the trainer
+func (t *TrainerProfile) IsVisible() bool {
+    return t.IsActive && !t.CrewInactive
+}
+
+// YearsOfExperienceAt returns the full years between ExpStartedFrom and at
+func (t *TrainerProfile) YearsOfExperienceAt(at time.Time) int {
+    years := at.Year() - t.ExpStartedFrom.Year()
+    if at.Month() < t.ExpStartedFrom.Month() ||
+        (at.Month() == t.ExpStartedFrom.Month() && at.Day() < t.ExpStartedFrom.Day()) {
+        years--
+    }
+    if years < 0 {
+        return 0
+    }
+    return years
 }
@@ -15,6 +15,7 @@ type User struct {
     Mobile       string       `gorm:"column:mobile;size:20;unique;not null"`
     UserType     USERROLE `gorm:"column:user_type;not null;default:-1"`
     PasswordHash string       `gorm:"column:password_hash;not null"`
+    MustChangePassword bool   `gorm:"column:must_change_password;default:false"`
     CreatedBy    int          `gorm:"column:created_by"`
     UpdatedBy    int          `gorm:"column:updated_by"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// UserMFA holds a user's TOTP enrollment. EnabledAt stays nil until the user
+// confirms the secret with a first code.
+type UserMFA struct {
+	BaseModel
+	UserID          uint       `gorm:"column:user_id;uniqueIndex;not null"`
+	Secret          string     `gorm:"column:secret;size:64;not null"`
+	EnabledAt       *time.Time `gorm:"column:enabled_at"`
+	LastUsedCounter int64      `gorm:"column:last_used_counter;not null;default:0"` // TOTP time step of the last accepted code
+
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// MFARecoveryCode is a single-use fallback for a lost authenticator. Only a
+// SHA-256 hash of the code is stored.
+type MFARecoveryCode struct {
+	BaseModel
+	UserID   uint       `gorm:"column:user_id;not null;index"`
+	CodeHash string     `gorm:"column:code_hash;size:64;not null;index"`
+	UsedAt   *time.Time `gorm:"column:used_at"`
+}
@@ -0,0 +1,40 @@
+// internal/notifications/notifier.go
+package notifications
+
+import (
+	"context"
+
+	"backend/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Message is a notification addressed to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users, e.g. by email
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// LogNotifier writes who a message is for to the application log instead of
+// delivering it. The body is left out, as it may carry secrets such as password
+// reset tokens. It is meant for local development only.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier instance
+func NewLogNotifier() Notifier {
+	return &LogNotifier{}
+}
+
+// Send logs the recipient and subject of the message
+func (n *LogNotifier) Send(ctx context.Context, message Message) error {
+	logging.Log.Info("Notification (not delivered, log notifier)",
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+	)
+	return nil
+}
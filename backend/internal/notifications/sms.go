@@ -0,0 +1,32 @@
+// internal/notifications/sms.go
+package notifications
+
+import (
+	"context"
+
+	"backend/internal/logging"
+	"go.uber.org/zap"
+)
+
+// SMSSender delivers text messages to mobile numbers
+type SMSSender interface {
+	SendSMS(ctx context.Context, mobile, body string) error
+}
+
+// LogSMSSender writes the number a text message is for to the application log
+// instead of sending it. The body is left out, as it may carry login codes. It
+// is meant for local development only.
+type LogSMSSender struct{}
+
+// NewLogSMSSender creates a new LogSMSSender instance
+func NewLogSMSSender() SMSSender {
+	return &LogSMSSender{}
+}
+
+// SendSMS logs the number of the text message
+func (s *LogSMSSender) SendSMS(ctx context.Context, mobile, body string) error {
+	logging.Log.Info("SMS (not delivered, log sender)",
+		zap.String("mobile", mobile),
+	)
+	return nil
+}
@@ -0,0 +1,91 @@
+// internal/payments/gateway.go
+package payments
+
+import (
+	"context"
+	"errors"
+)
+
+// Errors a gateway returns for requests it cannot take
+var (
+	ErrInvalidMethod    = errors.New("payment method not supported by the gateway")
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+)
+
+// ChargeStatus tells where a charge stands at the gateway
+type ChargeStatus string
+
+const (
+	ChargeCaptured ChargeStatus = "captured" // the money is taken
+	ChargePending  ChargeStatus = "pending"  // the outcome arrives later by webhook
+	ChargeFailed   ChargeStatus = "failed"
+)
+
+// RefundStatus tells where a refund stands at the gateway
+type RefundStatus string
+
+const (
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundPending   RefundStatus = "pending" // the outcome arrives later by webhook
+	RefundFailed    RefundStatus = "failed"
+)
+
+// Webhook event types
+const (
+	EventPaymentCaptured = "payment.captured"
+	EventPaymentFailed   = "payment.failed"
+	EventRefundSucceeded = "refund.succeeded"
+	EventRefundFailed    = "refund.failed"
+)
+
+// ChargeRequest asks the gateway to take a payment. Reference is our unique
+// reference of the attempt; gateways treat a repeated reference as the same charge.
+type ChargeRequest struct {
+	Reference   string
+	AmountMinor int64
+	Currency    string
+	Method      string
+	Description string
+}
+
+// Charge is the gateway's answer to a charge request
+type Charge struct {
+	ProviderRef   string
+	Status        ChargeStatus
+	FailureReason string
+}
+
+// RefundRequest asks the gateway to return part or all of a captured charge
+type RefundRequest struct {
+	Reference   string
+	ChargeRef   string // provider reference of the captured charge
+	AmountMinor int64
+	Currency    string
+}
+
+// Refund is the gateway's answer to a refund request
+type Refund struct {
+	ProviderRef   string
+	Status        RefundStatus
+	FailureReason string
+}
+
+// WebhookEvent is a verified notification from the gateway. Reference is our
+// reference of the attempt or refund the event is about.
+type WebhookEvent struct {
+	ID            string
+	Type          string
+	Reference     string
+	ProviderRef   string
+	FailureReason string
+}
+
+// PaymentGateway takes payments and refunds them through a payment provider
+type PaymentGateway interface {
+	// Name identifies the gateway in stored attempts and webhook URLs
+	Name() string
+	Charge(ctx context.Context, request ChargeRequest) (*Charge, error)
+	Refund(ctx context.Context, request RefundRequest) (*Refund, error)
+	// VerifyWebhook checks the signature of a webhook body and decodes it
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
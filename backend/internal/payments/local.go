@@ -0,0 +1,162 @@
+// internal/payments/local.go
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/logging"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+)
+
+// WebhookTolerance is how far the timestamp of a signed webhook may be from now
+const WebhookTolerance = 5 * time.Minute
+
+// Payment methods understood by the local gateway
+const (
+	LocalMethodCard         = "card"          // captured at once
+	LocalMethodUPI          = "upi"           // pending until a payment webhook arrives
+	LocalMethodCardDeclined = "card_declined" // fails for insufficient funds
+	LocalMethodCardExpired  = "card_expired"  // fails for an expired card
+)
+
+// LocalGateway settles payments in process without moving any money. It is
+// meant for local development and tests only. Its webhooks are signed like a
+// real provider's: the header is "t=<unix time>,v1=<hex HMAC-SHA256 of
+// "<unix time>.<body>">", so they can be produced with SignWebhook.
+type LocalGateway struct {
+	secret []byte
+}
+
+// NewLocalGateway creates a new LocalGateway verifying webhooks with the secret
+func NewLocalGateway(webhookSecret string) PaymentGateway {
+	return &LocalGateway{secret: []byte(webhookSecret)}
+}
+
+// Name identifies the local gateway
+func (g *LocalGateway) Name() string {
+	return "local"
+}
+
+// Charge settles the charge according to the method
+func (g *LocalGateway) Charge(ctx context.Context, request ChargeRequest) (*Charge, error) {
+	charge := &Charge{ProviderRef: "local_ch_" + localRef()}
+	switch request.Method {
+	case LocalMethodCard:
+		charge.Status = ChargeCaptured
+	case LocalMethodUPI:
+		charge.Status = ChargePending
+	case LocalMethodCardDeclined:
+		charge.Status = ChargeFailed
+		charge.FailureReason = INSUFFICIENT_FUNDS
+	case LocalMethodCardExpired:
+		charge.Status = ChargeFailed
+		charge.FailureReason = CARD_EXPIRED
+	default:
+		return nil, ErrInvalidMethod
+	}
+
+	logging.Log.Info("Payment (no money moved, local gateway)",
+		zap.String("reference", request.Reference),
+		zap.String("provider_ref", charge.ProviderRef),
+		zap.Int64("amount_minor", request.AmountMinor),
+		zap.String("currency", request.Currency),
+		zap.String("status", string(charge.Status)),
+	)
+	return charge, nil
+}
+
+// Refund always succeeds at once
+func (g *LocalGateway) Refund(ctx context.Context, request RefundRequest) (*Refund, error) {
+	refund := &Refund{ProviderRef: "local_re_" + localRef(), Status: RefundSucceeded}
+	logging.Log.Info("Refund (no money moved, local gateway)",
+		zap.String("reference", request.Reference),
+		zap.String("charge_ref", request.ChargeRef),
+		zap.Int64("amount_minor", request.AmountMinor),
+	)
+	return refund, nil
+}
+
+// localWebhook is the body of a local gateway webhook
+type localWebhook struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Reference     string `json:"reference"`
+		ProviderRef   string `json:"provider_ref"`
+		FailureReason string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+// VerifyWebhook checks the signature and its timestamp, then decodes the body
+func (g *LocalGateway) VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if len(g.secret) == 0 {
+		return nil, fmt.Errorf("%w: no webhook secret is configured", ErrInvalidSignature)
+	}
+
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || mac == "" {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > WebhookTolerance || age < -WebhookTolerance {
+		return nil, fmt.Errorf("%w: timestamp is outside the tolerance", ErrInvalidSignature)
+	}
+	expected, err := hex.DecodeString(mac)
+	if err != nil || !hmac.Equal(expected, g.sign(timestamp, payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var body localWebhook
+	if err := json.Unmarshal(payload, &body); err != nil || body.ID == "" || body.Type == "" {
+		return nil, fmt.Errorf("%w: malformed body", ErrInvalidSignature)
+	}
+	return &WebhookEvent{
+		ID:            body.ID,
+		Type:          body.Type,
+		Reference:     body.Data.Reference,
+		ProviderRef:   body.Data.ProviderRef,
+		FailureReason: body.Data.FailureReason,
+	}, nil
+}
+
+// SignWebhook returns the signature header of a webhook body sent at the time
+func (g *LocalGateway) SignWebhook(payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(g.sign(timestamp, payload))
+}
+
+func (g *LocalGateway) sign(timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// localRef returns a random provider reference
+func localRef() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
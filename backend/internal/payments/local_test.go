@@ -0,0 +1,108 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend/internal/logging"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logging.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+const testWebhook = `{"id":"evt_1","type":"payment.captured","data":{"reference":"pay_1","provider_ref":"local_ch_1"}}`
+
+func TestVerifyWebhook(t *testing.T) {
+	gateway := NewLocalGateway("webhook-secret").(*LocalGateway)
+	other := NewLocalGateway("other-secret").(*LocalGateway)
+	payload := []byte(testWebhook)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+		ok        bool
+	}{
+		{"signed now", payload, gateway.SignWebhook(payload, now), true},
+		{"signed within the tolerance in the past", payload, gateway.SignWebhook(payload, now.Add(-WebhookTolerance+time.Minute)), true},
+		{"signed within the tolerance in the future", payload, gateway.SignWebhook(payload, now.Add(WebhookTolerance-time.Minute)), true},
+		{"signed too long ago", payload, gateway.SignWebhook(payload, now.Add(-WebhookTolerance-time.Minute)), false},
+		{"signed too far ahead", payload, gateway.SignWebhook(payload, now.Add(WebhookTolerance+time.Minute)), false},
+		{"signed with another secret", payload, other.SignWebhook(payload, now), false},
+		{"body changed after signing", []byte(`{"id":"evt_1","type":"payment.failed","data":{"reference":"pay_1"}}`), gateway.SignWebhook(payload, now), false},
+		{"mac is not hex", payload, "t=" + unixString(now) + ",v1=zz", false},
+		{"mac is missing", payload, "t=" + unixString(now), false},
+		{"timestamp is missing", payload, "v1=00", false},
+		{"empty signature", payload, "", false},
+		{"malformed body", []byte(`{"id":""}`), gateway.SignWebhook([]byte(`{"id":""}`), now), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := gateway.VerifyWebhook(tt.payload, tt.signature)
+			if !tt.ok {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("error = %v, want %v", err, ErrInvalidSignature)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyWebhook: %v", err)
+			}
+			want := WebhookEvent{ID: "evt_1", Type: EventPaymentCaptured, Reference: "pay_1", ProviderRef: "local_ch_1"}
+			if *event != want {
+				t.Errorf("event = %+v, want %+v", *event, want)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookWithoutSecret(t *testing.T) {
+	unsigned := NewLocalGateway("").(*LocalGateway)
+	payload := []byte(testWebhook)
+
+	// a signature made with the empty key must not pass either
+	if _, err := unsigned.VerifyWebhook(payload, unsigned.SignWebhook(payload, time.Now())); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestLocalCharge(t *testing.T) {
+	gateway := NewLocalGateway("")
+	tests := []struct {
+		method string
+		status ChargeStatus
+		reason string
+	}{
+		{LocalMethodCard, ChargeCaptured, ""},
+		{LocalMethodUPI, ChargePending, ""},
+		{LocalMethodCardDeclined, ChargeFailed, INSUFFICIENT_FUNDS},
+		{LocalMethodCardExpired, ChargeFailed, CARD_EXPIRED},
+	}
+
+	for _, tt := range tests {
+		charge, err := gateway.Charge(context.Background(), ChargeRequest{Reference: "pay_1", AmountMinor: 1180, Currency: "INR", Method: tt.method})
+		if err != nil {
+			t.Fatalf("Charge with %s: %v", tt.method, err)
+		}
+		if charge.Status != tt.status || charge.FailureReason != tt.reason || charge.ProviderRef == "" {
+			t.Errorf("Charge with %s = %+v, want status %s and reason %q", tt.method, *charge, tt.status, tt.reason)
+		}
+	}
+	if _, err := gateway.Charge(context.Background(), ChargeRequest{Method: "cheque"}); !errors.Is(err, ErrInvalidMethod) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidMethod)
+	}
+}
+
+func unixString(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
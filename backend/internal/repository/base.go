@@ -46,7 +46,7 @@ func (r *BaseRepository) GetPagination(filter map[string]interface{}) Pagination
 	if val, ok := filter["offset"]; ok {
 		if o, ok := val.(int); ok {
 			if o > 1 {
-				offset = ((o - 1) * limit) + 1
+				offset = (o - 1) * limit
 			} else if o == 1 {
 				offset = 0
 			}
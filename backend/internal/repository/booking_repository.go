@@ -0,0 +1,403 @@
+// internal/repository/booking_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookingOutcome tells how a change to the bookings of a session went
+type BookingOutcome int
+
+const (
+	BookingApplied       BookingOutcome = iota // the change was made
+	BookingSessionClosed                       // the session is no longer scheduled
+	BookingDuplicate                           // the customer already holds an active booking of the session
+	BookingInactive                            // the booking was already cancelled or attended
+	BookingOverCapacity                        // the session has more booked customers than its capacity
+)
+
+// BookingListOptions narrows the bookings of a user
+type BookingListOptions struct {
+	Filters map[string]interface{} // paging and BuildQuery conditions
+	UserID  uint
+	From    *time.Time // only bookings of sessions starting from then on
+}
+
+// BookingRepositoryInterface defines the contract for class booking persistence.
+// Every change to the bookings of a session is made under a lock on the
+// session row, so the last seat cannot be taken twice.
+type BookingRepositoryInterface interface {
+	Book(booking *models.ClassBooking, now time.Time) (BookingOutcome, error)
+	Cancel(booking *models.ClassBooking, late bool, now time.Time, updatedBy uint) (BookingOutcome, []models.ClassBooking, error)
+	MarkAttendance(booking *models.ClassBooking, status BOOKINGSTATUS, updatedBy uint) (bool, error)
+	FindByID(id uint) (*models.ClassBooking, error)
+	ListBySession(sessionID uint) ([]models.ClassBooking, error)
+	ListForUser(options BookingListOptions) ([]models.ClassBooking, int64, error)
+	Occupancy(sessionIDs []uint) (map[uint]models.SessionOccupancy, error)
+	CountStrikes(customerID uint, since, until time.Time) (int64, error)
+	PlanCovers(planID, serviceID uint) (bool, error)
+
+	SaveSession(session *models.ClassSession, now time.Time, check TrainerCheck) (BookingOutcome, []models.ClassBooking, error)
+	CancelSession(session *models.ClassSession, now time.Time) ([]models.ClassBooking, error)
+
+	FindPolicy(allieID uint) (*models.BookingPolicy, error)
+	SavePolicy(policy *models.BookingPolicy) error
+}
+
+// BookingRepository implements BookingRepositoryInterface
+type BookingRepository struct {
+	*BaseRepository
+}
+
+// NewBookingRepository creates a new BookingRepository instance
+func NewBookingRepository(db *gorm.DB) BookingRepositoryInterface {
+	return &BookingRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Book creates the booking with a spot when the session has one left, or on
+// its waitlist otherwise
+func (r *BookingRepository) Book(booking *models.ClassBooking, now time.Time) (BookingOutcome, error) {
+	outcome := BookingApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		session, err := lockSession(tx, booking.SessionID)
+		if err != nil {
+			return err
+		}
+		if session.Status != CLASS_SESSION_SCHEDULED {
+			outcome = BookingSessionClosed
+			return nil
+		}
+
+		var held int64
+		err = tx.Model(&models.ClassBooking{}).
+			Where("session_id = ? AND customer_id = ? AND status IN ?", session.ID, booking.CustomerID, activeBookingStatuses).
+			Count(&held).Error
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			outcome = BookingDuplicate
+			return nil
+		}
+
+		booked, err := countBooked(tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if booked < int64(session.Capacity) {
+			booking.Status = BOOKING_BOOKED
+			booking.BookedAt = &now
+		} else {
+			booking.Status = BOOKING_WAITLISTED
+			booking.WaitlistedAt = &now
+		}
+		return tx.Omit("Session", "Customer").Create(booking).Error
+	})
+	return outcome, err
+}
+
+// Cancel cancels an active booking, as a late cancellation when late is set
+// and the booking held a spot. The spot goes to the waitlist; the promoted
+// bookings are returned.
+func (r *BookingRepository) Cancel(booking *models.ClassBooking, late bool, now time.Time, updatedBy uint) (BookingOutcome, []models.ClassBooking, error) {
+	outcome := BookingApplied
+	var promoted []models.ClassBooking
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		session, err := lockSession(tx, booking.SessionID)
+		if err != nil {
+			return err
+		}
+
+		var current models.ClassBooking
+		if err := tx.First(&current, booking.ID).Error; err != nil {
+			return err
+		}
+		if !current.IsActive() {
+			outcome = BookingInactive
+			return nil
+		}
+
+		status := BOOKING_CANCELLED
+		if late && current.Status == BOOKING_BOOKED {
+			status = BOOKING_LATE_CANCELLED
+		}
+		err = tx.Model(&models.ClassBooking{}).Where("id = ?", booking.ID).Updates(map[string]interface{}{
+			"status":       status,
+			"cancelled_at": now,
+			"updated_by":   updatedBy,
+		}).Error
+		if err != nil {
+			return err
+		}
+		booking.Status = status
+		booking.CancelledAt = &now
+		booking.UpdatedBy = int(updatedBy)
+
+		if current.Status != BOOKING_BOOKED || session.Status != CLASS_SESSION_SCHEDULED {
+			return nil
+		}
+		promoted, err = promoteWaitlist(tx, session, now, updatedBy)
+		return err
+	})
+	return outcome, promoted, err
+}
+
+// MarkAttendance records whether the customer of a booking that held a spot
+// came. A recorded attendance can be corrected.
+func (r *BookingRepository) MarkAttendance(booking *models.ClassBooking, status BOOKINGSTATUS, updatedBy uint) (bool, error) {
+	result := r.DB().Model(&models.ClassBooking{}).
+		Where("id = ? AND status IN ?", booking.ID, []BOOKINGSTATUS{BOOKING_BOOKED, BOOKING_ATTENDED, BOOKING_NO_SHOW}).
+		Updates(map[string]interface{}{"status": status, "updated_by": updatedBy})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	booking.Status = status
+	booking.UpdatedBy = int(updatedBy)
+	return true, nil
+}
+
+// FindByID retrieves a booking with its session and customer
+func (r *BookingRepository) FindByID(id uint) (*models.ClassBooking, error) {
+	var booking models.ClassBooking
+	err := r.DB().Preload("Session").Preload("Session.FitService").Preload("Customer").First(&booking, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// ListBySession returns the bookings of a session with their customers, in the
+// order they were made
+func (r *BookingRepository) ListBySession(sessionID uint) ([]models.ClassBooking, error) {
+	var bookings []models.ClassBooking
+	err := r.DB().Preload("Customer").Where("session_id = ?", sessionID).Order("id").Find(&bookings).Error
+	return bookings, err
+}
+
+// ListForUser returns a page of the bookings made for the memberships of a
+// user, in session start order, and the total match count
+func (r *BookingRepository) ListForUser(options BookingListOptions) ([]models.ClassBooking, int64, error) {
+	pagination := r.GetPagination(options.Filters)
+
+	conditions := make(map[string]interface{}, len(options.Filters))
+	for key, value := range options.Filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.BuildQuery(r.DB().Model(&models.ClassBooking{}).
+		Joins("JOIN class_sessions ON class_sessions.id = class_bookings.session_id").
+		Where("class_bookings.customer_id IN (?)", r.DB().Model(&models.Customer{}).Select("id").Where("user_id = ?", options.UserID)),
+		conditions)
+	if options.From != nil {
+		query = query.Where("class_sessions.starts_at >= ?", *options.From)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bookings []models.ClassBooking
+	err := query.Select("class_bookings.*").Preload("Session").Preload("Session.FitService").
+		Order("class_sessions.starts_at, class_bookings.id").
+		Limit(pagination.Limit).Offset(pagination.Offset).Find(&bookings).Error
+	return bookings, total, err
+}
+
+// Occupancy counts the booked and waitlisted customers of the sessions.
+// Sessions without bookings are left out.
+func (r *BookingRepository) Occupancy(sessionIDs []uint) (map[uint]models.SessionOccupancy, error) {
+	occupancy := make(map[uint]models.SessionOccupancy, len(sessionIDs))
+	if len(sessionIDs) == 0 {
+		return occupancy, nil
+	}
+
+	var rows []models.SessionOccupancy
+	err := r.DB().Model(&models.ClassBooking{}).
+		Select("session_id, COUNT(*) FILTER (WHERE status = ?) AS booked, COUNT(*) FILTER (WHERE status = ?) AS waitlisted",
+			BOOKING_BOOKED, BOOKING_WAITLISTED).
+		Where("session_id IN ?", sessionIDs).Group("session_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		occupancy[row.SessionID] = row
+	}
+	return occupancy, nil
+}
+
+// CountStrikes counts the late cancellations and no-shows of a customer on
+// sessions that started since..until
+func (r *BookingRepository) CountStrikes(customerID uint, since, until time.Time) (int64, error) {
+	var strikes int64
+	err := r.DB().Model(&models.ClassBooking{}).
+		Joins("JOIN class_sessions ON class_sessions.id = class_bookings.session_id").
+		Where("class_bookings.customer_id = ? AND class_bookings.status IN ?",
+			customerID, []BOOKINGSTATUS{BOOKING_LATE_CANCELLED, BOOKING_NO_SHOW}).
+		Where("class_sessions.starts_at >= ? AND class_sessions.starts_at < ?", since, until).
+		Count(&strikes).Error
+	return strikes, err
+}
+
+// PlanCovers reports whether a membership plan includes a service. Deleted
+// plans keep covering their services for the customers who bought them.
+func (r *BookingRepository) PlanCovers(planID, serviceID uint) (bool, error) {
+	var count int64
+	err := r.DB().Table("membership_plan_services").
+		Where("membership_plan_id = ? AND fit_service_id = ?", planID, serviceID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveSession saves a session when check passes for its trainer and its
+// capacity still holds its booked customers, and gives any new spots to the
+// waitlist. The promoted bookings are returned.
+func (r *BookingRepository) SaveSession(session *models.ClassSession, now time.Time, check TrainerCheck) (BookingOutcome, []models.ClassBooking, error) {
+	outcome := BookingApplied
+	var promoted []models.ClassBooking
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		// the trainer is locked before the session, in the order template changes lock them
+		if err := checkTrainerSessions(tx, session.TrainerID, []models.ClassSession{*session}, check); err != nil {
+			return err
+		}
+		if _, err := lockSession(tx, session.ID); err != nil {
+			return err
+		}
+		booked, err := countBooked(tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if booked > int64(session.Capacity) {
+			outcome = BookingOverCapacity
+			return nil
+		}
+		if err := tx.Omit("FitService", "Trainer").Save(session).Error; err != nil {
+			return err
+		}
+		promoted, err = promoteWaitlist(tx, session, now, uint(session.UpdatedBy))
+		return err
+	})
+	return outcome, promoted, err
+}
+
+// CancelSession saves a cancelled session and cancels its active bookings,
+// which are returned with their customers
+func (r *BookingRepository) CancelSession(session *models.ClassSession, now time.Time) ([]models.ClassBooking, error) {
+	var cancelled []models.ClassBooking
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := lockSession(tx, session.ID); err != nil {
+			return err
+		}
+		if err := tx.Omit("FitService", "Trainer").Save(session).Error; err != nil {
+			return err
+		}
+
+		err := tx.Preload("Customer").
+			Where("session_id = ? AND status IN ?", session.ID, activeBookingStatuses).
+			Find(&cancelled).Error
+		if err != nil || len(cancelled) == 0 {
+			return err
+		}
+		return tx.Model(&models.ClassBooking{}).
+			Where("session_id = ? AND status IN ?", session.ID, activeBookingStatuses).
+			Updates(map[string]interface{}{
+				"status":       BOOKING_CANCELLED,
+				"cancelled_at": now,
+				"updated_by":   session.UpdatedBy,
+			}).Error
+	})
+	return cancelled, err
+}
+
+// FindPolicy retrieves the booking policy an allie has set
+func (r *BookingRepository) FindPolicy(allieID uint) (*models.BookingPolicy, error) {
+	var policy models.BookingPolicy
+	err := r.DB().Where("allie_id = ?", allieID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SavePolicy creates or updates an allie's booking policy
+func (r *BookingRepository) SavePolicy(policy *models.BookingPolicy) error {
+	if policy.ID != 0 {
+		return r.DB().Save(policy).Error
+	}
+	return r.DB().Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "allie_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"booking_window_days", "cancel_cutoff_minutes", "max_strikes", "strike_window_days", "updated_by", "updated_at",
+		}),
+	}).Create(policy).Error
+}
+
+var activeBookingStatuses = []BOOKINGSTATUS{BOOKING_BOOKED, BOOKING_WAITLISTED}
+
+// lockSession reads a session and locks its row until the transaction ends
+func lockSession(tx *gorm.DB, sessionID uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func countBooked(tx *gorm.DB, sessionID uint) (int64, error) {
+	var booked int64
+	err := tx.Model(&models.ClassBooking{}).
+		Where("session_id = ? AND status = ?", sessionID, BOOKING_BOOKED).
+		Count(&booked).Error
+	return booked, err
+}
+
+// promoteWaitlist gives the free spots of a locked session to its waitlist in
+// the order it was joined, and returns the promoted bookings with their customers
+func promoteWaitlist(tx *gorm.DB, session *models.ClassSession, now time.Time, updatedBy uint) ([]models.ClassBooking, error) {
+	booked, err := countBooked(tx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	free := session.Capacity - int(booked)
+	if free <= 0 {
+		return nil, nil
+	}
+
+	var waiting []models.ClassBooking
+	err = tx.Preload("Customer").
+		Where("session_id = ? AND status = ?", session.ID, BOOKING_WAITLISTED).
+		Order("waitlisted_at, id").Limit(free).Find(&waiting).Error
+	if err != nil || len(waiting) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(waiting))
+	for i := range waiting {
+		ids = append(ids, waiting[i].ID)
+		waiting[i].Status = BOOKING_BOOKED
+		waiting[i].BookedAt = &now
+		waiting[i].UpdatedBy = int(updatedBy)
+	}
+	err = tx.Model(&models.ClassBooking{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":     BOOKING_BOOKED,
+		"booked_at":  now,
+		"updated_by": updatedBy,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return waiting, nil
+}
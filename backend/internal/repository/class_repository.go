@@ -0,0 +1,268 @@
+// internal/repository/class_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClassSessionListOptions narrows a session list. From and To bound the start
+// of the sessions: From included, To excluded.
+type ClassSessionListOptions struct {
+	Filters   map[string]interface{} // paging and BuildQuery conditions
+	CrewID    *uint
+	TrainerID *uint
+	From      *time.Time
+	To        *time.Time
+}
+
+// TrainerCheck inspects the scheduled sessions of a trainer that overlap the
+// sessions about to be written. It runs in the writing transaction with the
+// trainer's row locked, so two writes for one trainer cannot both pass it; an
+// error aborts the write.
+type TrainerCheck func(existing []models.ClassSession) error
+
+// ClassRepositoryInterface defines the contract for class template and session persistence
+type ClassRepositoryInterface interface {
+	CreateTemplate(template *models.ClassTemplate, sessions []models.ClassSession, check TrainerCheck) error
+	FindTemplateByID(id uint) (*models.ClassTemplate, error)
+	ListTemplates(crewID uint) ([]models.ClassTemplate, error)
+	UpdateTemplate(template *models.ClassTemplate, from time.Time, sessions []models.ClassSession, check TrainerCheck) error
+	DeleteTemplate(id uint, from time.Time) error
+	AddSessions(sessions []models.ClassSession, check TrainerCheck) (int64, error)
+	LastSessionStart(templateID uint) (*time.Time, error)
+	BookedGeneratedSessions(templateID uint, from time.Time) ([]models.ClassSession, error)
+
+	FindSessionByID(id uint) (*models.ClassSession, error)
+	UpdateSession(session *models.ClassSession) error
+	ListSessions(options ClassSessionListOptions) ([]models.ClassSession, int64, error)
+	TrainerSessions(trainerID uint, from, to time.Time) ([]models.ClassSession, error)
+}
+
+// ClassRepository implements ClassRepositoryInterface
+type ClassRepository struct {
+	*BaseRepository
+}
+
+// NewClassRepository creates a new ClassRepository instance
+func NewClassRepository(db *gorm.DB) ClassRepositoryInterface {
+	return &ClassRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateTemplate creates the template and its first sessions in one
+// transaction, once check passed for the template's trainer
+func (r *ClassRepository) CreateTemplate(template *models.ClassTemplate, sessions []models.ClassSession, check TrainerCheck) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := checkTrainerSessions(tx, template.TrainerID, sessions, check); err != nil {
+			return err
+		}
+		if err := tx.Omit("FitService", "Trainer").Create(template).Error; err != nil {
+			return err
+		}
+		_, err := insertSessions(tx, template.ID, sessions)
+		return err
+	})
+}
+
+// FindTemplateByID retrieves a template with its service and trainer
+func (r *ClassRepository) FindTemplateByID(id uint) (*models.ClassTemplate, error) {
+	var template models.ClassTemplate
+	err := r.DB().Preload("FitService").Preload("Trainer").First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListTemplates returns the templates of a crew
+func (r *ClassRepository) ListTemplates(crewID uint) ([]models.ClassTemplate, error) {
+	var templates []models.ClassTemplate
+	err := r.DB().Preload("FitService").Preload("Trainer").
+		Where("crew_id = ?", crewID).Order("id").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateTemplate saves the template and replaces its scheduled sessions that
+// start from `from`, were not edited on their own and were never booked with
+// the given ones, once check passed for the template's trainer
+func (r *ClassRepository) UpdateTemplate(template *models.ClassTemplate, from time.Time, sessions []models.ClassSession, check TrainerCheck) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := checkTrainerSessions(tx, template.TrainerID, sessions, check); err != nil {
+			return err
+		}
+		if err := tx.Omit("FitService", "Trainer").Save(template).Error; err != nil {
+			return err
+		}
+		if err := deleteGeneratedSessions(tx, template.ID, from); err != nil {
+			return err
+		}
+		_, err := insertSessions(tx, template.ID, sessions)
+		return err
+	})
+}
+
+// DeleteTemplate removes the template and its scheduled sessions that start
+// from `from`, were not edited on their own and were never booked. Earlier
+// sessions are kept.
+func (r *ClassRepository) DeleteTemplate(id uint, from time.Time) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := deleteGeneratedSessions(tx, id, from); err != nil {
+			return err
+		}
+		return tx.Delete(&models.ClassTemplate{}, id).Error
+	})
+}
+
+// AddSessions inserts generated sessions once check passed for their trainer,
+// skipping the slots that already have one, and returns how many were added
+func (r *ClassRepository) AddSessions(sessions []models.ClassSession, check TrainerCheck) (int64, error) {
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+	var added int64
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := checkTrainerSessions(tx, sessions[0].TrainerID, sessions, check); err != nil {
+			return err
+		}
+		var err error
+		added, err = insertSessions(tx, *sessions[0].TemplateID, sessions)
+		return err
+	})
+	return added, err
+}
+
+// LastSessionStart returns the start of the template's latest session, or nil without sessions
+func (r *ClassRepository) LastSessionStart(templateID uint) (*time.Time, error) {
+	var last *time.Time
+	err := r.DB().Model(&models.ClassSession{}).Where("template_id = ?", templateID).
+		Select("MAX(starts_at)").Scan(&last).Error
+	return last, err
+}
+
+// BookedGeneratedSessions returns the scheduled, unedited sessions of the
+// template from `from` on that have bookings. Template changes leave them to
+// the caller, which cancels them so their customers are told.
+func (r *ClassRepository) BookedGeneratedSessions(templateID uint, from time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.DB().
+		Where("template_id = ? AND starts_at >= ? AND status = ? AND NOT modified", templateID, from, CLASS_SESSION_SCHEDULED).
+		Where("EXISTS (?)", r.DB().Unscoped().Model(&models.ClassBooking{}).Select("1").Where("class_bookings.session_id = class_sessions.id")).
+		Order("starts_at").Find(&sessions).Error
+	return sessions, err
+}
+
+// FindSessionByID retrieves a session with its service and trainer
+func (r *ClassRepository) FindSessionByID(id uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	err := r.DB().Preload("FitService").Preload("Trainer").First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateSession saves a session
+func (r *ClassRepository) UpdateSession(session *models.ClassSession) error {
+	return r.DB().Omit("FitService", "Trainer").Save(session).Error
+}
+
+// ListSessions returns a page of sessions in start order and the total match count
+func (r *ClassRepository) ListSessions(options ClassSessionListOptions) ([]models.ClassSession, int64, error) {
+	pagination := r.GetPagination(options.Filters)
+
+	conditions := make(map[string]interface{}, len(options.Filters))
+	for key, value := range options.Filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.BuildQuery(r.DB().Model(&models.ClassSession{}), conditions)
+	if options.CrewID != nil {
+		query = query.Where("crew_id = ?", *options.CrewID)
+	}
+	if options.TrainerID != nil {
+		query = query.Where("trainer_id = ?", *options.TrainerID)
+	}
+	if options.From != nil {
+		query = query.Where("starts_at >= ?", *options.From)
+	}
+	if options.To != nil {
+		query = query.Where("starts_at < ?", *options.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []models.ClassSession
+	err := query.Preload("FitService").Preload("Trainer").
+		Order("starts_at, id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&sessions).Error
+	return sessions, total, err
+}
+
+// TrainerSessions returns the scheduled sessions of a trainer that run at any
+// moment of from..to
+func (r *ClassRepository) TrainerSessions(trainerID uint, from, to time.Time) ([]models.ClassSession, error) {
+	return trainerSessions(r.DB(), trainerID, from, to)
+}
+
+func trainerSessions(tx *gorm.DB, trainerID uint, from, to time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := tx.
+		Where("trainer_id = ? AND status = ?", trainerID, CLASS_SESSION_SCHEDULED).
+		Where("starts_at < ? AND ends_at > ?", to, from).
+		Order("starts_at").Find(&sessions).Error
+	return sessions, err
+}
+
+// checkTrainerSessions locks the trainer's row and runs check against the
+// trainer's scheduled sessions that overlap the span of the given ones, which
+// are in start order
+func checkTrainerSessions(tx *gorm.DB, trainerID uint, sessions []models.ClassSession, check TrainerCheck) error {
+	if check == nil || len(sessions) == 0 {
+		return nil
+	}
+	var trainer models.TrainerProfile
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&trainer, trainerID).Error; err != nil {
+		return err
+	}
+	existing, err := trainerSessions(tx, trainerID, sessions[0].StartsAt, sessions[len(sessions)-1].EndsAt)
+	if err != nil {
+		return err
+	}
+	return check(existing)
+}
+
+// insertSessions links the sessions to the template and inserts them, leaving
+// alone the slots that already have a session
+func insertSessions(tx *gorm.DB, templateID uint, sessions []models.ClassSession) (int64, error) {
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+	for i := range sessions {
+		sessions[i].TemplateID = &templateID
+	}
+	result := tx.Omit("FitService", "Trainer").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "template_id"}, {Name: "occurs_at"}}, DoNothing: true}).
+		Create(&sessions)
+	return result.RowsAffected, result.Error
+}
+
+// deleteGeneratedSessions removes the scheduled, unedited sessions of the
+// template from `from` on that were never booked. They are deleted for good so
+// their slots can be generated again; sessions with bookings, even cancelled
+// ones, are kept for their bookings to refer to.
+func deleteGeneratedSessions(tx *gorm.DB, templateID uint, from time.Time) error {
+	return tx.Unscoped().
+		Where("template_id = ? AND starts_at >= ? AND status = ? AND NOT modified", templateID, from, CLASS_SESSION_SCHEDULED).
+		Where("NOT EXISTS (?)", tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.ClassBooking{}).Select("1").Where("class_bookings.session_id = class_sessions.id")).
+		Delete(&models.ClassSession{}).Error
+}
@@ -0,0 +1,82 @@
+// internal/repository/crew_schedule_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CrewScheduleRepositoryInterface defines the contract for crew opening hours and holidays
+type CrewScheduleRepositoryInterface interface {
+	FindCrewWithSchedule(crewID uint, holidaysFrom time.Time) (*models.FitCrew, error)
+	ReplaceOpeningHours(crewID uint, hours []models.CrewOpeningHour) error
+	CreateHoliday(holiday *models.CrewHoliday) error
+	FindHolidayByID(id uint) (*models.CrewHoliday, error)
+	DeleteHoliday(id uint) error
+}
+
+// CrewScheduleRepository implements CrewScheduleRepositoryInterface
+type CrewScheduleRepository struct {
+	*BaseRepository
+}
+
+// NewCrewScheduleRepository creates a new CrewScheduleRepository instance
+func NewCrewScheduleRepository(db *gorm.DB) CrewScheduleRepositoryInterface {
+	return &CrewScheduleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindCrewWithSchedule retrieves a crew with its weekly hours and the holidays
+// that have not ended before holidaysFrom
+func (r *CrewScheduleRepository) FindCrewWithSchedule(crewID uint, holidaysFrom time.Time) (*models.FitCrew, error) {
+	var crew models.FitCrew
+	err := r.DB().
+		Preload("OpeningHours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("CASE weekday WHEN 'MONDAY' THEN 1 WHEN 'TUESDAY' THEN 2 WHEN 'WEDNESDAY' THEN 3 " +
+				"WHEN 'THURSDAY' THEN 4 WHEN 'FRIDAY' THEN 5 WHEN 'SATURDAY' THEN 6 ELSE 7 END, opens_at")
+		}).
+		Preload("Holidays", func(db *gorm.DB) *gorm.DB {
+			return db.Where("to_date >= ?", holidaysFrom.Format(time.DateOnly)).Order("from_date, id")
+		}).
+		First(&crew, crewID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &crew, nil
+}
+
+// ReplaceOpeningHours swaps the crew's weekly hours for the given ones in one transaction
+func (r *CrewScheduleRepository) ReplaceOpeningHours(crewID uint, hours []models.CrewOpeningHour) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("crew_id = ?", crewID).Delete(&models.CrewOpeningHour{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// CreateHoliday inserts a holiday of a crew
+func (r *CrewScheduleRepository) CreateHoliday(holiday *models.CrewHoliday) error {
+	return r.DB().Create(holiday).Error
+}
+
+// FindHolidayByID retrieves a holiday by its ID
+func (r *CrewScheduleRepository) FindHolidayByID(id uint) (*models.CrewHoliday, error) {
+	var holiday models.CrewHoliday
+	err := r.DB().First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// DeleteHoliday removes a holiday by its ID
+func (r *CrewScheduleRepository) DeleteHoliday(id uint) error {
+	return r.DB().Delete(&models.CrewHoliday{}, id).Error
+}
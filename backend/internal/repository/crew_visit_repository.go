@@ -0,0 +1,174 @@
+// internal/repository/crew_visit_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckInOutcome tells how a check-in went
+type CheckInOutcome int
+
+const (
+	CheckInApplied CheckInOutcome = iota // the visit was recorded
+	CheckInFull                          // the crew is at capacity
+	CheckInInside                        // the customer is already inside
+)
+
+// CrewVisitRepositoryInterface defines the contract for check-in and attendance persistence.
+// Open visits checked in before the `since` passed in no longer count as inside.
+type CrewVisitRepositoryInterface interface {
+	CheckIn(visit *models.CrewVisit, capacity int, since time.Time) (CheckInOutcome, error)
+	CheckOut(crewID int, customerID uint, since, now time.Time, checkedOutBy uint) (*models.CrewVisit, error)
+	CurrentVisits(crewID uint, since time.Time) ([]models.CrewVisit, error)
+	CountVisits(customerID uint, from, to time.Time) (int64, error)
+	ListByCustomer(customerID uint, filters map[string]interface{}, from, to *time.Time) ([]models.CrewVisit, int64, error)
+	RedeemCode(code *models.RedeemedCheckInCode, now time.Time) (bool, error)
+}
+
+// CrewVisitRepository implements CrewVisitRepositoryInterface
+type CrewVisitRepository struct {
+	*BaseRepository
+}
+
+// NewCrewVisitRepository creates a new CrewVisitRepository instance
+func NewCrewVisitRepository(db *gorm.DB) CrewVisitRepositoryInterface {
+	return &CrewVisitRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CheckIn records the visit unless the customer is already inside or the crew
+// holds capacity customers; a capacity of 0 is unlimited. The crew row is
+// locked so concurrent check-ins cannot overfill it. Open visits of the
+// customer from before `since` are closed on the way.
+func (r *CrewVisitRepository) CheckIn(visit *models.CrewVisit, capacity int, since time.Time) (CheckInOutcome, error) {
+	outcome := CheckInApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		var crew models.FitCrew
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&crew, visit.CrewID).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&models.CrewVisit{}).
+			Where("customer_id = ? AND checked_out_at IS NULL AND checked_in_at <= ?", visit.CustomerID, since).
+			Updates(map[string]interface{}{"checked_out_at": visit.CheckedInAt, "auto_closed": true}).Error
+		if err != nil {
+			return err
+		}
+
+		var inside int64
+		err = openVisits(tx, since).Where("customer_id = ?", visit.CustomerID).Count(&inside).Error
+		if err != nil {
+			return err
+		}
+		if inside > 0 {
+			outcome = CheckInInside
+			return nil
+		}
+
+		if capacity > 0 {
+			var occupancy int64
+			if err := openVisits(tx, since).Where("crew_id = ?", visit.CrewID).Count(&occupancy).Error; err != nil {
+				return err
+			}
+			if occupancy >= int64(capacity) {
+				outcome = CheckInFull
+				return nil
+			}
+		}
+		return tx.Omit("Customer").Create(visit).Error
+	})
+	return outcome, err
+}
+
+// CheckOut closes the customer's open visit at the crew and returns it
+func (r *CrewVisitRepository) CheckOut(crewID int, customerID uint, since, now time.Time, checkedOutBy uint) (*models.CrewVisit, error) {
+	var visit models.CrewVisit
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		err := openVisits(tx, since).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("crew_id = ? AND customer_id = ?", crewID, customerID).
+			Order("checked_in_at DESC").First(&visit).Error
+		if err != nil {
+			return err
+		}
+		visit.CheckedOutAt = &now
+		visit.CheckedOutBy = int(checkedOutBy)
+		return tx.Model(&visit).Updates(map[string]interface{}{
+			"checked_out_at": now,
+			"checked_out_by": checkedOutBy,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &visit, nil
+}
+
+// CurrentVisits returns the visits inside the crew with their customers, earliest first
+func (r *CrewVisitRepository) CurrentVisits(crewID uint, since time.Time) ([]models.CrewVisit, error) {
+	var visits []models.CrewVisit
+	err := openVisits(r.DB(), since).Preload("Customer").
+		Where("crew_id = ?", crewID).Order("checked_in_at").Find(&visits).Error
+	return visits, err
+}
+
+// CountVisits counts the visits of a customer checked in from..to, to excluded
+func (r *CrewVisitRepository) CountVisits(customerID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.DB().Model(&models.CrewVisit{}).
+		Where("customer_id = ? AND checked_in_at >= ? AND checked_in_at < ?", customerID, from, to).
+		Count(&count).Error
+	return count, err
+}
+
+// ListByCustomer returns a page of a customer's visits, latest first, and the
+// total match count. from and to bound the check-in: from included, to excluded.
+func (r *CrewVisitRepository) ListByCustomer(customerID uint, filters map[string]interface{}, from, to *time.Time) ([]models.CrewVisit, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	query := r.DB().Model(&models.CrewVisit{}).Where("customer_id = ?", customerID)
+	if from != nil {
+		query = query.Where("checked_in_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("checked_in_at < ?", *to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var visits []models.CrewVisit
+	err := query.Order("checked_in_at DESC, id DESC").
+		Limit(pagination.Limit).Offset(pagination.Offset).Find(&visits).Error
+	return visits, total, err
+}
+
+// RedeemCode records a check-in code as used and reports false when it was
+// already redeemed. Records of codes expired by now are purged on the way.
+func (r *CrewVisitRepository) RedeemCode(code *models.RedeemedCheckInCode, now time.Time) (bool, error) {
+	redeemed := false
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("expires_at < ?", now).Delete(&models.RedeemedCheckInCode{}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "token_id"}}, DoNothing: true}).Create(code)
+		if result.Error != nil {
+			return result.Error
+		}
+		redeemed = result.RowsAffected == 1
+		return nil
+	})
+	return redeemed, err
+}
+
+// openVisits selects the visits without a check-out that started after since
+func openVisits(tx *gorm.DB, since time.Time) *gorm.DB {
+	return tx.Model(&models.CrewVisit{}).Where("checked_out_at IS NULL AND checked_in_at > ?", since)
+}
@@ -0,0 +1,131 @@
+// internal/repository/customer_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CustomerRepositoryInterface defines the contract for crew membership persistence
+type CustomerRepositoryInterface interface {
+	Enroll(user *models.User, customer *models.Customer, history *models.MembershipHistory, order *models.PaymentOrder) error
+	FindByID(id uint) (*models.Customer, error)
+	FindByUserAndCrew(userID uint, crewID int) (*models.Customer, error)
+	SaveWithHistory(customer *models.Customer, history *models.MembershipHistory) error
+	List(crewID uint, filters map[string]interface{}, search string) ([]models.Customer, int64, error)
+	ListHistory(customerID uint) ([]models.MembershipHistory, error)
+	SyncActive(crewID uint, now time.Time) error
+}
+
+// CustomerRepository implements CustomerRepositoryInterface
+type CustomerRepository struct {
+	*BaseRepository
+}
+
+// NewCustomerRepository creates a new CustomerRepository instance
+func NewCustomerRepository(db *gorm.DB) CustomerRepositoryInterface {
+	return &CustomerRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Enroll creates the customer, its first history entry and the order of its
+// first term in one transaction. A user without an ID is created first and
+// linked to the customer.
+func (r *CustomerRepository) Enroll(user *models.User, customer *models.Customer, history *models.MembershipHistory, order *models.PaymentOrder) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if user.ID == 0 {
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+		}
+		customer.UserID = user.ID
+		if err := tx.Create(customer).Error; err != nil {
+			return err
+		}
+		history.CustomerID = customer.ID
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+		order.CustomerID = customer.ID
+		return tx.Omit("Customer", "Attempts", "Refunds").Create(order).Error
+	})
+}
+
+// FindByID retrieves a customer by its ID
+func (r *CustomerRepository) FindByID(id uint) (*models.Customer, error) {
+	var customer models.Customer
+	err := r.DB().First(&customer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// FindByUserAndCrew retrieves the membership a user holds at a crew
+func (r *CustomerRepository) FindByUserAndCrew(userID uint, crewID int) (*models.Customer, error) {
+	var customer models.Customer
+	err := r.DB().Where("user_id = ? AND crew_id = ?", userID, crewID).First(&customer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// SaveWithHistory saves the customer and records the transition together
+func (r *CustomerRepository) SaveWithHistory(customer *models.Customer, history *models.MembershipHistory) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(customer).Error; err != nil {
+			return err
+		}
+		history.CustomerID = customer.ID
+		return tx.Create(history).Error
+	})
+}
+
+// List returns a page of a crew's customers matching the filters and the total match count
+func (r *CustomerRepository) List(crewID uint, filters map[string]interface{}, search string) ([]models.Customer, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.BuildQuery(r.DB().Model(&models.Customer{}).Where("crew_id = ?", crewID), conditions)
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("first_name ILIKE ? OR last_name ILIKE ? OR mobile ILIKE ? OR email ILIKE ?", pattern, pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var customers []models.Customer
+	err := query.Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&customers).Error
+	return customers, total, err
+}
+
+// ListHistory returns a customer's membership transitions, oldest first
+func (r *CustomerRepository) ListHistory(customerID uint) ([]models.MembershipHistory, error) {
+	var history []models.MembershipHistory
+	err := r.DB().Where("customer_id = ?", customerID).Order("id").Find(&history).Error
+	return history, err
+}
+
+// SyncActive brings the stored is_active flags of a crew's customers in line
+// with their dates at now, mirroring Customer.MembershipActiveAt.
+func (r *CustomerRepository) SyncActive(crewID uint, now time.Time) error {
+	return r.DB().Model(&models.Customer{}).
+		Where("crew_id = ?", crewID).
+		Update("is_active", gorm.Expr(
+			"cancelled_at IS NULL AND NOT awaiting_payment AND membership_start <= ? AND membership_end > ? AND "+
+				"NOT (frozen_from IS NOT NULL AND frozen_until IS NOT NULL AND frozen_from <= ? AND frozen_until > ?)",
+			now, now, now, now)).Error
+}
@@ -0,0 +1,121 @@
+// internal/repository/fit_allie_repository.go
+package repository
+
+import (
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+// FitAllieRepositoryInterface defines the contract for gym partner persistence
+type FitAllieRepositoryInterface interface {
+	Create(allie *models.FitAllie) error
+	CreateWithOwner(allie *models.FitAllie, owner *models.User) error
+	FindByID(id uint) (*models.FitAllie, error)
+	Update(allie *models.FitAllie) error
+	Delete(id uint) error
+	List(filters map[string]interface{}, search string) ([]models.FitAllie, int64, error)
+}
+
+// FitAllieRepository implements FitAllieRepositoryInterface
+type FitAllieRepository struct {
+	*BaseRepository
+}
+
+// NewFitAllieRepository creates a new FitAllieRepository instance
+func NewFitAllieRepository(db *gorm.DB) FitAllieRepositoryInterface {
+	return &FitAllieRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new allie
+func (r *FitAllieRepository) Create(allie *models.FitAllie) error {
+	return r.DB().Create(allie).Error
+}
+
+// CreateWithOwner inserts the allie's login user and the allie in one
+// transaction, linking the allie to the new user
+func (r *FitAllieRepository) CreateWithOwner(allie *models.FitAllie, owner *models.User) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(owner).Error; err != nil {
+			return err
+		}
+		allie.UserID = int(owner.ID)
+		return tx.Create(allie).Error
+	})
+}
+
+// FindByID retrieves an allie by its ID
+func (r *FitAllieRepository) FindByID(id uint) (*models.FitAllie, error) {
+	var allie models.FitAllie
+	err := r.DB().First(&allie, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &allie, nil
+}
+
+// Update saves changes to an existing allie
+func (r *FitAllieRepository) Update(allie *models.FitAllie) error {
+	return r.Save(allie)
+}
+
+// Delete removes an allie by its ID. In the same transaction its crews, their
+// trainers and its GYM owner user are deactivated, so nothing of the allie
+// stays usable.
+func (r *FitAllieRepository) Delete(id uint) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		var allie models.FitAllie
+		if err := tx.First(&allie, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.FitCrew{}).Where("allie_id = ?", id).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.TrainerProfile{}).
+			Where("crew_id IN (?)", tx.Model(&models.FitCrew{}).Select("id").Where("allie_id = ?", id)).
+			Update("crew_inactive", true).Error
+		if err != nil {
+			return err
+		}
+		if allie.UserID != 0 {
+			err := tx.Model(&models.User{}).
+				Where("id = ? AND user_type = ?", allie.UserID, GYM).
+				Update("is_active", false).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&allie).Error
+	})
+}
+
+// List returns a page of allies matching the filters, together with the total
+// number of matches. search matches the business or owner name. The "offset"
+// (page) and "limit" keys select the page; every other key is a condition.
+func (r *FitAllieRepository) List(filters map[string]interface{}, search string) ([]models.FitAllie, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.BuildQuery(r.DB().Model(&models.FitAllie{}), conditions)
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("gym_name ILIKE ? OR owner_first_name ILIKE ? OR owner_last_name ILIKE ?", pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var allies []models.FitAllie
+	err := query.Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&allies).Error
+	return allies, total, err
+}
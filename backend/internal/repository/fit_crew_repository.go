@@ -0,0 +1,239 @@
+// internal/repository/fit_crew_repository.go
+package repository
+
+import (
+	"math"
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FitCrewRepositoryInterface defines the contract for gym branch persistence
+type FitCrewRepositoryInterface interface {
+	CreateWithinLimit(crew *models.FitCrew, limit int) (bool, error)
+	FindByID(id uint) (*models.FitCrew, error)
+	Update(crew *models.FitCrew) error
+	Delete(crew *models.FitCrew) error
+	ListByAllie(allieID uint) ([]models.FitCrew, error)
+	Search(filters map[string]interface{}, search string, serviceID *uint) ([]models.FitCrew, int64, error)
+	Nearby(options NearbyOptions) ([]models.FitCrewDistance, int64, error)
+}
+
+// NearbyOptions narrows the nearby search. OpenAt, when set, keeps only the
+// crews open at that time on their own clock. VisitsSince, when set, keeps only
+// the crews with room left, counting the open visits checked in after it.
+type NearbyOptions struct {
+	Filters     map[string]interface{}
+	Lat         float64
+	Lng         float64
+	RadiusKm    float64
+	ServiceID   *uint
+	OpenAt      *time.Time
+	VisitsSince *time.Time
+}
+
+// FitCrewRepository implements FitCrewRepositoryInterface
+type FitCrewRepository struct {
+	*BaseRepository
+}
+
+// NewFitCrewRepository creates a new FitCrewRepository instance
+func NewFitCrewRepository(db *gorm.DB) FitCrewRepositoryInterface {
+	return &FitCrewRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateWithinLimit inserts the crew unless its allie already has limit crews.
+// The allie row is locked so concurrent creations cannot both pass the check.
+func (r *FitCrewRepository) CreateWithinLimit(crew *models.FitCrew, limit int) (bool, error) {
+	created := false
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		var allie models.FitAllie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&allie, crew.AllieID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.FitCrew{}).Where("allie_id = ?", crew.AllieID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			return nil
+		}
+
+		if err := tx.Create(crew).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// FindByID retrieves a crew by its ID
+func (r *FitCrewRepository) FindByID(id uint) (*models.FitCrew, error) {
+	var crew models.FitCrew
+	err := r.DB().First(&crew, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &crew, nil
+}
+
+// Update saves the crew and hides or shows its trainers to match IsActive
+func (r *FitCrewRepository) Update(crew *models.FitCrew) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(crew).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TrainerProfile{}).
+			Where("crew_id = ?", crew.ID).
+			Update("crew_inactive", !crew.IsActive).Error
+	})
+}
+
+// Delete removes the crew and hides its trainers
+func (r *FitCrewRepository) Delete(crew *models.FitCrew) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(crew).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TrainerProfile{}).
+			Where("crew_id = ?", crew.ID).
+			Update("crew_inactive", true).Error
+	})
+}
+
+// ListByAllie returns every crew of an allie
+func (r *FitCrewRepository) ListByAllie(allieID uint) ([]models.FitCrew, error) {
+	var crews []models.FitCrew
+	err := r.DB().Where("allie_id = ?", allieID).Order("id").Find(&crews).Error
+	return crews, err
+}
+
+// Search returns a page of the active crews of active allies matching the
+// filters, optionally only those where the service is offered, and the total
+// match count
+func (r *FitCrewRepository) Search(filters map[string]interface{}, search string, serviceID *uint) ([]models.FitCrew, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.discoverable(r.BuildQuery(r.DB().Model(&models.FitCrew{}), conditions), serviceID)
+	if search != "" {
+		query = query.Where("gym_name ILIKE ?", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var crews []models.FitCrew
+	err := query.Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&crews).Error
+	return crews, total, err
+}
+
+// Nearby returns a page of the discoverable crews within the radius of the
+// point, nearest first, and the total match count. A bounding box on the indexed
+// coordinates narrows the rows before the haversine distance is computed.
+func (r *FitCrewRepository) Nearby(options NearbyOptions) ([]models.FitCrewDistance, int64, error) {
+	pagination := r.GetPagination(options.Filters)
+	lat, lng, radiusKm := options.Lat, options.Lng, options.RadiusKm
+
+	distance := "2 * ? * ASIN(SQRT(LEAST(1, POWER(SIN(RADIANS(latitude - ?) / 2), 2) + " +
+		"COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2))))"
+	distanceArgs := []interface{}{earthRadiusKm, lat, lat, lng}
+
+	query := r.discoverable(r.DB().Model(&models.FitCrew{}), options.ServiceID).
+		Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+	query = withinBoundingBox(query, lat, lng, radiusKm).
+		Where(distance+" <= ?", append(distanceArgs, radiusKm)...)
+	if options.OpenAt != nil {
+		query = r.openAt(query, *options.OpenAt)
+	}
+	if options.VisitsSince != nil {
+		query = query.Where("(fit_crews.capacity <= 0 OR fit_crews.capacity > (?))",
+			openVisits(r.DB(), *options.VisitsSince).Select("COUNT(*)").Where("crew_visits.crew_id = fit_crews.id"))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var crews []models.FitCrewDistance
+	err := query.Select("fit_crews.*, "+distance+" AS distance_km", distanceArgs...).
+		Order("distance_km, id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&crews).Error
+	return crews, total, err
+}
+
+// discoverable limits the query to the active crews of active allies and, with
+// a service, to the crews where that service is offered allie-wide or by the crew
+func (r *FitCrewRepository) discoverable(query *gorm.DB, serviceID *uint) *gorm.DB {
+	query = query.Where("fit_crews.is_active").
+		Where("fit_crews.allie_id IN (?)", r.DB().Model(&models.FitAllie{}).Select("id").Where("is_active"))
+	if serviceID != nil {
+		query = query.Where("EXISTS (?)", r.DB().Model(&models.FitAllieService{}).Select("1").
+			Where("fit_allie_offerings.is_active AND fit_allie_offerings.fit_service_id = ?", *serviceID).
+			Where("fit_allie_offerings.fit_allie_id = fit_crews.allie_id").
+			Where("fit_allie_offerings.crew_id IS NULL OR fit_allie_offerings.crew_id = fit_crews.id"))
+	}
+	return query
+}
+
+// openAt keeps the crews open at t, mirroring FitCrew.OpenAt: t is read on
+// each crew's clock, and holidays covering that date replace its weekly hours
+func (r *FitCrewRepository) openAt(query *gorm.DB, t time.Time) *gorm.DB {
+	local := "(?::timestamptz AT TIME ZONE fit_crews.timezone)"
+	hhmm := "TO_CHAR(" + local + ", 'HH24:MI')"
+	holidays := r.DB().Model(&models.CrewHoliday{}).Select("1").
+		Where("crew_holidays.crew_id = fit_crews.id").
+		Where(local+"::date BETWEEN crew_holidays.from_date AND crew_holidays.to_date", t)
+	return query.Where("CASE WHEN EXISTS (?) THEN EXISTS (?) ELSE EXISTS (?) END",
+		holidays,
+		holidays.Session(&gorm.Session{}).
+			Where("crew_holidays.opens_at <= "+hhmm+" AND "+hhmm+" < crew_holidays.closes_at", t, t),
+		r.DB().Model(&models.CrewOpeningHour{}).Select("1").
+			Where("crew_opening_hours.crew_id = fit_crews.id").
+			Where("crew_opening_hours.weekday = TO_CHAR("+local+", 'FMDAY')", t).
+			Where("crew_opening_hours.opens_at <= "+hhmm+" AND "+hhmm+" < crew_opening_hours.closes_at", t, t),
+	)
+}
+
+const (
+	earthRadiusKm = 6371.0
+	kmPerDegree   = earthRadiusKm * math.Pi / 180
+)
+
+// withinBoundingBox keeps the rows whose coordinates fall in the box around the
+// circle of radiusKm. Longitude is left unbounded near the poles, and the box
+// wraps when it crosses the antimeridian.
+func withinBoundingBox(query *gorm.DB, lat, lng, radiusKm float64) *gorm.DB {
+	latDelta := radiusKm / kmPerDegree
+	query = query.Where("latitude BETWEEN ? AND ?", math.Max(lat-latDelta, -90), math.Min(lat+latDelta, 90))
+	if lat-latDelta <= -90 || lat+latDelta >= 90 {
+		return query
+	}
+
+	lngDelta := latDelta / math.Cos(lat*math.Pi/180)
+	minLng, maxLng := lng-lngDelta, lng+lngDelta
+	switch {
+	case lngDelta >= 180:
+		return query
+	case minLng < -180:
+		return query.Where("longitude >= ? OR longitude <= ?", minLng+360, maxLng)
+	case maxLng > 180:
+		return query.Where("longitude >= ? OR longitude <= ?", minLng, maxLng-360)
+	default:
+		return query.Where("longitude BETWEEN ? AND ?", minLng, maxLng)
+	}
+}
@@ -0,0 +1,153 @@
+// internal/repository/fit_service_repository.go
+package repository
+
+import (
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// FitServiceRepositoryInterface defines the contract for the service catalog and allie offerings
+type FitServiceRepositoryInterface interface {
+	Create(service *models.FitService) error
+	FindByID(id uint) (*models.FitService, error)
+	FindByName(name string) (*models.FitService, error)
+	Update(service *models.FitService) error
+	Delete(id uint) error
+	List(category string, activeOnly bool, search string) ([]models.FitService, error)
+
+	CreateOffering(offering *models.FitAllieService) error
+	FindOfferingByID(id uint) (*models.FitAllieService, error)
+	FindOffering(allieID, serviceID uint, crewID *int) (*models.FitAllieService, error)
+	UpdateOffering(offering *models.FitAllieService) error
+	DeleteOffering(id uint) error
+	ListOfferings(allieID uint, crewID *uint) ([]models.FitAllieService, error)
+}
+
+// FitServiceRepository implements FitServiceRepositoryInterface
+type FitServiceRepository struct {
+	*BaseRepository
+}
+
+// NewFitServiceRepository creates a new FitServiceRepository instance
+func NewFitServiceRepository(db *gorm.DB) FitServiceRepositoryInterface {
+	return &FitServiceRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a service into the catalog
+func (r *FitServiceRepository) Create(service *models.FitService) error {
+	return r.DB().Create(service).Error
+}
+
+// FindByID retrieves a service by its ID
+func (r *FitServiceRepository) FindByID(id uint) (*models.FitService, error) {
+	var service models.FitService
+	err := r.DB().First(&service, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// FindByName retrieves a service by its name, ignoring case
+func (r *FitServiceRepository) FindByName(name string) (*models.FitService, error) {
+	var service models.FitService
+	err := r.DB().Where("LOWER(name) = LOWER(?)", name).First(&service).Error
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// Update saves a catalog service
+func (r *FitServiceRepository) Update(service *models.FitService) error {
+	return r.DB().Save(service).Error
+}
+
+// Delete removes a service and every offering of it
+func (r *FitServiceRepository) Delete(id uint) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("fit_service_id = ?", id).Delete(&models.FitAllieService{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FitService{}, id).Error
+	})
+}
+
+// List returns the catalog ordered by category and name
+func (r *FitServiceRepository) List(category string, activeOnly bool, search string) ([]models.FitService, error) {
+	query := r.DB().Model(&models.FitService{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if activeOnly {
+		query = query.Where("is_active")
+	}
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	var services []models.FitService
+	err := query.Order("category, name").Find(&services).Error
+	return services, err
+}
+
+// CreateOffering inserts an allie offering
+func (r *FitServiceRepository) CreateOffering(offering *models.FitAllieService) error {
+	if err := r.DB().Omit("FitAllie", "FitService").Create(offering).Error; err != nil {
+		return err
+	}
+	return r.DB().Model(offering).Association("FitService").Find(&offering.FitService)
+}
+
+// FindOfferingByID retrieves an offering and its service
+func (r *FitServiceRepository) FindOfferingByID(id uint) (*models.FitAllieService, error) {
+	var offering models.FitAllieService
+	err := r.DB().Preload("FitService").First(&offering, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
+
+// FindOffering retrieves the offering of a service by an allie at a crew, or
+// allie-wide when crewID is nil
+func (r *FitServiceRepository) FindOffering(allieID, serviceID uint, crewID *int) (*models.FitAllieService, error) {
+	query := r.DB().Where("fit_allie_id = ? AND fit_service_id = ?", allieID, serviceID)
+	if crewID == nil {
+		query = query.Where("crew_id IS NULL")
+	} else {
+		query = query.Where("crew_id = ?", *crewID)
+	}
+
+	var offering models.FitAllieService
+	err := query.First(&offering).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
+
+// UpdateOffering saves an offering
+func (r *FitServiceRepository) UpdateOffering(offering *models.FitAllieService) error {
+	return r.DB().Omit("FitAllie", "FitService").Save(offering).Error
+}
+
+// DeleteOffering removes an offering by its ID
+func (r *FitServiceRepository) DeleteOffering(id uint) error {
+	return r.DB().Delete(&models.FitAllieService{}, id).Error
+}
+
+// ListOfferings returns an allie's offerings. With a crew, only the offerings
+// available there are returned: the allie-wide ones and the crew's own.
+func (r *FitServiceRepository) ListOfferings(allieID uint, crewID *uint) ([]models.FitAllieService, error) {
+	query := r.DB().Preload("FitService").Where("fit_allie_id = ?", allieID)
+	if crewID != nil {
+		query = query.Where("crew_id IS NULL OR crew_id = ?", *crewID)
+	}
+
+	var offerings []models.FitAllieService
+	err := query.Order("fit_service_id, crew_id NULLS FIRST").Find(&offerings).Error
+	return offerings, err
+}
@@ -0,0 +1,29 @@
+// internal/repository/impersonation_repository.go
+package repository
+
+import (
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ImpersonationRepositoryInterface defines the contract for the impersonation audit trail
+type ImpersonationRepositoryInterface interface {
+	Create(impersonation *models.Impersonation) error
+}
+
+// ImpersonationRepository implements ImpersonationRepositoryInterface
+type ImpersonationRepository struct {
+	*BaseRepository
+}
+
+// NewImpersonationRepository creates a new ImpersonationRepository instance
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepositoryInterface {
+	return &ImpersonationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new impersonation record
+func (r *ImpersonationRepository) Create(impersonation *models.Impersonation) error {
+	return r.DB().Create(impersonation).Error
+}
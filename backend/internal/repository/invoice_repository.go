@@ -0,0 +1,141 @@
+// internal/repository/invoice_repository.go
+package repository
+
+import (
+	"errors"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvoiceRepositoryInterface defines the contract for issued invoices and
+// credit notes. Nothing here changes a document once it is issued.
+type InvoiceRepositoryInterface interface {
+	Issue(invoice *models.Invoice) (*models.Invoice, bool, error)
+	FindByID(id uint) (*models.Invoice, error)
+	ListForOrder(orderID uint) ([]models.Invoice, error)
+	List(crewID uint, filters map[string]interface{}) ([]models.Invoice, int64, error)
+	ListForUser(userID uint, filters map[string]interface{}) ([]models.Invoice, int64, error)
+}
+
+// InvoiceRepository implements InvoiceRepositoryInterface
+type InvoiceRepository struct {
+	*BaseRepository
+}
+
+// NewInvoiceRepository creates a new InvoiceRepository instance
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepositoryInterface {
+	return &InvoiceRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Issue numbers and stores an invoice, or a credit note when it has a refund,
+// unless the order's invoice or the refund's credit note was issued before. It
+// returns the stored document and whether it was issued now. The order row is
+// locked so a document is issued once, and the sequence row of the series is
+// locked and advanced in the same transaction, so a number is only used when
+// the document is stored.
+func (r *InvoiceRepository) Issue(invoice *models.Invoice) (*models.Invoice, bool, error) {
+	stored := invoice
+	issued := false
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := lockOrder(tx, invoice.OrderID); err != nil {
+			return err
+		}
+
+		var existing models.Invoice
+		query := tx.Where("order_id = ? AND kind = ?", invoice.OrderID, invoice.Kind)
+		if invoice.RefundID != nil {
+			query = query.Where("refund_id = ?", *invoice.RefundID)
+		}
+		err := query.First(&existing).Error
+		if err == nil {
+			stored = &existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		sequence := models.InvoiceSequence{AllieID: invoice.AllieID, Kind: invoice.Kind, FiscalYear: invoice.FiscalYear}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "allie_id"}, {Name: "kind"}, {Name: "fiscal_year"}},
+			DoNothing: true,
+		}).Create(&sequence).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("allie_id = ? AND kind = ? AND fiscal_year = ?", invoice.AllieID, invoice.Kind, invoice.FiscalYear).
+			First(&sequence).Error
+		if err != nil {
+			return err
+		}
+		next := sequence.LastNumber + 1
+		if err := tx.Model(&sequence).Update("last_number", next).Error; err != nil {
+			return err
+		}
+
+		invoice.AssignNumber(next)
+		if err := tx.Create(invoice).Error; err != nil {
+			return err
+		}
+		issued = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, issued, nil
+}
+
+// FindByID retrieves an invoice or credit note
+func (r *InvoiceRepository) FindByID(id uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.DB().First(&invoice, id).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// ListForOrder returns the invoice and credit notes of an order in the order they were issued
+func (r *InvoiceRepository) ListForOrder(orderID uint) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	err := r.DB().Where("order_id = ?", orderID).Order("issued_at, id").Find(&invoices).Error
+	return invoices, err
+}
+
+// List returns a page of a crew's invoices and credit notes, latest first, and the total match count
+func (r *InvoiceRepository) List(crewID uint, filters map[string]interface{}) ([]models.Invoice, int64, error) {
+	return r.listInvoices(r.DB().Model(&models.Invoice{}).Where("crew_id = ?", crewID), filters)
+}
+
+// ListForUser returns a page of the invoices and credit notes of every membership the user holds
+func (r *InvoiceRepository) ListForUser(userID uint, filters map[string]interface{}) ([]models.Invoice, int64, error) {
+	return r.listInvoices(r.DB().Model(&models.Invoice{}).
+		Where("customer_id IN (?)", r.DB().Model(&models.Customer{}).Select("id").Where("user_id = ?", userID)), filters)
+}
+
+func (r *InvoiceRepository) listInvoices(query *gorm.DB, filters map[string]interface{}) ([]models.Invoice, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+	query = r.BuildQuery(query, conditions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []models.Invoice
+	err := query.Order("issued_at DESC, id DESC").
+		Limit(pagination.Limit).Offset(pagination.Offset).Find(&invoices).Error
+	return invoices, total, err
+}
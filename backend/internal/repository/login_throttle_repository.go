@@ -0,0 +1,75 @@
+// internal/repository/login_throttle_repository.go
+package repository
+
+import (
+	"errors"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginThrottleRepositoryInterface defines the contract for failed-login counters
+type LoginThrottleRepositoryInterface interface {
+	Find(scope, key string) (*models.LoginThrottle, error)
+	Update(scope, key string, fn func(throttle *models.LoginThrottle)) (*models.LoginThrottle, error)
+	Reset(scope, key string) error
+}
+
+// LoginThrottleRepository implements LoginThrottleRepositoryInterface
+type LoginThrottleRepository struct {
+	*BaseRepository
+}
+
+// NewLoginThrottleRepository creates a new LoginThrottleRepository instance
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepositoryInterface {
+	return &LoginThrottleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Find returns the counter for scope and key, or an empty counter if none exists
+func (r *LoginThrottleRepository) Find(scope, key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.DB().Where("scope = ? AND key = ?", scope, key).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.LoginThrottle{Scope: scope, Key: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Update applies fn to the counter while holding a row lock, creating it if needed
+func (r *LoginThrottleRepository) Update(scope, key string, fn func(throttle *models.LoginThrottle)) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		seed := models.LoginThrottle{Scope: scope, Key: key}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("scope = ? AND key = ?", scope, key).
+			First(&throttle).Error; err != nil {
+			return err
+		}
+		fn(&throttle)
+		return tx.Save(&throttle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Reset clears the counter for scope and key
+func (r *LoginThrottleRepository) Reset(scope, key string) error {
+	return r.DB().Model(&models.LoginThrottle{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Updates(map[string]interface{}{
+			"failed_count":   0,
+			"last_failed_at": nil,
+			"locked_until":   nil,
+		}).Error
+}
@@ -0,0 +1,86 @@
+// internal/repository/membership_plan_repository.go
+package repository
+
+import (
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// MembershipPlanRepositoryInterface defines the contract for membership plan persistence
+type MembershipPlanRepositoryInterface interface {
+	Create(plan *models.MembershipPlan) error
+	FindByID(id uint) (*models.MembershipPlan, error)
+	Update(plan *models.MembershipPlan) error
+	Delete(id uint) error
+	ListByAllie(allieID uint, crewID *uint, activeOnly bool) ([]models.MembershipPlan, error)
+	FindServices(ids []uint) ([]models.FitService, error)
+}
+
+// MembershipPlanRepository implements MembershipPlanRepositoryInterface
+type MembershipPlanRepository struct {
+	*BaseRepository
+}
+
+// NewMembershipPlanRepository creates a new MembershipPlanRepository instance
+func NewMembershipPlanRepository(db *gorm.DB) MembershipPlanRepositoryInterface {
+	return &MembershipPlanRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a plan together with its included services
+func (r *MembershipPlanRepository) Create(plan *models.MembershipPlan) error {
+	return r.DB().Create(plan).Error
+}
+
+// FindByID retrieves a plan and its included services
+func (r *MembershipPlanRepository) FindByID(id uint) (*models.MembershipPlan, error) {
+	var plan models.MembershipPlan
+	err := r.DB().Preload("Services").First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Update saves the plan and replaces its included services
+func (r *MembershipPlanRepository) Update(plan *models.MembershipPlan) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Services").Save(plan).Error; err != nil {
+			return err
+		}
+		return tx.Model(plan).Association("Services").Replace(plan.Services)
+	})
+}
+
+// Delete removes a plan by its ID. Customers keep the plan reference and the
+// price they paid.
+func (r *MembershipPlanRepository) Delete(id uint) error {
+	return r.DB().Delete(&models.MembershipPlan{}, id).Error
+}
+
+// ListByAllie returns an allie's plans. With a crew, only the plans sold at that
+// crew are returned: the allie-wide ones and the crew's own.
+func (r *MembershipPlanRepository) ListByAllie(allieID uint, crewID *uint, activeOnly bool) ([]models.MembershipPlan, error) {
+	query := r.DB().Preload("Services").Where("allie_id = ?", allieID)
+	if crewID != nil {
+		query = query.Where("crew_id IS NULL OR crew_id = ?", *crewID)
+	}
+	if activeOnly {
+		query = query.Where("is_active")
+	}
+
+	var plans []models.MembershipPlan
+	err := query.Order("crew_id NULLS FIRST, price_minor, id").Find(&plans).Error
+	return plans, err
+}
+
+// FindServices retrieves the services with the given IDs
+func (r *MembershipPlanRepository) FindServices(ids []uint) ([]models.FitService, error) {
+	var services []models.FitService
+	if len(ids) == 0 {
+		return services, nil
+	}
+	err := r.DB().Where("id IN ?", ids).Find(&services).Error
+	return services, err
+}
@@ -0,0 +1,91 @@
+// internal/repository/menu_repository.go
+package repository
+
+import (
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// MenuRepositoryInterface defines the contract for admin panel menu entries
+type MenuRepositoryInterface interface {
+	Create(menu *models.Menu) error
+	FindByID(id uint) (*models.Menu, error)
+	FindByName(name string) (*models.Menu, error)
+	Update(menu *models.Menu) error
+	Delete(id uint) error
+	CountChildren(id uint) (int64, error)
+	ListAll() ([]models.Menu, error)
+	ListActive(constant bool) ([]models.Menu, error)
+}
+
+// MenuRepository implements MenuRepositoryInterface
+type MenuRepository struct {
+	*BaseRepository
+}
+
+// NewMenuRepository creates a new MenuRepository instance
+func NewMenuRepository(db *gorm.DB) MenuRepositoryInterface {
+	return &MenuRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new menu entry
+func (r *MenuRepository) Create(menu *models.Menu) error {
+	return r.DB().Create(menu).Error
+}
+
+// FindByID retrieves a menu entry by its ID
+func (r *MenuRepository) FindByID(id uint) (*models.Menu, error) {
+	var menu models.Menu
+	err := r.DB().First(&menu, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// FindByName retrieves a menu entry by its route name
+func (r *MenuRepository) FindByName(name string) (*models.Menu, error) {
+	var menu models.Menu
+	err := r.DB().Where("name = ?", name).First(&menu).Error
+	if err != nil {
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// Update saves changes to an existing menu entry
+func (r *MenuRepository) Update(menu *models.Menu) error {
+	return r.Save(menu)
+}
+
+// Delete removes a menu entry by its ID
+func (r *MenuRepository) Delete(id uint) error {
+	_, err := r.DeleteByField(&[]models.Menu{}, []interface{}{id}, "id")
+	return err
+}
+
+// CountChildren returns how many entries sit directly under the menu
+func (r *MenuRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.DB().Model(&models.Menu{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ListAll returns every menu entry in display order
+func (r *MenuRepository) ListAll() ([]models.Menu, error) {
+	var menus []models.Menu
+	err := r.DB().Order("sort_order, id").Find(&menus).Error
+	return menus, err
+}
+
+// ListActive returns the active constant or non-constant entries in display order
+func (r *MenuRepository) ListActive(constant bool) ([]models.Menu, error) {
+	var menus []models.Menu
+	err := r.DB().
+		Where("is_active = ? AND constant = ?", true, constant).
+		Order("sort_order, id").
+		Find(&menus).Error
+	return menus, err
+}
@@ -0,0 +1,107 @@
+// internal/repository/mfa_repository.go
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MFARepositoryInterface defines the contract for TOTP enrollments and recovery codes
+type MFARepositoryInterface interface {
+	FindByUserID(userID uint) (*models.UserMFA, error)
+	IsEnabled(userID uint) (bool, error)
+	SaveEnrollment(mfa *models.UserMFA) error
+	Enable(userID uint, counter int64) (bool, error)
+	AdvanceCounter(userID uint, counter int64) (bool, error)
+	ReplaceRecoveryCodes(userID uint, codes []models.MFARecoveryCode) error
+	ConsumeRecoveryCode(userID uint, codeHash string) (bool, error)
+}
+
+// MFARepository implements MFARepositoryInterface
+type MFARepository struct {
+	*BaseRepository
+}
+
+// NewMFARepository creates a new MFARepository instance
+func NewMFARepository(db *gorm.DB) MFARepositoryInterface {
+	return &MFARepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindByUserID retrieves the enrollment of a user
+func (r *MFARepository) FindByUserID(userID uint) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	err := r.DB().Where("user_id = ?", userID).First(&mfa).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// IsEnabled reports whether the user has a confirmed enrollment
+func (r *MFARepository) IsEnabled(userID uint) (bool, error) {
+	mfa, err := r.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return mfa.EnabledAt != nil, nil
+}
+
+// SaveEnrollment creates or replaces the enrollment of a user
+func (r *MFARepository) SaveEnrollment(mfa *models.UserMFA) error {
+	return r.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_counter", "updated_at"}),
+	}).Create(mfa).Error
+}
+
+// Enable confirms a pending enrollment. It reports false when the enrollment was
+// already enabled.
+func (r *MFARepository) Enable(userID uint, counter int64) (bool, error) {
+	result := r.DB().Model(&models.UserMFA{}).
+		Where("user_id = ? AND enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"enabled_at":        time.Now(),
+			"last_used_counter": counter,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// AdvanceCounter records the time step of an accepted code. It reports false when
+// a code from the same or a later step was already accepted.
+func (r *MFARepository) AdvanceCounter(userID uint, counter int64) (bool, error) {
+	result := r.DB().Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_counter < ?", userID, counter).
+		Update("last_used_counter", counter)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReplaceRecoveryCodes discards the user's recovery codes and stores new ones
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codes []models.MFARecoveryCode) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeRecoveryCode marks an unused recovery code as used. It reports false when
+// the code does not exist or was already used.
+func (r *MFARepository) ConsumeRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.DB().Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
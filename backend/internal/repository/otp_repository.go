@@ -0,0 +1,91 @@
+// internal/repository/otp_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OTPRepositoryInterface defines the contract for one-time login codes
+type OTPRepositoryInterface interface {
+	Create(code *models.OTPCode) error
+	FindLatest(mobile string) (*models.OTPCode, error)
+	CountSince(mobile string, since time.Time) (int64, error)
+	Verify(mobile string, check func(code *models.OTPCode) bool) (*models.OTPCode, error)
+	InvalidateForMobile(mobile string) error
+}
+
+// OTPRepository implements OTPRepositoryInterface
+type OTPRepository struct {
+	*BaseRepository
+}
+
+// NewOTPRepository creates a new OTPRepository instance
+func NewOTPRepository(db *gorm.DB) OTPRepositoryInterface {
+	return &OTPRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new code
+func (r *OTPRepository) Create(code *models.OTPCode) error {
+	return r.DB().Create(code).Error
+}
+
+// FindLatest returns the most recently issued code for the mobile number
+func (r *OTPRepository) FindLatest(mobile string) (*models.OTPCode, error) {
+	var code models.OTPCode
+	err := r.DB().Where("mobile = ?", mobile).Order("created_at DESC").First(&code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// CountSince counts the codes issued to the mobile number since the given time
+func (r *OTPRepository) CountSince(mobile string, since time.Time) (int64, error) {
+	var count int64
+	err := r.DB().Model(&models.OTPCode{}).
+		Where("mobile = ? AND created_at >= ?", mobile, since).
+		Count(&count).Error
+	return count, err
+}
+
+// Verify locks the live code for the mobile number, records an attempt and runs
+// check against it. The code is consumed when check succeeds. It returns
+// gorm.ErrRecordNotFound when no live code exists or check fails.
+func (r *OTPRepository) Verify(mobile string, check func(code *models.OTPCode) bool) (*models.OTPCode, error) {
+	var code models.OTPCode
+	matched := false
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("mobile = ? AND consumed_at IS NULL AND expires_at > ?", mobile, time.Now()).
+			Order("created_at DESC").
+			First(&code).Error; err != nil {
+			return err
+		}
+		code.Attempts++
+		if matched = check(&code); matched {
+			now := time.Now()
+			code.ConsumedAt = &now
+		}
+		return tx.Save(&code).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !matched {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &code, nil
+}
+
+// InvalidateForMobile consumes every outstanding code for the mobile number
+func (r *OTPRepository) InvalidateForMobile(mobile string) error {
+	return r.DB().Model(&models.OTPCode{}).
+		Where("mobile = ? AND consumed_at IS NULL", mobile).
+		Update("consumed_at", time.Now()).Error
+}
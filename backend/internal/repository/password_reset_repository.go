@@ -0,0 +1,61 @@
+// internal/repository/password_reset_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PasswordResetRepositoryInterface defines the contract for password reset tokens
+type PasswordResetRepositoryInterface interface {
+	Create(token *models.PasswordResetToken) error
+	Consume(tokenHash string) (*models.PasswordResetToken, error)
+	InvalidateForUser(userID uint) error
+}
+
+// PasswordResetRepository implements PasswordResetRepositoryInterface
+type PasswordResetRepository struct {
+	*BaseRepository
+}
+
+// NewPasswordResetRepository creates a new PasswordResetRepository instance
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepositoryInterface {
+	return &PasswordResetRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new reset token
+func (r *PasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	return r.DB().Create(token).Error
+}
+
+// Consume marks an unused, unexpired token as used and returns it. It returns
+// gorm.ErrRecordNotFound when no such token exists.
+func (r *PasswordResetRepository) Consume(tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+			First(&token).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		token.UsedAt = &now
+		return tx.Save(&token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// InvalidateForUser marks every outstanding token of the user as used
+func (r *PasswordResetRepository) InvalidateForUser(userID uint) error {
+	return r.DB().Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
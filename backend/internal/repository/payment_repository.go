@@ -0,0 +1,402 @@
+// internal/repository/payment_repository.go
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentOutcome tells how a change to an order, attempt or refund went
+type PaymentOutcome int
+
+const (
+	PaymentApplied    PaymentOutcome = iota // the change was made
+	PaymentSettled                          // the order, attempt or refund is no longer pending
+	PaymentInProgress                       // another attempt of the order is pending
+	PaymentOverRefund                       // the refund exceeds what is left to refund
+)
+
+// PaymentRepositoryInterface defines the contract for payment orders, attempts,
+// refunds, the payments ledger and gateway webhook events
+type PaymentRepositoryInterface interface {
+	CreateOrder(order *models.PaymentOrder) error
+	FindOrderByID(id uint) (*models.PaymentOrder, error)
+	ListOrders(crewID uint, filters map[string]interface{}) ([]models.PaymentOrder, int64, error)
+	ListOrdersForUser(userID uint, filters map[string]interface{}) ([]models.PaymentOrder, int64, error)
+	LedgerForOrder(orderID uint) ([]models.LedgerEntry, error)
+
+	CreateAttempt(attempt *models.PaymentAttempt) (PaymentOutcome, error)
+	FindAttemptByReference(reference string) (*models.PaymentAttempt, error)
+	SetAttemptProviderRef(attemptID uint, providerRef string) error
+	CaptureAttempt(attemptID uint, providerRef string, now time.Time, entries []models.LedgerEntry, renew func(customer *models.Customer) *models.MembershipHistory) (PaymentOutcome, error)
+	FailAttempt(attemptID uint, reason string) (PaymentOutcome, error)
+
+	CreateRefund(refund *models.PaymentRefund) (PaymentOutcome, error)
+	FindRefundByReference(reference string) (*models.PaymentRefund, error)
+	SettleRefund(refundID uint, providerRef string, now time.Time, entries []models.LedgerEntry) (PaymentOutcome, error)
+	FailRefund(refundID uint, providerRef, reason string) (PaymentOutcome, error)
+
+	RecordWebhookEvent(event *models.PaymentWebhookEvent) (*models.PaymentWebhookEvent, error)
+	MarkWebhookProcessed(eventID uint, now time.Time) error
+}
+
+// PaymentRepository implements PaymentRepositoryInterface
+type PaymentRepository struct {
+	*BaseRepository
+}
+
+// NewPaymentRepository creates a new PaymentRepository instance
+func NewPaymentRepository(db *gorm.DB) PaymentRepositoryInterface {
+	return &PaymentRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateOrder stores a new order
+func (r *PaymentRepository) CreateOrder(order *models.PaymentOrder) error {
+	return r.DB().Omit("Customer", "Attempts", "Refunds").Create(order).Error
+}
+
+// FindOrderByID retrieves an order with its customer, attempts and refunds
+func (r *PaymentRepository) FindOrderByID(id uint) (*models.PaymentOrder, error) {
+	var order models.PaymentOrder
+	err := r.DB().Preload("Customer").
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListOrders returns a page of a crew's orders, latest first, and the total match count
+func (r *PaymentRepository) ListOrders(crewID uint, filters map[string]interface{}) ([]models.PaymentOrder, int64, error) {
+	return r.listOrders(r.DB().Model(&models.PaymentOrder{}).Where("crew_id = ?", crewID), filters)
+}
+
+// ListOrdersForUser returns a page of the orders of every membership the user holds
+func (r *PaymentRepository) ListOrdersForUser(userID uint, filters map[string]interface{}) ([]models.PaymentOrder, int64, error) {
+	return r.listOrders(r.DB().Model(&models.PaymentOrder{}).
+		Where("customer_id IN (?)", r.DB().Model(&models.Customer{}).Select("id").Where("user_id = ?", userID)), filters)
+}
+
+func (r *PaymentRepository) listOrders(query *gorm.DB, filters map[string]interface{}) ([]models.PaymentOrder, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+	query = r.BuildQuery(query, conditions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []models.PaymentOrder
+	err := query.Preload("Customer").Order("created_at DESC, id DESC").
+		Limit(pagination.Limit).Offset(pagination.Offset).Find(&orders).Error
+	return orders, total, err
+}
+
+// LedgerForOrder returns the ledger entries of an order in posting order
+func (r *PaymentRepository) LedgerForOrder(orderID uint) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.DB().Where("order_id = ?", orderID).Order("posted_at, id").Find(&entries).Error
+	return entries, err
+}
+
+// CreateAttempt stores a pending attempt unless the order is no longer pending
+// or already has a pending attempt. The order row is locked so two attempts of
+// one order cannot both be charged.
+func (r *PaymentRepository) CreateAttempt(attempt *models.PaymentAttempt) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, attempt.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != ORDER_STATUS_PENDING {
+			outcome = PaymentSettled
+			return nil
+		}
+
+		var pending int64
+		err = tx.Model(&models.PaymentAttempt{}).
+			Where("order_id = ? AND status = ?", order.ID, ATTEMPT_STATUS_PENDING).Count(&pending).Error
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			outcome = PaymentInProgress
+			return nil
+		}
+		return tx.Create(attempt).Error
+	})
+	return outcome, err
+}
+
+// FindAttemptByReference retrieves an attempt by our reference
+func (r *PaymentRepository) FindAttemptByReference(reference string) (*models.PaymentAttempt, error) {
+	var attempt models.PaymentAttempt
+	if err := r.DB().Where("reference = ?", reference).First(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// SetAttemptProviderRef stores the gateway's reference of a pending attempt
+func (r *PaymentRepository) SetAttemptProviderRef(attemptID uint, providerRef string) error {
+	return r.DB().Model(&models.PaymentAttempt{}).Where("id = ?", attemptID).
+		Update("provider_ref", providerRef).Error
+}
+
+// CaptureAttempt marks a pending attempt captured and its order paid, posts
+// the ledger entries and lets renew add the bought term to the customer, all in
+// one transaction. An attempt that is no longer pending is left alone.
+func (r *PaymentRepository) CaptureAttempt(attemptID uint, providerRef string, now time.Time, entries []models.LedgerEntry, renew func(customer *models.Customer) *models.MembershipHistory) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		attempt, order, err := lockAttempt(tx, attemptID)
+		if err != nil {
+			return err
+		}
+		if attempt.Status != ATTEMPT_STATUS_PENDING || order.Status != ORDER_STATUS_PENDING {
+			outcome = PaymentSettled
+			return nil
+		}
+
+		updates := map[string]interface{}{"status": ATTEMPT_STATUS_CAPTURED, "captured_at": now}
+		if providerRef != "" {
+			updates["provider_ref"] = providerRef
+		}
+		if err := tx.Model(attempt).Updates(updates).Error; err != nil {
+			return err
+		}
+		err = tx.Model(order).Updates(map[string]interface{}{"status": ORDER_STATUS_PAID, "paid_at": now}).Error
+		if err != nil {
+			return err
+		}
+		if err := postEntries(tx, entries); err != nil {
+			return err
+		}
+
+		var customer models.Customer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&customer, order.CustomerID).Error; err != nil {
+			return err
+		}
+		history := renew(&customer)
+		if err := tx.Omit("FitCrew", "User", "Plan").Save(&customer).Error; err != nil {
+			return err
+		}
+		history.CustomerID = customer.ID
+		return tx.Create(history).Error
+	})
+	return outcome, err
+}
+
+// FailAttempt marks a pending attempt failed; its order stays payable
+func (r *PaymentRepository) FailAttempt(attemptID uint, reason string) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		attempt, _, err := lockAttempt(tx, attemptID)
+		if err != nil {
+			return err
+		}
+		if attempt.Status != ATTEMPT_STATUS_PENDING {
+			outcome = PaymentSettled
+			return nil
+		}
+		return tx.Model(attempt).Updates(map[string]interface{}{
+			"status":         ATTEMPT_STATUS_FAILED,
+			"failure_reason": reason,
+		}).Error
+	})
+	return outcome, err
+}
+
+// CreateRefund stores a pending refund of a paid order when the amount, with
+// the refunds already made or pending, does not exceed the captured amount
+func (r *PaymentRepository) CreateRefund(refund *models.PaymentRefund) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, refund.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != ORDER_STATUS_PAID && order.Status != ORDER_STATUS_PARTIALLY_REFUNDED {
+			outcome = PaymentSettled
+			return nil
+		}
+
+		var pending int64
+		err = tx.Model(&models.PaymentRefund{}).Select("COALESCE(SUM(amount_minor), 0)").
+			Where("order_id = ? AND status = ?", order.ID, REFUND_STATUS_PENDING).Scan(&pending).Error
+		if err != nil {
+			return err
+		}
+		if refund.AmountMinor > order.RefundableMinor()-pending {
+			outcome = PaymentOverRefund
+			return nil
+		}
+		return tx.Create(refund).Error
+	})
+	return outcome, err
+}
+
+// FindRefundByReference retrieves a refund by our reference
+func (r *PaymentRepository) FindRefundByReference(reference string) (*models.PaymentRefund, error) {
+	var refund models.PaymentRefund
+	if err := r.DB().Where("reference = ?", reference).First(&refund).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// SettleRefund marks a pending refund succeeded, adds it to the refunded
+// amount of its order and posts the ledger entries
+func (r *PaymentRepository) SettleRefund(refundID uint, providerRef string, now time.Time, entries []models.LedgerEntry) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		refund, order, err := lockRefund(tx, refundID)
+		if err != nil {
+			return err
+		}
+		if refund.Status != REFUND_STATUS_PENDING {
+			outcome = PaymentSettled
+			return nil
+		}
+
+		updates := map[string]interface{}{"status": REFUND_STATUS_SUCCEEDED, "refunded_at": now}
+		if providerRef != "" {
+			updates["provider_ref"] = providerRef
+		}
+		if err := tx.Model(refund).Updates(updates).Error; err != nil {
+			return err
+		}
+		refunded := order.RefundedMinor + refund.AmountMinor
+		status := ORDER_STATUS_PARTIALLY_REFUNDED
+		if refunded >= order.AmountMinor {
+			status = ORDER_STATUS_REFUNDED
+		}
+		err = tx.Model(order).Updates(map[string]interface{}{"refunded_minor": refunded, "status": status}).Error
+		if err != nil {
+			return err
+		}
+		return postEntries(tx, entries)
+	})
+	return outcome, err
+}
+
+// FailRefund marks a pending refund failed
+func (r *PaymentRepository) FailRefund(refundID uint, providerRef, reason string) (PaymentOutcome, error) {
+	outcome := PaymentApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		refund, _, err := lockRefund(tx, refundID)
+		if err != nil {
+			return err
+		}
+		if refund.Status != REFUND_STATUS_PENDING {
+			outcome = PaymentSettled
+			return nil
+		}
+		updates := map[string]interface{}{"status": REFUND_STATUS_FAILED, "failure_reason": reason}
+		if providerRef != "" {
+			updates["provider_ref"] = providerRef
+		}
+		return tx.Model(refund).Updates(updates).Error
+	})
+	return outcome, err
+}
+
+// RecordWebhookEvent stores a webhook event unless the gateway sent it before,
+// and returns the stored event either way
+func (r *PaymentRepository) RecordWebhookEvent(event *models.PaymentWebhookEvent) (*models.PaymentWebhookEvent, error) {
+	err := r.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "gateway"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(event).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var stored models.PaymentWebhookEvent
+	err = r.DB().Where("gateway = ? AND event_id = ?", event.Gateway, event.EventID).First(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// MarkWebhookProcessed records that the event's changes were applied
+func (r *PaymentRepository) MarkWebhookProcessed(eventID uint, now time.Time) error {
+	return r.DB().Model(&models.PaymentWebhookEvent{}).Where("id = ?", eventID).
+		Update("processed_at", now).Error
+}
+
+// postEntries stores ledger entries after checking that each transaction balances
+func postEntries(tx *gorm.DB, entries []models.LedgerEntry) error {
+	balance := make(map[string]int64)
+	for _, entry := range entries {
+		balance[entry.TransactionRef] += entry.DebitMinor - entry.CreditMinor
+	}
+	for ref, difference := range balance {
+		if difference != 0 {
+			return fmt.Errorf("ledger transaction %s does not balance: debits exceed credits by %d", ref, difference)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Create(&entries).Error
+}
+
+func lockOrder(tx *gorm.DB, orderID uint) (*models.PaymentOrder, error) {
+	var order models.PaymentOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// lockAttempt locks an attempt and its order, order first like every other
+// writer of the order
+func lockAttempt(tx *gorm.DB, attemptID uint) (*models.PaymentAttempt, *models.PaymentOrder, error) {
+	var attempt models.PaymentAttempt
+	if err := tx.Select("id", "order_id").First(&attempt, attemptID).Error; err != nil {
+		return nil, nil, err
+	}
+	order, err := lockOrder(tx, attempt.OrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&attempt, attemptID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &attempt, order, nil
+}
+
+// lockRefund locks a refund and its order, order first
+func lockRefund(tx *gorm.DB, refundID uint) (*models.PaymentRefund, *models.PaymentOrder, error) {
+	var refund models.PaymentRefund
+	if err := tx.Select("id", "order_id").First(&refund, refundID).Error; err != nil {
+		return nil, nil, err
+	}
+	order, err := lockOrder(tx, refund.OrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&refund, refundID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &refund, order, nil
+}
@@ -0,0 +1,95 @@
+// internal/repository/refresh_token_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepositoryInterface defines the contract for refresh token persistence
+type RefreshTokenRepositoryInterface interface {
+	Create(token *models.RefreshToken) error
+	FindByJTI(jti string) (*models.RefreshToken, error)
+	Rotate(current *models.RefreshToken, next *models.RefreshToken) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeDevice(userID uint, deviceID string) error
+	RevokeAllForUser(userID uint) error
+}
+
+// RefreshTokenRepository implements RefreshTokenRepositoryInterface
+type RefreshTokenRepository struct {
+	*BaseRepository
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository instance
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepositoryInterface {
+	return &RefreshTokenRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new refresh token record
+func (r *RefreshTokenRepository) Create(token *models.RefreshToken) error {
+	return r.DB().Create(token).Error
+}
+
+// FindByJTI retrieves a refresh token by its token ID
+func (r *RefreshTokenRepository) FindByJTI(jti string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.DB().Where("jti = ?", jti).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes the current token and stores its replacement in one transaction.
+// It reports false when the current token was already revoked, which means another
+// request used it first.
+func (r *RefreshTokenRepository) Rotate(current *models.RefreshToken, next *models.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":  time.Now(),
+				"replaced_by": next.JTI,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeFamily revokes every active token descended from the same login
+func (r *RefreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.revokeWhere("family_id = ?", familyID)
+}
+
+// RevokeDevice revokes every active token a user holds on one device
+func (r *RefreshTokenRepository) RevokeDevice(userID uint, deviceID string) error {
+	return r.revokeWhere("user_id = ? AND device_id = ?", userID, deviceID)
+}
+
+// RevokeAllForUser revokes every active token a user holds
+func (r *RefreshTokenRepository) RevokeAllForUser(userID uint) error {
+	return r.revokeWhere("user_id = ?", userID)
+}
+
+func (r *RefreshTokenRepository) revokeWhere(query string, args ...interface{}) error {
+	return r.DB().Model(&models.RefreshToken{}).
+		Where(query, args...).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
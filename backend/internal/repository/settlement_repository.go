@@ -0,0 +1,196 @@
+// internal/repository/settlement_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettlementOutcome tells how a change to a settlement statement went
+type SettlementOutcome int
+
+const (
+	SettlementApplied       SettlementOutcome = iota // the change was made
+	SettlementStatusChanged                          // the statement is not in the status the change needs
+	SettlementAlreadyDone                            // a transaction of the statement was settled meanwhile
+)
+
+// SettlementRepositoryInterface defines the contract for commission settlement
+// statements and the ledger transactions they settle
+type SettlementRepositoryInterface interface {
+	UnsettledEntries(allieID uint, from, to time.Time) ([]models.LedgerEntry, error)
+	PaymentRates(orderIDs []uint) (map[uint]int, error)
+	CreateStatement(statement *models.SettlementStatement) (SettlementOutcome, error)
+	FindByID(id uint) (*models.SettlementStatement, error)
+	List(filters map[string]interface{}) ([]models.SettlementStatement, int64, error)
+	Approve(id, approvedBy uint, now time.Time) (SettlementOutcome, error)
+	MarkPaid(id, paidBy uint, reference string, now time.Time) (SettlementOutcome, error)
+	Discard(id uint) (SettlementOutcome, error)
+}
+
+// SettlementRepository implements SettlementRepositoryInterface
+type SettlementRepository struct {
+	*BaseRepository
+}
+
+// NewSettlementRepository creates a new SettlementRepository instance
+func NewSettlementRepository(db *gorm.DB) SettlementRepositoryInterface {
+	return &SettlementRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// UnsettledEntries returns the allie's ledger entries posted in [from, to)
+// whose transaction is on no statement yet, in the order they were posted
+func (r *SettlementRepository) UnsettledEntries(allieID uint, from, to time.Time) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.DB().
+		Where("allie_id = ? AND posted_at >= ? AND posted_at < ?", allieID, from, to).
+		Where("transaction_ref NOT IN (?)", r.DB().Model(&models.SettlementLine{}).Select("transaction_ref")).
+		Order("posted_at, id").Find(&entries).Error
+	return entries, err
+}
+
+// PaymentRates returns the commission rate the payment of each order was
+// settled at, for the orders whose payment is on a statement
+func (r *SettlementRepository) PaymentRates(orderIDs []uint) (map[uint]int, error) {
+	rates := make(map[uint]int, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return rates, nil
+	}
+	var lines []models.SettlementLine
+	err := r.DB().Select("order_id", "commission_rate").
+		Where("order_id IN ? AND kind = ?", orderIDs, SETTLEMENT_LINE_PAYMENT).
+		Find(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		rates[line.OrderID] = line.CommissionRate
+	}
+	return rates, nil
+}
+
+// CreateStatement stores a statement with its lines. The allie's row is locked
+// so statements of an allie are generated one at a time, and the statement is
+// refused when one of its transactions was settled since it was computed.
+func (r *SettlementRepository) CreateStatement(statement *models.SettlementStatement) (SettlementOutcome, error) {
+	outcome := SettlementApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		var allie models.FitAllie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&allie, statement.AllieID).Error; err != nil {
+			return err
+		}
+
+		refs := make([]string, 0, len(statement.Lines))
+		for _, line := range statement.Lines {
+			refs = append(refs, line.TransactionRef)
+		}
+		var settled int64
+		if err := tx.Model(&models.SettlementLine{}).Where("transaction_ref IN ?", refs).Count(&settled).Error; err != nil {
+			return err
+		}
+		if settled > 0 {
+			outcome = SettlementAlreadyDone
+			return nil
+		}
+		return tx.Create(statement).Error
+	})
+	return outcome, err
+}
+
+// FindByID retrieves a statement with its lines in the order they were posted
+func (r *SettlementRepository) FindByID(id uint) (*models.SettlementStatement, error) {
+	var statement models.SettlementStatement
+	err := r.DB().Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("posted_at, id")
+	}).First(&statement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// List returns a page of statements, latest period first, and the total match count
+func (r *SettlementRepository) List(filters map[string]interface{}) ([]models.SettlementStatement, int64, error) {
+	pagination := r.GetPagination(filters)
+
+	conditions := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+	query := r.BuildQuery(r.DB().Model(&models.SettlementStatement{}), conditions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var statements []models.SettlementStatement
+	err := query.Order("period_start DESC, id DESC").
+		Limit(pagination.Limit).Offset(pagination.Offset).Find(&statements).Error
+	return statements, total, err
+}
+
+// Approve moves a draft statement to approved
+func (r *SettlementRepository) Approve(id, approvedBy uint, now time.Time) (SettlementOutcome, error) {
+	return r.transition(id, SETTLEMENT_STATUS_DRAFT, map[string]interface{}{
+		"status":      SETTLEMENT_STATUS_APPROVED,
+		"approved_by": approvedBy,
+		"approved_at": now,
+	})
+}
+
+// MarkPaid moves an approved statement to paid, recording the payout's reference
+func (r *SettlementRepository) MarkPaid(id, paidBy uint, reference string, now time.Time) (SettlementOutcome, error) {
+	return r.transition(id, SETTLEMENT_STATUS_APPROVED, map[string]interface{}{
+		"status":            SETTLEMENT_STATUS_PAID,
+		"paid_by":           paidBy,
+		"paid_at":           now,
+		"payment_reference": reference,
+	})
+}
+
+// Discard deletes a draft statement. Its lines are removed for good so their
+// transactions can be settled again.
+func (r *SettlementRepository) Discard(id uint) (SettlementOutcome, error) {
+	outcome := SettlementApplied
+	err := r.DB().Transaction(func(tx *gorm.DB) error {
+		var statement models.SettlementStatement
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&statement, id).Error; err != nil {
+			return err
+		}
+		if statement.Status != SETTLEMENT_STATUS_DRAFT {
+			outcome = SettlementStatusChanged
+			return nil
+		}
+		if err := tx.Unscoped().Where("statement_id = ?", id).Delete(&models.SettlementLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&statement).Error
+	})
+	return outcome, err
+}
+
+// transition updates a statement when it is still in the expected status
+func (r *SettlementRepository) transition(id uint, from SETTLEMENTSTATUS, updates map[string]interface{}) (SettlementOutcome, error) {
+	result := r.DB().Model(&models.SettlementStatement{}).
+		Where("id = ? AND status = ?", id, from).Updates(updates)
+	if result.Error != nil {
+		return SettlementApplied, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var statement models.SettlementStatement
+		if err := r.DB().Select("id").First(&statement, id).Error; err != nil {
+			return SettlementApplied, err
+		}
+		return SettlementStatusChanged, nil
+	}
+	return SettlementApplied, nil
+}
@@ -0,0 +1,151 @@
+// internal/repository/trainer_repository.go
+package repository
+
+import (
+	"time"
+
+	"backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TrainerListOptions narrows the trainers of a crew
+type TrainerListOptions struct {
+	Filters           map[string]interface{} // paging and BuildQuery conditions
+	Search            string
+	SpecializationID  *uint
+	StartedOnOrBefore *time.Time // at least this much experience
+	StartedAfter      *time.Time // less than this much experience
+}
+
+// TrainerRepositoryInterface defines the contract for trainer persistence
+type TrainerRepositoryInterface interface {
+	Create(trainer *models.TrainerProfile, user *models.User) error
+	FindByID(id uint) (*models.TrainerProfile, error)
+	FindByUserID(userID uint) (*models.TrainerProfile, error)
+	Update(trainer *models.TrainerProfile) error
+	Delete(id uint) error
+	List(crewID uint, options TrainerListOptions) ([]models.TrainerProfile, int64, error)
+	FindServices(ids []uint) ([]models.FitService, error)
+}
+
+// TrainerRepository implements TrainerRepositoryInterface
+type TrainerRepository struct {
+	*BaseRepository
+}
+
+// NewTrainerRepository creates a new TrainerRepository instance
+func NewTrainerRepository(db *gorm.DB) TrainerRepositoryInterface {
+	return &TrainerRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts the trainer with its specializations and certifications. When
+// a user is given it is created first and linked as the trainer's login.
+func (r *TrainerRepository) Create(trainer *models.TrainerProfile, user *models.User) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if user != nil {
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+			trainer.UserID = &user.ID
+		}
+		return tx.Create(trainer).Error
+	})
+}
+
+// FindByID retrieves a trainer with its specializations and certifications
+func (r *TrainerRepository) FindByID(id uint) (*models.TrainerProfile, error) {
+	var trainer models.TrainerProfile
+	err := r.DB().Preload("Specializations").Preload("Certifications").First(&trainer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trainer, nil
+}
+
+// FindByUserID retrieves the trainer linked to a user
+func (r *TrainerRepository) FindByUserID(userID uint) (*models.TrainerProfile, error) {
+	var trainer models.TrainerProfile
+	err := r.DB().Where("user_id = ?", userID).First(&trainer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trainer, nil
+}
+
+// Update saves the trainer and replaces its specializations and certifications
+func (r *TrainerRepository) Update(trainer *models.TrainerProfile) error {
+	return r.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Specializations", "Certifications").Save(trainer).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(trainer).Association("Specializations").Replace(trainer.Specializations); err != nil {
+			return err
+		}
+		if err := tx.Where("trainer_id = ?", trainer.ID).Delete(&models.TrainerCertification{}).Error; err != nil {
+			return err
+		}
+		for i := range trainer.Certifications {
+			trainer.Certifications[i].ID = 0
+			trainer.Certifications[i].TrainerID = trainer.ID
+		}
+		if len(trainer.Certifications) == 0 {
+			return nil
+		}
+		return tx.Create(&trainer.Certifications).Error
+	})
+}
+
+// Delete removes a trainer by its ID
+func (r *TrainerRepository) Delete(id uint) error {
+	return r.DB().Delete(&models.TrainerProfile{}, id).Error
+}
+
+// List returns a page of a crew's trainers matching the options and the total match count
+func (r *TrainerRepository) List(crewID uint, options TrainerListOptions) ([]models.TrainerProfile, int64, error) {
+	pagination := r.GetPagination(options.Filters)
+
+	conditions := make(map[string]interface{}, len(options.Filters))
+	for key, value := range options.Filters {
+		if key != "offset" && key != "limit" {
+			conditions[key] = value
+		}
+	}
+
+	query := r.BuildQuery(r.DB().Model(&models.TrainerProfile{}).Where("crew_id = ?", crewID), conditions)
+	if options.Search != "" {
+		pattern := "%" + options.Search + "%"
+		query = query.Where("full_name ILIKE ? OR email ILIKE ? OR mobile ILIKE ?", pattern, pattern, pattern)
+	}
+	if options.SpecializationID != nil {
+		query = query.Where("id IN (?)", r.DB().Table("trainer_specializations").
+			Select("trainer_profile_id").Where("fit_service_id = ?", *options.SpecializationID))
+	}
+	if options.StartedOnOrBefore != nil {
+		query = query.Where("exp_started_from <= ?", *options.StartedOnOrBefore)
+	}
+	if options.StartedAfter != nil {
+		query = query.Where("exp_started_from > ?", *options.StartedAfter)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var trainers []models.TrainerProfile
+	err := query.Preload("Specializations").Preload("Certifications").
+		Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&trainers).Error
+	return trainers, total, err
+}
+
+// FindServices retrieves the services with the given IDs
+func (r *TrainerRepository) FindServices(ids []uint) ([]models.FitService, error) {
+	var services []models.FitService
+	if len(ids) == 0 {
+		return services, nil
+	}
+	err := r.DB().Where("id IN ?", ids).Find(&services).Error
+	return services, err
+}
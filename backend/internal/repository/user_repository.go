@@ -82,6 +82,8 @@ type UserRepositoryInterface interface {
 	Create(user *models.User) error
 	FindByID(id uint) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
+	FindByLogin(identifier string) (*models.User, error)
+	FindByMobile(mobile string) (*models.User, error)
 	Update(user *models.User) error
 	Delete(id uint) error
 	ListUsers() ([]models.User, error)
@@ -137,6 +139,29 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByLogin retrieves a non-deleted user whose username, email or mobile matches the identifier
+func (r *UserRepository) FindByLogin(identifier string) (*models.User, error) {
+	var user models.User
+	err := r.DB().
+		Where("username = ? OR email = ? OR mobile = ?", identifier, identifier, identifier).
+		Where("is_deleted = ?", false).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByMobile retrieves a non-deleted user by their mobile number
+func (r *UserRepository) FindByMobile(mobile string) (*models.User, error) {
+	var user models.User
+	err := r.DB().Where("mobile = ? AND is_deleted = ?", mobile, false).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // Update updates the details of an existing user in the database
 func (r *UserRepository) Update(user *models.User) error {
 	return r.Save(user)
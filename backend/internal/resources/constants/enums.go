@@ -1,6 +1,11 @@
 // internal/resources/constants.go
 package resources
 
+import (
+	"strings"
+	"time"
+)
+
 // WEEKDAY represents days of the week
 type WEEKDAY string
 
@@ -15,6 +20,20 @@ const (
 	SUNDAY    WEEKDAY = "SUNDAY"
 )
 
+// WeekdayOf returns the WEEKDAY of a time.Weekday
+func WeekdayOf(day time.Weekday) WEEKDAY {
+	return WEEKDAY(strings.ToUpper(day.String()))
+}
+
+// IsValid reports whether d is one of the WEEKDAY constants
+func (d WEEKDAY) IsValid() bool {
+	switch d {
+	case MONDAY, TUESDAY, WEDNESDAY, THURSDAY, FRIDAY, SATURDAY, SUNDAY:
+		return true
+	}
+	return false
+}
+
 // GENDER represents user gender
 type GENDER string
 
@@ -38,3 +57,131 @@ const (
 	INVALID    USERROLE = 0
 )
 
+
+// MEMBERSHIPACTION represents a transition recorded in a customer's membership history
+type MEMBERSHIPACTION string
+
+// MEMBERSHIPACTION constants
+const (
+	MEMBERSHIP_ENROLLED    MEMBERSHIPACTION = "ENROLLED"
+	MEMBERSHIP_RENEWED     MEMBERSHIPACTION = "RENEWED"
+	MEMBERSHIP_EXTENDED    MEMBERSHIPACTION = "EXTENDED"
+	MEMBERSHIP_FROZEN      MEMBERSHIPACTION = "FROZEN"
+	MEMBERSHIP_UNFROZEN    MEMBERSHIPACTION = "UNFROZEN"
+	MEMBERSHIP_TRANSFERRED MEMBERSHIPACTION = "TRANSFERRED"
+	MEMBERSHIP_CANCELLED   MEMBERSHIPACTION = "CANCELLED"
+)
+
+// MEMBERSHIPSTATUS represents where a membership stands at a point in time
+type MEMBERSHIPSTATUS string
+
+// MEMBERSHIPSTATUS constants
+const (
+	MEMBERSHIP_STATUS_UNPAID    MEMBERSHIPSTATUS = "UNPAID" // enrolled, waiting for the first term to be paid
+	MEMBERSHIP_STATUS_PENDING   MEMBERSHIPSTATUS = "PENDING"
+	MEMBERSHIP_STATUS_ACTIVE    MEMBERSHIPSTATUS = "ACTIVE"
+	MEMBERSHIP_STATUS_FROZEN    MEMBERSHIPSTATUS = "FROZEN"
+	MEMBERSHIP_STATUS_EXPIRED   MEMBERSHIPSTATUS = "EXPIRED"
+	MEMBERSHIP_STATUS_CANCELLED MEMBERSHIPSTATUS = "CANCELLED"
+)
+
+// CLASSSESSIONSTATUS represents the state of a class session
+type CLASSSESSIONSTATUS string
+
+// CLASSSESSIONSTATUS constants
+const (
+	CLASS_SESSION_SCHEDULED CLASSSESSIONSTATUS = "SCHEDULED"
+	CLASS_SESSION_CANCELLED CLASSSESSIONSTATUS = "CANCELLED"
+)
+
+// BOOKINGSTATUS represents the state of a class booking
+type BOOKINGSTATUS string
+
+// BOOKINGSTATUS constants
+const (
+	BOOKING_BOOKED         BOOKINGSTATUS = "BOOKED"
+	BOOKING_WAITLISTED     BOOKINGSTATUS = "WAITLISTED"
+	BOOKING_CANCELLED      BOOKINGSTATUS = "CANCELLED"
+	BOOKING_LATE_CANCELLED BOOKINGSTATUS = "LATE_CANCELLED"
+	BOOKING_ATTENDED       BOOKINGSTATUS = "ATTENDED"
+	BOOKING_NO_SHOW        BOOKINGSTATUS = "NO_SHOW"
+)
+
+// CHECKINMETHOD represents how a member was checked in
+type CHECKINMETHOD string
+
+// CHECKINMETHOD constants
+const (
+	CHECKIN_DESK  CHECKINMETHOD = "DESK"
+	CHECKIN_KIOSK CHECKINMETHOD = "KIOSK"
+)
+
+// ORDERSTATUS represents the state of a payment order
+type ORDERSTATUS string
+
+// ORDERSTATUS constants
+const (
+	ORDER_STATUS_PENDING            ORDERSTATUS = "PENDING"
+	ORDER_STATUS_PAID               ORDERSTATUS = "PAID"
+	ORDER_STATUS_PARTIALLY_REFUNDED ORDERSTATUS = "PARTIALLY_REFUNDED"
+	ORDER_STATUS_REFUNDED           ORDERSTATUS = "REFUNDED"
+)
+
+// ATTEMPTSTATUS represents the state of a payment attempt at the gateway
+type ATTEMPTSTATUS string
+
+// ATTEMPTSTATUS constants
+const (
+	ATTEMPT_STATUS_PENDING  ATTEMPTSTATUS = "PENDING"
+	ATTEMPT_STATUS_CAPTURED ATTEMPTSTATUS = "CAPTURED"
+	ATTEMPT_STATUS_FAILED   ATTEMPTSTATUS = "FAILED"
+)
+
+// REFUNDSTATUS represents the state of a refund at the gateway
+type REFUNDSTATUS string
+
+// REFUNDSTATUS constants
+const (
+	REFUND_STATUS_PENDING   REFUNDSTATUS = "PENDING"
+	REFUND_STATUS_SUCCEEDED REFUNDSTATUS = "SUCCEEDED"
+	REFUND_STATUS_FAILED    REFUNDSTATUS = "FAILED"
+)
+
+// LEDGERACCOUNT represents an account of the payments ledger
+type LEDGERACCOUNT string
+
+// LEDGERACCOUNT constants
+const (
+	LEDGER_GATEWAY_CLEARING   LEDGERACCOUNT = "GATEWAY_CLEARING"   // money held by the payment gateway
+	LEDGER_MEMBERSHIP_REVENUE LEDGERACCOUNT = "MEMBERSHIP_REVENUE" // price of memberships sold, tax excluded
+	LEDGER_TAX_PAYABLE        LEDGERACCOUNT = "TAX_PAYABLE"        // tax collected on memberships sold
+	LEDGER_DESK_CASH          LEDGERACCOUNT = "DESK_CASH"          // money taken at a crew's desk, held by the allie
+)
+
+// INVOICEKIND represents the kind of a GST document
+type INVOICEKIND string
+
+// INVOICEKIND constants
+const (
+	INVOICE_KIND_TAX_INVOICE INVOICEKIND = "INVOICE"     // issued when an order is paid
+	INVOICE_KIND_CREDIT_NOTE INVOICEKIND = "CREDIT_NOTE" // issued when a paid order is refunded
+)
+
+// SETTLEMENTSTATUS represents where a commission settlement statement stands
+type SETTLEMENTSTATUS string
+
+// SETTLEMENTSTATUS constants
+const (
+	SETTLEMENT_STATUS_DRAFT    SETTLEMENTSTATUS = "DRAFT"    // generated, may still be discarded
+	SETTLEMENT_STATUS_APPROVED SETTLEMENTSTATUS = "APPROVED" // checked by finance, awaiting payout
+	SETTLEMENT_STATUS_PAID     SETTLEMENTSTATUS = "PAID"     // the payout was sent to the allie
+)
+
+// SETTLEMENTLINEKIND represents what a line of a settlement statement settles
+type SETTLEMENTLINEKIND string
+
+// SETTLEMENTLINEKIND constants
+const (
+	SETTLEMENT_LINE_PAYMENT SETTLEMENTLINEKIND = "PAYMENT"
+	SETTLEMENT_LINE_REFUND  SETTLEMENTLINEKIND = "REFUND"
+)
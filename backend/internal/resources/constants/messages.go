@@ -14,6 +14,25 @@ const (
 	PASSWORD_CHANGE_REQUIRED   = "Password change is required"
 	LOGIN_SUCCESSFUL           = "Login successful"
 	LOGOUT_SUCCESSFUL          = "Logout successful"
+	REFRESH_TOKEN_INVALID      = "Refresh token is invalid or expired"
+	PASSWORD_RESET_REQUESTED   = "If the account exists, password reset instructions have been sent"
+	PASSWORD_RESET_TOKEN_INVALID = "Password reset token is invalid or expired"
+	PASSWORD_CHANGE_SUCCESSFUL = "Password changed successfully"
+	INCORRECT_CURRENT_PASSWORD = "Current password is incorrect"
+	PASSWORD_UNCHANGED         = "New password must differ from the current password"
+	MFA_REQUIRED               = "Two-factor authentication code required"
+	MFA_ENROLLMENT_REQUIRED    = "Two-factor authentication must be set up for this account"
+	MFA_CODE_INVALID           = "Two-factor authentication code is invalid"
+	MFA_NOT_ENROLLED           = "Two-factor authentication enrollment has not been started"
+	MFA_ALREADY_ENABLED        = "Two-factor authentication is already enabled"
+	MFA_ENABLED                = "Two-factor authentication enabled"
+	OTP_SENT                   = "If the number is registered, a login code has been sent"
+	OTP_INVALID                = "Login code is invalid or expired"
+	OTP_RESEND_TOO_SOON        = "A login code was sent recently, please wait before requesting another"
+	REFRESH_TOKEN_REUSED       = "Refresh token was already used; the session has been revoked"
+	IMPERSONATION_STARTED      = "Impersonation token issued"
+	IMPERSONATION_FORBIDDEN    = "This user cannot be impersonated"
+	IMPERSONATION_NOT_ALLOWED  = "This action is not allowed while impersonating a user"
 )
 
 // General error and success messages
@@ -37,7 +56,140 @@ const (
 	USER_UPDATE_SUCCESSFUL     = "User updated successfully"
 	EMAIL_ALREADY_IN_USE       = "Email address is already in use"
 	USERNAME_ALREADY_TAKEN     = "Username is already taken"
+	USER_ACCESS_DENIED         = "You cannot manage users of this role"
 	ACCOUNT_LOCKED             = "User account is locked"
+	ACCOUNT_INACTIVE           = "User account is inactive"
+	ACCOUNT_UNLOCKED           = "User account unlocked successfully"
+	TOO_MANY_LOGIN_ATTEMPTS    = "Too many failed login attempts, please try again later"
+)
+
+// Allie-related error and success messages
+const (
+	ALLIE_NOT_FOUND            = "Allie not found"
+	ALLIE_CREATED              = "Allie created successfully"
+	ALLIE_UPDATED              = "Allie updated successfully"
+	ALLIE_DELETED              = "Allie deleted successfully"
+	INVALID_ALLIE_INPUT        = "Invalid allie input"
+	ALLIE_ACCESS_DENIED        = "You can only manage your own allie"
+)
+
+// Crew-related error and success messages
+const (
+	CREW_NOT_FOUND             = "Crew not found"
+	CREW_CREATED               = "Crew created successfully"
+	CREW_UPDATED               = "Crew updated successfully"
+	CREW_DELETED               = "Crew deleted successfully"
+	INVALID_CREW_INPUT         = "Invalid crew input"
+	BRANCH_LIMIT_REACHED       = "Allie has reached its number of branches"
+	INVALID_SCHEDULE_INPUT     = "Invalid opening hours input"
+	SCHEDULE_UPDATED           = "Opening hours updated successfully"
+	HOLIDAY_NOT_FOUND          = "Holiday not found"
+	HOLIDAY_ADDED              = "Holiday added successfully"
+	HOLIDAY_REMOVED            = "Holiday removed successfully"
+)
+
+// Membership-related error and success messages
+const (
+	CUSTOMER_NOT_FOUND          = "Customer not found"
+	CUSTOMER_ENROLLED           = "Customer enrolled successfully"
+	CUSTOMER_ALREADY_ENROLLED   = "Customer is already enrolled at this crew"
+	INVALID_MEMBERSHIP_INPUT    = "Invalid membership input"
+	MEMBERSHIP_STATE_CONFLICT   = "Membership cannot make this change in its current state"
+	MEMBERSHIP_RENEW_SUCCESS    = "Membership renewed successfully"
+	MEMBERSHIP_EXTEND_SUCCESS   = "Membership extended successfully"
+	MEMBERSHIP_FREEZE_SUCCESS   = "Membership frozen successfully"
+	MEMBERSHIP_RESUME_SUCCESS   = "Membership resumed successfully"
+	MEMBERSHIP_TRANSFER_SUCCESS = "Membership transferred successfully"
+	MEMBERSHIP_CANCEL_SUCCESS   = "Membership cancelled successfully"
+)
+
+// Plan-related error and success messages
+const (
+	PLAN_NOT_FOUND             = "Membership plan not found"
+	PLAN_CREATED               = "Membership plan created successfully"
+	PLAN_UPDATED               = "Membership plan updated successfully"
+	PLAN_DELETED               = "Membership plan deleted successfully"
+	INVALID_PLAN_INPUT         = "Invalid membership plan input"
+	PLAN_NOT_AVAILABLE         = "Membership plan is not sold at this crew"
+)
+
+// Trainer-related error and success messages
+const (
+	TRAINER_NOT_FOUND          = "Trainer not found"
+	TRAINER_CREATED            = "Trainer created successfully"
+	TRAINER_UPDATED            = "Trainer updated successfully"
+	TRAINER_DELETED            = "Trainer deleted successfully"
+	INVALID_TRAINER_INPUT      = "Invalid trainer input"
+	TRAINER_USER_TAKEN         = "User is already linked to another trainer"
+)
+
+// Service catalog error and success messages
+const (
+	SERVICE_NOT_FOUND          = "Service not found"
+	SERVICE_CREATED            = "Service created successfully"
+	SERVICE_UPDATED            = "Service updated successfully"
+	SERVICE_DELETED            = "Service deleted successfully"
+	SERVICE_NAME_TAKEN         = "Service name is already taken"
+	INVALID_SERVICE_INPUT      = "Invalid service input"
+	OFFERING_NOT_FOUND         = "Service offering not found"
+	OFFERING_ADDED             = "Service offering added successfully"
+	OFFERING_UPDATED           = "Service offering updated successfully"
+	OFFERING_REMOVED           = "Service offering removed successfully"
+	OFFERING_EXISTS            = "Service is already offered there"
+)
+
+// Class-related error and success messages
+const (
+	CLASS_NOT_FOUND            = "Class not found"
+	CLASS_CREATED              = "Class created successfully"
+	CLASS_UPDATED              = "Class updated successfully"
+	CLASS_DELETED              = "Class deleted successfully"
+	INVALID_CLASS_INPUT        = "Invalid class input"
+	SESSIONS_GENERATED         = "Class sessions generated successfully"
+	SESSION_NOT_FOUND          = "Class session not found"
+	SESSION_UPDATED            = "Class session updated successfully"
+	SESSION_CANCELLED          = "Class session cancelled successfully"
+	SESSION_NOT_SCHEDULED      = "Class session is not scheduled"
+	TRAINER_DOUBLE_BOOKED      = "Trainer already leads a class at that time"
+)
+
+// Booking-related error and success messages
+const (
+	BOOKING_NOT_FOUND          = "Booking not found"
+	CLASS_BOOKED               = "Class booked successfully"
+	CLASS_WAITLISTED           = "Class is full, added to the waitlist"
+	CLASS_BOOKING_CANCELLED    = "Booking cancelled successfully"
+	BOOKING_UPDATED            = "Booking updated successfully"
+	INVALID_BOOKING_INPUT      = "Invalid booking input"
+	BOOKING_NOT_ALLOWED        = "Booking not allowed"
+	ALREADY_BOOKED             = "Customer already holds a booking for this session"
+	BOOKING_NOT_ACTIVE         = "Booking is no longer active"
+	BOOKING_POLICY_UPDATED     = "Booking policy updated successfully"
+)
+
+// Check-in-related error and success messages
+const (
+	CHECKED_IN                 = "Checked in successfully"
+	CHECKED_OUT                = "Checked out successfully"
+	INVALID_CHECKIN_INPUT      = "Invalid check-in input"
+	ENTRY_REFUSED              = "Entry refused"
+	CREW_AT_CAPACITY           = "Gym is at capacity"
+	ALREADY_CHECKED_IN         = "Customer is already checked in"
+	NOT_CHECKED_IN             = "Customer is not checked in"
+	INVALID_CHECKIN_CODE       = "Check-in code is invalid or expired"
+	CHECKIN_CODE_USED          = "Check-in code has already been used"
+)
+
+// Menu-related error and success messages
+const (
+	MENU_NOT_FOUND             = "Menu not found"
+	MENU_CREATED               = "Menu created successfully"
+	MENU_UPDATED               = "Menu updated successfully"
+	MENU_DELETED               = "Menu deleted successfully"
+	MENU_NAME_TAKEN            = "Menu route name is already taken"
+	MENU_HAS_CHILDREN          = "Menu has child entries; delete them first"
+	MENU_INVALID_PARENT        = "Menu parent is invalid"
+	INVALID_PERMISSION         = "Permission code is not recognised"
 )
 
 // File-related error and success messages
@@ -58,6 +210,36 @@ const (
 	INVALID_PAYMENT_METHOD     = "Invalid payment method"
 	REFUND_SUCCESSFUL          = "Refund issued successfully"
 	REFUND_FAILED              = "Refund process failed"
+	PAYMENT_PENDING            = "Payment is pending confirmation"
+	ORDER_NOT_FOUND            = "Order not found"
+	INVALID_PAYMENT_INPUT      = "Invalid payment input"
+	ORDER_NOT_PAYABLE          = "Order cannot be paid"
+	PAYMENT_IN_PROGRESS        = "A payment for this order is already in progress"
+	REFUND_NOT_ALLOWED         = "Refund not allowed"
+	INVALID_WEBHOOK_SIGNATURE  = "Invalid webhook signature"
+	UNKNOWN_PAYMENT_GATEWAY    = "Unknown payment gateway"
+	WEBHOOK_PROCESSED          = "Webhook processed successfully"
+	WEBHOOK_ALREADY_PROCESSED  = "Webhook already processed"
+)
+
+// Invoice-related error and success messages
+const (
+	INVOICE_ISSUED             = "Invoice issued successfully"
+	INVOICE_NOT_FOUND          = "Invoice not found"
+	INVALID_INVOICE_INPUT      = "Invalid invoice input"
+	INVOICE_NOT_ISSUABLE       = "Invoice cannot be issued"
+)
+
+// Settlement-related error and success messages
+const (
+	SETTLEMENT_CREATED         = "Settlement statement created successfully"
+	SETTLEMENT_APPROVED        = "Settlement statement approved successfully"
+	SETTLEMENT_PAID            = "Settlement statement marked paid"
+	SETTLEMENT_DISCARDED       = "Settlement statement discarded successfully"
+	SETTLEMENT_NOT_FOUND       = "Settlement statement not found"
+	INVALID_SETTLEMENT_INPUT   = "Invalid settlement input"
+	SETTLEMENT_STATE_CONFLICT  = "Settlement statement cannot be changed in its current status"
+	NOTHING_TO_SETTLE          = "Nothing to settle in the period"
 )
 
 // Notification-related messages
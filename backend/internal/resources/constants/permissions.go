@@ -0,0 +1,222 @@
+// internal/resources/permissions.go
+package resources
+
+// PERMISSION represents an action a role may perform
+type PERMISSION string
+
+// PERMISSION constants
+const (
+	PERM_USER_READ   PERMISSION = "user:read"
+	PERM_USER_CREATE PERMISSION = "user:create"
+	PERM_USER_UPDATE PERMISSION = "user:update"
+	PERM_USER_DELETE PERMISSION = "user:delete"
+	PERM_USER_UNLOCK PERMISSION = "user:unlock"
+	PERM_MENU_READ   PERMISSION = "menu:read"
+	PERM_MENU_MANAGE PERMISSION = "menu:manage"
+
+	PERM_ALLIE_READ   PERMISSION = "allie:read"
+	PERM_ALLIE_CREATE PERMISSION = "allie:create"
+	PERM_ALLIE_UPDATE PERMISSION = "allie:update"
+	PERM_ALLIE_DELETE PERMISSION = "allie:delete"
+
+	PERM_CREW_READ   PERMISSION = "crew:read"
+	PERM_CREW_CREATE PERMISSION = "crew:create"
+	PERM_CREW_UPDATE PERMISSION = "crew:update"
+	PERM_CREW_DELETE PERMISSION = "crew:delete"
+
+	PERM_CUSTOMER_READ   PERMISSION = "customer:read"
+	PERM_CUSTOMER_MANAGE PERMISSION = "customer:manage"
+
+	PERM_PLAN_READ   PERMISSION = "plan:read"
+	PERM_PLAN_MANAGE PERMISSION = "plan:manage"
+
+	PERM_TRAINER_READ   PERMISSION = "trainer:read"
+	PERM_TRAINER_MANAGE PERMISSION = "trainer:manage"
+
+	PERM_OFFERING_READ   PERMISSION = "offering:read"
+	PERM_OFFERING_MANAGE PERMISSION = "offering:manage"
+
+	PERM_CLASS_READ   PERMISSION = "class:read"
+	PERM_CLASS_MANAGE PERMISSION = "class:manage"
+
+	PERM_BOOKING_READ   PERMISSION = "booking:read"
+	PERM_BOOKING_MANAGE PERMISSION = "booking:manage"
+	PERM_BOOKING_SELF   PERMISSION = "booking:self"
+
+	PERM_CHECKIN_READ   PERMISSION = "checkin:read"
+	PERM_CHECKIN_MANAGE PERMISSION = "checkin:manage"
+	PERM_CHECKIN_SELF   PERMISSION = "checkin:self"
+
+	PERM_PAYMENT_READ   PERMISSION = "payment:read"
+	PERM_PAYMENT_MANAGE PERMISSION = "payment:manage"
+	PERM_PAYMENT_SELF   PERMISSION = "payment:self"
+
+	PERM_INVOICE_READ   PERMISSION = "invoice:read"
+	PERM_INVOICE_MANAGE PERMISSION = "invoice:manage"
+	PERM_INVOICE_SELF   PERMISSION = "invoice:self"
+
+	PERM_SETTLEMENT_READ   PERMISSION = "settlement:read"
+	PERM_SETTLEMENT_MANAGE PERMISSION = "settlement:manage"
+)
+
+// allPermissions lists every permission, in the order they are reported
+var allPermissions = []PERMISSION{
+	PERM_USER_READ,
+	PERM_USER_CREATE,
+	PERM_USER_UPDATE,
+	PERM_USER_DELETE,
+	PERM_USER_UNLOCK,
+	PERM_MENU_READ,
+	PERM_MENU_MANAGE,
+	PERM_ALLIE_READ,
+	PERM_ALLIE_CREATE,
+	PERM_ALLIE_UPDATE,
+	PERM_ALLIE_DELETE,
+	PERM_CREW_READ,
+	PERM_CREW_CREATE,
+	PERM_CREW_UPDATE,
+	PERM_CREW_DELETE,
+	PERM_CUSTOMER_READ,
+	PERM_CUSTOMER_MANAGE,
+	PERM_PLAN_READ,
+	PERM_PLAN_MANAGE,
+	PERM_TRAINER_READ,
+	PERM_TRAINER_MANAGE,
+	PERM_OFFERING_READ,
+	PERM_OFFERING_MANAGE,
+	PERM_CLASS_READ,
+	PERM_CLASS_MANAGE,
+	PERM_BOOKING_READ,
+	PERM_BOOKING_MANAGE,
+	PERM_BOOKING_SELF,
+	PERM_CHECKIN_READ,
+	PERM_CHECKIN_MANAGE,
+	PERM_CHECKIN_SELF,
+	PERM_PAYMENT_READ,
+	PERM_PAYMENT_MANAGE,
+	PERM_PAYMENT_SELF,
+	PERM_INVOICE_READ,
+	PERM_INVOICE_MANAGE,
+	PERM_INVOICE_SELF,
+	PERM_SETTLEMENT_READ,
+	PERM_SETTLEMENT_MANAGE,
+}
+
+// rolePermissions maps each role to the permissions it is granted.
+// SUPERADMIN is implicitly granted every permission.
+var rolePermissions = map[USERROLE][]PERMISSION{
+	ADMIN: {
+		PERM_USER_READ,
+		PERM_USER_CREATE,
+		PERM_USER_UPDATE,
+		PERM_USER_DELETE,
+		PERM_USER_UNLOCK,
+		PERM_MENU_READ,
+		PERM_ALLIE_READ,
+		PERM_ALLIE_CREATE,
+		PERM_ALLIE_UPDATE,
+		PERM_ALLIE_DELETE,
+		PERM_CREW_READ,
+		PERM_CREW_CREATE,
+		PERM_CREW_UPDATE,
+		PERM_CREW_DELETE,
+		PERM_CUSTOMER_READ,
+		PERM_CUSTOMER_MANAGE,
+		PERM_PLAN_READ,
+		PERM_PLAN_MANAGE,
+		PERM_TRAINER_READ,
+		PERM_TRAINER_MANAGE,
+		PERM_OFFERING_READ,
+		PERM_OFFERING_MANAGE,
+		PERM_CLASS_READ,
+		PERM_CLASS_MANAGE,
+		PERM_BOOKING_READ,
+		PERM_BOOKING_MANAGE,
+		PERM_CHECKIN_READ,
+		PERM_CHECKIN_MANAGE,
+		PERM_PAYMENT_READ,
+		PERM_PAYMENT_MANAGE,
+		PERM_INVOICE_READ,
+		PERM_INVOICE_MANAGE,
+		PERM_SETTLEMENT_READ,
+		PERM_SETTLEMENT_MANAGE,
+	},
+	// GYM users are limited to their own allie by the services
+	GYM: {
+		PERM_CREW_READ,
+		PERM_CREW_CREATE,
+		PERM_CREW_UPDATE,
+		PERM_CREW_DELETE,
+		PERM_CUSTOMER_READ,
+		PERM_CUSTOMER_MANAGE,
+		PERM_PLAN_READ,
+		PERM_PLAN_MANAGE,
+		PERM_TRAINER_READ,
+		PERM_TRAINER_MANAGE,
+		PERM_OFFERING_READ,
+		PERM_OFFERING_MANAGE,
+		PERM_CLASS_READ,
+		PERM_CLASS_MANAGE,
+		PERM_BOOKING_READ,
+		PERM_BOOKING_MANAGE,
+		PERM_CHECKIN_READ,
+		PERM_CHECKIN_MANAGE,
+		PERM_PAYMENT_READ,
+		PERM_PAYMENT_MANAGE,
+		PERM_INVOICE_READ,
+		PERM_INVOICE_MANAGE,
+		PERM_SETTLEMENT_READ,
+	},
+	// GYMSTAFF users are limited to the crew of their trainer profile by the services
+	GYMSTAFF: {
+		PERM_CHECKIN_READ,
+		PERM_CHECKIN_MANAGE,
+	},
+	CUSTOMER: {
+		PERM_BOOKING_SELF,
+		PERM_CHECKIN_SELF,
+		PERM_PAYMENT_SELF,
+		PERM_INVOICE_SELF,
+	},
+}
+
+// HasPermission reports whether the role is granted the permission
+func (r USERROLE) HasPermission(permission PERMISSION) bool {
+	if r == SUPERADMIN {
+		return true
+	}
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManage reports whether the role may create, change or delete users of the
+// target role. Roles are ranked SUPERADMIN first; a role only manages roles
+// ranked below it, except SUPERADMIN, which manages every role.
+func (r USERROLE) CanManage(target USERROLE) bool {
+	if r == SUPERADMIN {
+		return true
+	}
+	return r != INVALID && target > r
+}
+
+// Permissions returns the permissions granted to the role
+func (r USERROLE) Permissions() []PERMISSION {
+	if r == SUPERADMIN {
+		return allPermissions
+	}
+	return rolePermissions[r]
+}
+
+// IsValidPermission reports whether the permission is one the system defines
+func IsValidPermission(permission PERMISSION) bool {
+	for _, p := range allPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -6,9 +6,11 @@ const (
 	STATUS_NO_CONTENT          = 204 // No content to return
 	STATUS_BAD_REQUEST         = 400 // Invalid client request
 	STATUS_UNAUTHORIZED        = 401 // Authentication required or failed
+	STATUS_PAYMENT_REQUIRED    = 402 // Payment declined by the gateway
 	STATUS_FORBIDDEN           = 403 // Insufficient permissions
 	STATUS_NOT_FOUND           = 404 // Resource not found
 	STATUS_CONFLICT            = 409 // Conflict with current state (e.g., duplicate resource)
+	STATUS_TOO_MANY_REQUESTS   = 429 // Rate limited or temporarily locked out
 	STATUS_INTERNAL_SERVER_ERR = 500 // Internal server error
 	STATUS_SERVICE_UNAVAILABLE = 503 // Service unavailable
 )
@@ -0,0 +1,267 @@
+// internal/services/auth_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	tokens "backend/pkg/jwt"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New(INVALID_CREDENTIALS)
+	ErrAccountInactive     = errors.New(ACCOUNT_INACTIVE)
+	ErrInvalidRefreshToken = errors.New(REFRESH_TOKEN_INVALID)
+	ErrRefreshTokenReused  = errors.New(REFRESH_TOKEN_REUSED)
+)
+
+// dummyPasswordHash is compared against when no user matches, so unknown
+// identifiers take about as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("flexiofit-dummy-password"), bcrypt.DefaultCost)
+
+// TokenPair is the access and refresh token handed to a client
+type TokenPair struct {
+	AccessToken           string
+	RefreshToken          string
+	MustChangePassword    bool
+	MFAEnrollmentRequired bool
+}
+
+type AuthService struct {
+	userRepository         repository.UserRepositoryInterface
+	refreshTokenRepository repository.RefreshTokenRepositoryInterface
+	mfaRepository          repository.MFARepositoryInterface
+	lockoutService         *LockoutService
+	mfaRequiredRoles       []USERROLE
+}
+
+func NewAuthService(
+	userRepository repository.UserRepositoryInterface,
+	refreshTokenRepository repository.RefreshTokenRepositoryInterface,
+	mfaRepository repository.MFARepositoryInterface,
+	lockoutService *LockoutService,
+	mfaRequiredRoles []USERROLE,
+) *AuthService {
+	return &AuthService{
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		mfaRepository:          mfaRepository,
+		lockoutService:         lockoutService,
+		mfaRequiredRoles:       mfaRequiredRoles,
+	}
+}
+
+// Login resolves a user by username, email or mobile and verifies the password.
+// Locked or backing-off accounts and IPs are refused before the password is checked.
+func (s *AuthService) Login(ctx context.Context, identifier, password, clientIP string) (*models.User, error) {
+	if err := s.lockoutService.CheckIP(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.FindByLogin(identifier)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+			if err := s.lockoutService.RecordFailure(ctx, clientIP, 0); err != nil {
+				return nil, err
+			}
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := s.lockoutService.CheckAccount(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if err := s.lockoutService.RecordFailure(ctx, clientIP, user.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if !user.IsActive || user.IsDeleted {
+		return nil, ErrAccountInactive
+	}
+
+	if err := s.lockoutService.RecordSuccess(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// IssueTokens starts a new session for the user on the device, replacing any
+// session the user already had there.
+func (s *AuthService) IssueTokens(ctx context.Context, user *models.User, deviceID string) (*TokenPair, error) {
+	if err := s.refreshTokenRepository.RevokeDevice(user.ID, deviceID); err != nil {
+		return nil, err
+	}
+
+	familyID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	record, err := newRefreshRecord(user.ID, deviceID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepository.Create(record); err != nil {
+		return nil, err
+	}
+
+	return s.mintTokens(user, record)
+}
+
+// RefreshTokens rotates a refresh token. Presenting a token that was already
+// rotated or revoked is treated as theft and revokes the whole family.
+func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := middleware.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	current, err := s.refreshTokenRepository.FindByJTI(claims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		return nil, s.revokeReusedFamily(current)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepository.FindByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.IsDeleted {
+		if err := s.refreshTokenRepository.RevokeAllForUser(user.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountInactive
+	}
+
+	next, err := newRefreshRecord(current.UserID, current.DeviceID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.refreshTokenRepository.Rotate(current, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// A concurrent request rotated this token first
+		return nil, s.revokeReusedFamily(current)
+	}
+
+	return s.mintTokens(user, next)
+}
+
+// Logout revokes the session the caller is using
+func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	return s.refreshTokenRepository.RevokeFamily(sessionID)
+}
+
+// LogoutAll revokes every session the user holds
+func (s *AuthService) LogoutAll(ctx context.Context, userID uint) error {
+	return s.refreshTokenRepository.RevokeAllForUser(userID)
+}
+
+func (s *AuthService) revokeReusedFamily(token *models.RefreshToken) error {
+	logging.Log.Warn("Refresh token reuse detected, revoking session",
+		zap.Uint("user_id", token.UserID),
+		zap.String("device_id", token.DeviceID),
+		zap.String("family_id", token.FamilyID),
+	)
+	if err := s.refreshTokenRepository.RevokeFamily(token.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func newRefreshRecord(userID uint, deviceID, familyID string) (*models.RefreshToken, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return &models.RefreshToken{
+		UserID:    userID,
+		DeviceID:  deviceID,
+		JTI:       jti,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(tokens.RefreshTTL()),
+	}, nil
+}
+
+func (s *AuthService) mintTokens(user *models.User, record *models.RefreshToken) (*TokenPair, error) {
+	enrollmentRequired, err := s.mfaEnrollmentRequired(user)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := middleware.GenerateTokens(middleware.TokenSubject{
+		UserID:                user.ID,
+		Username:              user.Username,
+		Role:                  user.UserType,
+		SessionID:             record.FamilyID,
+		MustChangePassword:    user.MustChangePassword,
+		MFAEnrollmentRequired: enrollmentRequired,
+	}, record.JTI)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		MustChangePassword:    user.MustChangePassword,
+		MFAEnrollmentRequired: enrollmentRequired,
+	}, nil
+}
+
+// mfaEnrollmentRequired reports whether the user's role must use MFA but the
+// user has not enrolled yet
+func (s *AuthService) mfaEnrollmentRequired(user *models.User) (bool, error) {
+	for _, role := range s.mfaRequiredRoles {
+		if role == user.UserType {
+			enabled, err := s.mfaRepository.IsEnabled(user.ID)
+			if err != nil {
+				return false, err
+			}
+			return !enabled, nil
+		}
+	}
+	return false, nil
+}
+
+// newTokenID returns a random 128-bit identifier encoded as hex
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
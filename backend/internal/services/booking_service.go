@@ -0,0 +1,451 @@
+// internal/services/booking_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/notifications"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// bookingTimeLayout formats session times in the texts sent to customers
+const bookingTimeLayout = "Mon 2 Jan 15:04"
+
+var (
+	ErrBookingNotFound   = errors.New(BOOKING_NOT_FOUND)
+	ErrInvalidBooking    = errors.New(INVALID_BOOKING_INPUT)
+	ErrBookingNotAllowed = errors.New(BOOKING_NOT_ALLOWED)
+	ErrAlreadyBooked     = errors.New(ALREADY_BOOKED)
+	ErrBookingNotActive  = errors.New(BOOKING_NOT_ACTIVE)
+)
+
+type BookingService struct {
+	bookingRepository  repository.BookingRepositoryInterface
+	classRepository    repository.ClassRepositoryInterface
+	customerRepository repository.CustomerRepositoryInterface
+	crewRepository     repository.FitCrewRepositoryInterface
+	fitAllieService    *FitAllieService
+	fitCrewService     *FitCrewService
+	smsSender          notifications.SMSSender
+}
+
+func NewBookingService(
+	bookingRepository repository.BookingRepositoryInterface,
+	classRepository repository.ClassRepositoryInterface,
+	customerRepository repository.CustomerRepositoryInterface,
+	crewRepository repository.FitCrewRepositoryInterface,
+	fitAllieService *FitAllieService,
+	fitCrewService *FitCrewService,
+	smsSender notifications.SMSSender,
+) *BookingService {
+	return &BookingService{
+		bookingRepository:  bookingRepository,
+		classRepository:    classRepository,
+		customerRepository: customerRepository,
+		crewRepository:     crewRepository,
+		fitAllieService:    fitAllieService,
+		fitCrewService:     fitCrewService,
+		smsSender:          smsSender,
+	}
+}
+
+// GetPolicy returns the booking policy of an allie, the default one when it has not set one
+func (s *BookingService) GetPolicy(ctx context.Context, requester Requester, allieID uint) (*models.BookingPolicy, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	return s.policyFor(int(allieID))
+}
+
+// UpdatePolicy sets the booking policy of an allie. It applies to bookings
+// and cancellations made from then on.
+func (s *BookingService) UpdatePolicy(ctx context.Context, requester Requester, allieID uint, input dtos.UpdateBookingPolicyRequest) (*models.BookingPolicy, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	policy, err := s.policyFor(int(allieID))
+	if err != nil {
+		return nil, err
+	}
+
+	policy.BookingWindowDays = input.BookingWindowDays
+	policy.CancelCutoffMinutes = *input.CancelCutoffMinutes
+	policy.MaxStrikes = *input.MaxStrikes
+	policy.StrikeWindowDays = input.StrikeWindowDays
+	policy.UpdatedBy = int(requester.UserID)
+	if err := s.bookingRepository.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ListSessionBookings returns the bookings of a session of the crew, waitlist included
+func (s *BookingService) ListSessionBookings(ctx context.Context, requester Requester, crewID, sessionID uint) ([]models.ClassBooking, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	if _, err := s.crewSession(crewID, sessionID); err != nil {
+		return nil, err
+	}
+	return s.bookingRepository.ListBySession(sessionID)
+}
+
+// BookForCustomer books a session of the crew for one of its customers, on
+// the waitlist when the session is full
+func (s *BookingService) BookForCustomer(ctx context.Context, requester Requester, crewID, sessionID uint, input dtos.BookClassRequest) (*models.ClassBooking, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.crewSession(crewID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepository.FindByID(input.CustomerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(customer.CrewID) != crewID {
+		return nil, ErrCustomerNotFound
+	}
+	return s.book(crew, session, customer, requester.UserID, time.Now())
+}
+
+// BookForSelf books a session for the requester's membership at its crew, on
+// the waitlist when the session is full
+func (s *BookingService) BookForSelf(ctx context.Context, requester Requester, sessionID uint) (*models.ClassBooking, error) {
+	session, err := s.classRepository.FindSessionByID(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepository.FindByUserAndCrew(requester.UserID, session.CrewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: you are not a member of this gym", ErrBookingNotAllowed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	crew, err := s.crewRepository.FindByID(uint(session.CrewID))
+	if err != nil {
+		return nil, err
+	}
+	return s.book(crew, session, customer, requester.UserID, time.Now())
+}
+
+// CancelBooking cancels a booking of a session of the crew. A spot given up
+// later than the policy's cutoff is a late cancellation.
+func (s *BookingService) CancelBooking(ctx context.Context, requester Requester, crewID, sessionID, bookingID uint) (*models.ClassBooking, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.sessionBooking(crewID, sessionID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, crew, booking, requester.UserID, time.Now())
+}
+
+// CancelOwnBooking cancels a booking of the requester before its session starts
+func (s *BookingService) CancelOwnBooking(ctx context.Context, requester Requester, bookingID uint) (*models.ClassBooking, error) {
+	booking, err := s.bookingRepository.FindByID(bookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if booking.Customer.UserID != requester.UserID {
+		return nil, ErrBookingNotFound
+	}
+
+	now := time.Now()
+	if !now.Before(booking.Session.StartsAt) {
+		return nil, fmt.Errorf("%w: the session has already started", ErrBookingNotAllowed)
+	}
+	crew, err := s.crewRepository.FindByID(uint(booking.Session.CrewID))
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, crew, booking, requester.UserID, now)
+}
+
+// MarkAttendance records whether the customer of a booking came, once the
+// session has started. A customer who did not come counts a no-show.
+func (s *BookingService) MarkAttendance(ctx context.Context, requester Requester, crewID, sessionID, bookingID uint, input dtos.MarkAttendanceRequest) (*models.ClassBooking, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	booking, err := s.sessionBooking(crewID, sessionID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Session.Status != CLASS_SESSION_SCHEDULED {
+		return nil, ErrSessionNotScheduled
+	}
+	if time.Now().Before(booking.Session.StartsAt) {
+		return nil, fmt.Errorf("%w: attendance can be recorded once the session starts", ErrInvalidBooking)
+	}
+
+	status := BOOKING_NO_SHOW
+	if *input.Attended {
+		status = BOOKING_ATTENDED
+	}
+	marked, err := s.bookingRepository.MarkAttendance(booking, status, requester.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, ErrBookingNotActive
+	}
+	return booking, nil
+}
+
+// ListOwnBookings returns a page of the requester's bookings at every crew,
+// in session start order
+func (s *BookingService) ListOwnBookings(ctx context.Context, requester Requester, filter dtos.MyBookingFilter) ([]models.ClassBooking, int64, error) {
+	options := repository.BookingListOptions{
+		Filters: map[string]interface{}{
+			"offset": filter.Page,
+			"limit":  filter.Limit,
+		},
+		UserID: requester.UserID,
+	}
+	if filter.Status != "" {
+		status, err := parseBookingStatus(filter.Status)
+		if err != nil {
+			return nil, 0, err
+		}
+		options.Filters["class_bookings.status"] = map[string]interface{}{"Op": "eq", "value": status}
+	}
+	if !filter.IncludePast {
+		now := time.Now()
+		options.From = &now
+	}
+	return s.bookingRepository.ListForUser(options)
+}
+
+// ListBookableSessions returns a page of the scheduled sessions of a crew the
+// requester is a member of, within its allie's booking window, with their occupancy
+func (s *BookingService) ListBookableSessions(ctx context.Context, requester Requester, crewID uint, filter dtos.BookableSessionFilter) ([]models.ClassSession, map[uint]models.SessionOccupancy, int64, error) {
+	if _, err := s.customerRepository.FindByUserAndCrew(requester.UserID, int(crewID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, 0, ErrCrewNotFound
+		}
+		return nil, nil, 0, err
+	}
+	crew, err := s.crewRepository.FindByID(crewID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	policy, err := s.policyFor(crew.AllieID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	now := time.Now()
+	until := now.AddDate(0, 0, policy.BookingWindowDays)
+	options := repository.ClassSessionListOptions{
+		Filters: map[string]interface{}{
+			"offset": filter.Page,
+			"limit":  filter.Limit,
+			"status": map[string]interface{}{"Op": "eq", "value": CLASS_SESSION_SCHEDULED},
+		},
+		CrewID: &crewID,
+		From:   &now,
+		To:     &until,
+	}
+	if filter.ServiceID != nil {
+		options.Filters["fit_service_id"] = map[string]interface{}{"Op": "eq", "value": *filter.ServiceID}
+	}
+	sessions, total, err := s.classRepository.ListSessions(options)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	ids := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	occupancy, err := s.bookingRepository.Occupancy(ids)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return sessions, occupancy, total, nil
+}
+
+// book checks that the customer may book the session under the crew's
+// policy and books it
+func (s *BookingService) book(crew *models.FitCrew, session *models.ClassSession, customer *models.Customer, bookedBy uint, now time.Time) (*models.ClassBooking, error) {
+	if session.Status != CLASS_SESSION_SCHEDULED {
+		return nil, ErrSessionNotScheduled
+	}
+	policy, err := s.policyFor(crew.AllieID)
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(session.StartsAt) {
+		return nil, fmt.Errorf("%w: the session has already started", ErrBookingNotAllowed)
+	}
+	if !policy.OpensBookingAt(session.StartsAt, now) {
+		return nil, fmt.Errorf("%w: sessions can be booked at most %d days ahead", ErrBookingNotAllowed, policy.BookingWindowDays)
+	}
+	if !customer.MembershipActiveAt(session.StartsAt) {
+		return nil, fmt.Errorf("%w: the membership is not active on the session date", ErrBookingNotAllowed)
+	}
+
+	covered := false
+	if customer.PlanID != nil {
+		if covered, err = s.bookingRepository.PlanCovers(*customer.PlanID, session.ServiceID); err != nil {
+			return nil, err
+		}
+	}
+	if !covered {
+		return nil, fmt.Errorf("%w: the membership plan does not include %s", ErrBookingNotAllowed, session.FitService.Name)
+	}
+
+	if policy.MaxStrikes > 0 {
+		strikes, err := s.bookingRepository.CountStrikes(customer.ID, now.AddDate(0, 0, -policy.StrikeWindowDays), now)
+		if err != nil {
+			return nil, err
+		}
+		if strikes >= int64(policy.MaxStrikes) {
+			return nil, fmt.Errorf("%w: %d late cancellations or no-shows in the last %d days",
+				ErrBookingNotAllowed, strikes, policy.StrikeWindowDays)
+		}
+	}
+
+	booking := &models.ClassBooking{
+		SessionID:  session.ID,
+		CustomerID: customer.ID,
+		CreatedBy:  int(bookedBy),
+		UpdatedBy:  int(bookedBy),
+	}
+	outcome, err := s.bookingRepository.Book(booking, now)
+	if err != nil {
+		return nil, err
+	}
+	switch outcome {
+	case repository.BookingSessionClosed:
+		return nil, ErrSessionNotScheduled
+	case repository.BookingDuplicate:
+		return nil, ErrAlreadyBooked
+	}
+	booking.Session = *session
+	booking.Customer = *customer
+	return booking, nil
+}
+
+// cancel cancels an active booking and texts the customers promoted from the
+// waitlist into the spot it frees
+func (s *BookingService) cancel(ctx context.Context, crew *models.FitCrew, booking *models.ClassBooking, cancelledBy uint, now time.Time) (*models.ClassBooking, error) {
+	policy, err := s.policyFor(crew.AllieID)
+	if err != nil {
+		return nil, err
+	}
+
+	late := policy.LateCancellationAt(booking.Session.StartsAt, now)
+	outcome, promoted, err := s.bookingRepository.Cancel(booking, late, now, cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == repository.BookingInactive {
+		return nil, ErrBookingNotActive
+	}
+
+	notifyPromoted(ctx, s.smsSender, crew, &booking.Session, promoted)
+	return booking, nil
+}
+
+// policyFor returns the booking policy of an allie, the default one when it has not set one
+func (s *BookingService) policyFor(allieID int) (*models.BookingPolicy, error) {
+	policy, err := s.bookingRepository.FindPolicy(uint(allieID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultBookingPolicy(allieID), nil
+	}
+	return policy, err
+}
+
+func (s *BookingService) crewSession(crewID, sessionID uint) (*models.ClassSession, error) {
+	session, err := s.classRepository.FindSessionByID(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(session.CrewID) != crewID {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *BookingService) sessionBooking(crewID, sessionID, bookingID uint) (*models.ClassBooking, error) {
+	booking, err := s.bookingRepository.FindByID(bookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if booking.SessionID != sessionID || uint(booking.Session.CrewID) != crewID {
+		return nil, ErrBookingNotFound
+	}
+	return booking, nil
+}
+
+func parseBookingStatus(value string) (BOOKINGSTATUS, error) {
+	status := BOOKINGSTATUS(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case BOOKING_BOOKED, BOOKING_WAITLISTED, BOOKING_CANCELLED, BOOKING_LATE_CANCELLED, BOOKING_ATTENDED, BOOKING_NO_SHOW:
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: unknown status %q", ErrInvalidBooking, value)
+}
+
+// notifyPromoted texts the customers promoted from the waitlist of a session
+func notifyPromoted(ctx context.Context, sender notifications.SMSSender, crew *models.FitCrew, session *models.ClassSession, promoted []models.ClassBooking) {
+	if len(promoted) == 0 {
+		return
+	}
+	logging.Log.Info("Waitlisted bookings promoted",
+		zap.Uint("session_id", session.ID),
+		zap.Int("promoted", len(promoted)),
+	)
+	notifyBookings(ctx, sender, promoted, fmt.Sprintf("A spot opened up in %s on %s at %s and it is now yours.",
+		session.Name, session.StartsAt.In(crew.Location()).Format(bookingTimeLayout), crew.GymName))
+}
+
+// notifyBookings texts the same message to the customers of the bookings.
+// Delivery failures are logged; they do not undo the booking change.
+func notifyBookings(ctx context.Context, sender notifications.SMSSender, bookings []models.ClassBooking, body string) {
+	for _, booking := range bookings {
+		if booking.Customer.Mobile == "" {
+			continue
+		}
+		if err := sender.SendSMS(ctx, booking.Customer.Mobile, body); err != nil {
+			logging.Log.Warn("Booking notification not sent",
+				zap.Uint("booking_id", booking.ID),
+				zap.Error(err),
+			)
+		}
+	}
+}
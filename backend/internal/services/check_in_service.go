@@ -0,0 +1,311 @@
+// internal/services/check_in_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCheckIn     = errors.New(INVALID_CHECKIN_INPUT)
+	ErrEntryRefused       = errors.New(ENTRY_REFUSED)
+	ErrCrewAtCapacity     = errors.New(CREW_AT_CAPACITY)
+	ErrAlreadyCheckedIn   = errors.New(ALREADY_CHECKED_IN)
+	ErrNotCheckedIn       = errors.New(NOT_CHECKED_IN)
+	ErrInvalidCheckInCode = errors.New(INVALID_CHECKIN_CODE)
+	ErrCheckInCodeUsed    = errors.New(CHECKIN_CODE_USED)
+)
+
+type CheckInService struct {
+	visitRepository    repository.CrewVisitRepositoryInterface
+	customerRepository repository.CustomerRepositoryInterface
+	crewRepository     repository.FitCrewRepositoryInterface
+	trainerRepository  repository.TrainerRepositoryInterface
+	fitCrewService     *FitCrewService
+	codeTTL            time.Duration
+}
+
+func NewCheckInService(
+	visitRepository repository.CrewVisitRepositoryInterface,
+	customerRepository repository.CustomerRepositoryInterface,
+	crewRepository repository.FitCrewRepositoryInterface,
+	trainerRepository repository.TrainerRepositoryInterface,
+	fitCrewService *FitCrewService,
+	codeTTL time.Duration,
+) *CheckInService {
+	return &CheckInService{
+		visitRepository:    visitRepository,
+		customerRepository: customerRepository,
+		crewRepository:     crewRepository,
+		trainerRepository:  trainerRepository,
+		fitCrewService:     fitCrewService,
+		codeTTL:            codeTTL,
+	}
+}
+
+// CheckIn lets a customer of the crew in when the membership is active, its
+// visit limits are not used up and the crew is below capacity
+func (s *CheckInService) CheckIn(ctx context.Context, requester Requester, crewID uint, input dtos.CheckInRequest) (*models.CrewVisit, error) {
+	crew, err := s.AuthorizeDesk(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	method := CHECKIN_DESK
+	if input.Method != "" {
+		method = CHECKINMETHOD(strings.ToUpper(strings.TrimSpace(input.Method)))
+		if method != CHECKIN_DESK && method != CHECKIN_KIOSK {
+			return nil, fmt.Errorf("%w: unknown method %q", ErrInvalidCheckIn, input.Method)
+		}
+	}
+	customer, err := s.crewCustomer(crewID, input.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.checkIn(crew, customer, method, requester.UserID, time.Now())
+}
+
+// IssueCode signs a short-lived check-in code for the signed-in user to show
+// at a kiosk. It is not tied to a crew: the kiosk checks the membership.
+func (s *CheckInService) IssueCode(ctx context.Context, requester Requester) (string, time.Time, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return middleware.GenerateCheckInToken(requester.UserID, tokenID, s.codeTTL)
+}
+
+// ScanCode checks in the customer whose code a kiosk of the crew scanned. A
+// code is accepted once, whether or not the entry is then allowed.
+func (s *CheckInService) ScanCode(ctx context.Context, requester Requester, crewID uint, input dtos.ScanCheckInCodeRequest) (*models.CrewVisit, error) {
+	crew, err := s.AuthorizeDesk(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := middleware.ValidateCheckInToken(strings.TrimSpace(input.Code))
+	if err != nil {
+		return nil, ErrInvalidCheckInCode
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, ErrInvalidCheckInCode
+	}
+
+	now := time.Now()
+	redeemed, err := s.visitRepository.RedeemCode(&models.RedeemedCheckInCode{
+		TokenID:   claims.ID,
+		UserID:    userID,
+		CrewID:    int(crewID),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if !redeemed {
+		logging.Log.Warn("Check-in code replayed",
+			zap.Uint("crew_id", crewID),
+			zap.Uint("user_id", userID),
+			zap.String("token_id", claims.ID),
+		)
+		return nil, ErrCheckInCodeUsed
+	}
+
+	customer, err := s.customerRepository.FindByUserAndCrew(userID, int(crewID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: not a member of this gym", ErrEntryRefused)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.checkIn(crew, customer, CHECKIN_KIOSK, requester.UserID, now)
+}
+
+// CheckOut closes the visit of a customer inside the crew
+func (s *CheckInService) CheckOut(ctx context.Context, requester Requester, crewID uint, input dtos.CheckOutRequest) (*models.CrewVisit, error) {
+	if _, err := s.AuthorizeDesk(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	customer, err := s.crewCustomer(crewID, input.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	visit, err := s.visitRepository.CheckOut(int(crewID), customer.ID, now.Add(-models.MaxVisitDuration), now, requester.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotCheckedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	visit.Customer = *customer
+	return visit, nil
+}
+
+// Occupancy returns the crew and the visits inside it right now
+func (s *CheckInService) Occupancy(ctx context.Context, requester Requester, crewID uint) (*models.FitCrew, []models.CrewVisit, error) {
+	crew, err := s.AuthorizeDesk(ctx, requester, crewID)
+	if err != nil {
+		return nil, nil, err
+	}
+	visits, err := s.visitRepository.CurrentVisits(crewID, time.Now().Add(-models.MaxVisitDuration))
+	if err != nil {
+		return nil, nil, err
+	}
+	return crew, visits, nil
+}
+
+// CustomerVisits returns a page of the attendance history of a customer of the crew
+func (s *CheckInService) CustomerVisits(ctx context.Context, requester Requester, crewID, customerID uint, filter dtos.VisitHistoryFilter) ([]models.CrewVisit, int64, error) {
+	crew, err := s.AuthorizeDesk(ctx, requester, crewID)
+	if err != nil {
+		return nil, 0, err
+	}
+	customer, err := s.crewCustomer(crewID, customerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var from, to *time.Time
+	if filter.From != "" {
+		date, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(filter.From), crew.Location())
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: from must be a YYYY-MM-DD date", ErrInvalidCheckIn)
+		}
+		from = &date
+	}
+	if filter.To != "" {
+		date, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(filter.To), crew.Location())
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: to must be a YYYY-MM-DD date", ErrInvalidCheckIn)
+		}
+		end := date.AddDate(0, 0, 1)
+		to = &end
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, 0, fmt.Errorf("%w: to cannot be before from", ErrInvalidCheckIn)
+	}
+
+	visits, total, err := s.visitRepository.ListByCustomer(customer.ID, map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range visits {
+		visits[i].Customer = *customer
+	}
+	return visits, total, nil
+}
+
+// AuthorizeDesk returns the crew when the requester may run its front desk:
+// anyone who may manage the crew, and the GYMSTAFF users of its visible trainers
+func (s *CheckInService) AuthorizeDesk(ctx context.Context, requester Requester, crewID uint) (*models.FitCrew, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if !errors.Is(err, ErrAllieAccessDenied) || requester.Role != GYMSTAFF {
+		return crew, err
+	}
+
+	trainer, findErr := s.trainerRepository.FindByUserID(requester.UserID)
+	if findErr != nil && !errors.Is(findErr, gorm.ErrRecordNotFound) {
+		return nil, findErr
+	}
+	if trainer == nil || uint(trainer.CrewID) != crewID || !trainer.IsVisible() {
+		return nil, err
+	}
+	return s.crewRepository.FindByID(crewID)
+}
+
+// checkIn records the visit of a customer, refusing entry when the membership
+// does not allow it or the crew is full
+func (s *CheckInService) checkIn(crew *models.FitCrew, customer *models.Customer, method CHECKINMETHOD, checkedInBy uint, now time.Time) (*models.CrewVisit, error) {
+	if !crew.IsActive {
+		return nil, fmt.Errorf("%w: the gym is not active", ErrEntryRefused)
+	}
+	if !customer.MembershipActiveAt(now) {
+		return nil, s.refuse(crew, customer, fmt.Sprintf("the membership is %s",
+			strings.ToLower(string(customer.MembershipStatusAt(now)))))
+	}
+
+	if customer.VisitLimit > 0 {
+		visits, err := s.visitRepository.CountVisits(customer.ID, customer.MembershipStart, now.Add(time.Second))
+		if err != nil {
+			return nil, err
+		}
+		if visits >= int64(customer.VisitLimit) {
+			return nil, s.refuse(crew, customer, fmt.Sprintf("all %d visits of the membership are used", customer.VisitLimit))
+		}
+	}
+	today := calendarDayIn(now, crew.Location())
+	if customer.VisitsPerDay > 0 {
+		visits, err := s.visitRepository.CountVisits(customer.ID, today, today.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+		if visits >= int64(customer.VisitsPerDay) {
+			return nil, s.refuse(crew, customer, fmt.Sprintf("the limit of %d visits per day is reached", customer.VisitsPerDay))
+		}
+	}
+
+	visit := &models.CrewVisit{
+		CrewID:      int(crew.ID),
+		CustomerID:  customer.ID,
+		VisitDate:   today,
+		CheckedInAt: now,
+		Method:      method,
+		CheckedInBy: int(checkedInBy),
+	}
+	outcome, err := s.visitRepository.CheckIn(visit, crew.Capacity, now.Add(-models.MaxVisitDuration))
+	if err != nil {
+		return nil, err
+	}
+	switch outcome {
+	case repository.CheckInInside:
+		return nil, ErrAlreadyCheckedIn
+	case repository.CheckInFull:
+		logging.Log.Info("Check-in refused at capacity",
+			zap.Uint("crew_id", crew.ID),
+			zap.Uint("customer_id", customer.ID),
+			zap.Int("capacity", crew.Capacity),
+		)
+		return nil, ErrCrewAtCapacity
+	}
+	visit.Customer = *customer
+	return visit, nil
+}
+
+// refuse logs a refused entry and returns the error telling why
+func (s *CheckInService) refuse(crew *models.FitCrew, customer *models.Customer, reason string) error {
+	logging.Log.Info("Check-in refused",
+		zap.Uint("crew_id", crew.ID),
+		zap.Uint("customer_id", customer.ID),
+		zap.String("reason", reason),
+	)
+	return fmt.Errorf("%w: %s", ErrEntryRefused, reason)
+}
+
+func (s *CheckInService) crewCustomer(crewID, customerID uint) (*models.Customer, error) {
+	customer, err := s.customerRepository.FindByID(customerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(customer.CrewID) != crewID {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
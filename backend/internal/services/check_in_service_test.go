@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	tokens "backend/pkg/jwt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	logging.Log = zap.NewNop()
+	if err := tokens.Configure(tokens.Config{
+		Algorithm: "HS256",
+		Secret:    "check-in-test-secret",
+		Issuer:    "flexiofit",
+		Audience:  "flexio-admin",
+	}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+type fakeAllieRepository struct {
+	repository.FitAllieRepositoryInterface
+	allie models.FitAllie
+}
+
+func (r *fakeAllieRepository) FindByID(id uint) (*models.FitAllie, error) {
+	if id != r.allie.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	allie := r.allie
+	return &allie, nil
+}
+
+type fakeCrewRepository struct {
+	repository.FitCrewRepositoryInterface
+	crew models.FitCrew
+}
+
+func (r *fakeCrewRepository) FindByID(id uint) (*models.FitCrew, error) {
+	if id != r.crew.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	crew := r.crew
+	return &crew, nil
+}
+
+type fakeCustomerRepository struct {
+	repository.CustomerRepositoryInterface
+	customers []models.Customer
+}
+
+func (r *fakeCustomerRepository) FindByUserAndCrew(userID uint, crewID int) (*models.Customer, error) {
+	for _, customer := range r.customers {
+		if customer.UserID == userID && customer.CrewID == crewID {
+			return &customer, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeVisitRepository redeems each token ID once, like the unique index on
+// redeemed_check_in_codes, and lets every visit in
+type fakeVisitRepository struct {
+	repository.CrewVisitRepositoryInterface
+	redeemed map[string]bool
+	visits   []models.CrewVisit
+}
+
+func (r *fakeVisitRepository) RedeemCode(code *models.RedeemedCheckInCode, now time.Time) (bool, error) {
+	if r.redeemed[code.TokenID] {
+		return false, nil
+	}
+	r.redeemed[code.TokenID] = true
+	return true, nil
+}
+
+func (r *fakeVisitRepository) CountVisits(customerID uint, from, to time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeVisitRepository) CheckIn(visit *models.CrewVisit, capacity int, since time.Time) (repository.CheckInOutcome, error) {
+	r.visits = append(r.visits, *visit)
+	return repository.CheckInApplied, nil
+}
+
+const (
+	testCrewID     = 3
+	testMemberID   = 40
+	testStrangerID = 41
+)
+
+func newCheckInTest() (*CheckInService, *fakeVisitRepository) {
+	now := time.Now()
+	crewRepository := &fakeCrewRepository{crew: models.FitCrew{AllieID: 2, IsActive: true}}
+	crewRepository.crew.ID = testCrewID
+	allieRepository := &fakeAllieRepository{}
+	allieRepository.allie.ID = 2
+	customers := &fakeCustomerRepository{customers: []models.Customer{{
+		UserID:          testMemberID,
+		CrewID:          testCrewID,
+		MembershipStart: now.AddDate(0, 0, -1),
+		MembershipEnd:   now.AddDate(0, 1, 0),
+	}}}
+	visits := &fakeVisitRepository{redeemed: make(map[string]bool)}
+
+	fitCrewService := NewFitCrewService(crewRepository, NewFitAllieService(allieRepository, nil))
+	service := NewCheckInService(visits, customers, crewRepository, nil, fitCrewService, time.Minute)
+	return service, visits
+}
+
+// kiosk is a requester allowed to run the desk of every crew
+var kiosk = Requester{UserID: 1, Role: SUPERADMIN}
+
+func issueCode(t *testing.T, service *CheckInService, userID uint) string {
+	t.Helper()
+	code, expiresAt, err := service.IssueCode(context.Background(), Requester{UserID: userID, Role: CUSTOMER})
+	if err != nil {
+		t.Fatalf("IssueCode: %v", err)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Fatalf("IssueCode expires at %v, before now", expiresAt)
+	}
+	return code
+}
+
+func scan(service *CheckInService, code string) (*models.CrewVisit, error) {
+	return service.ScanCode(context.Background(), kiosk, testCrewID, dtos.ScanCheckInCodeRequest{Code: code})
+}
+
+func TestScanCodeChecksInMember(t *testing.T) {
+	service, visits := newCheckInTest()
+
+	visit, err := scan(service, issueCode(t, service, testMemberID))
+	if err != nil {
+		t.Fatalf("ScanCode: %v", err)
+	}
+	if visit.Method != CHECKIN_KIOSK || visit.CrewID != testCrewID {
+		t.Errorf("visit = %+v, want a kiosk visit at crew %d", visit, testCrewID)
+	}
+	if len(visits.visits) != 1 {
+		t.Errorf("%d visits recorded, want 1", len(visits.visits))
+	}
+}
+
+func TestScanCodeRejectsReplay(t *testing.T) {
+	service, visits := newCheckInTest()
+	code := issueCode(t, service, testMemberID)
+
+	if _, err := scan(service, code); err != nil {
+		t.Fatalf("first scan: %v", err)
+	}
+	if _, err := scan(service, code); !errors.Is(err, ErrCheckInCodeUsed) {
+		t.Errorf("second scan error = %v, want %v", err, ErrCheckInCodeUsed)
+	}
+	if len(visits.visits) != 1 {
+		t.Errorf("%d visits recorded, want 1", len(visits.visits))
+	}
+}
+
+func TestScanCodeIsUsedUpByRefusedEntry(t *testing.T) {
+	service, _ := newCheckInTest()
+	code := issueCode(t, service, testStrangerID)
+
+	if _, err := scan(service, code); !errors.Is(err, ErrEntryRefused) {
+		t.Fatalf("scan of a non-member error = %v, want %v", err, ErrEntryRefused)
+	}
+	if _, err := scan(service, code); !errors.Is(err, ErrCheckInCodeUsed) {
+		t.Errorf("second scan error = %v, want %v", err, ErrCheckInCodeUsed)
+	}
+}
+
+func TestScanCodeRejectsExpiredCode(t *testing.T) {
+	service, visits := newCheckInTest()
+	code, _, err := middleware.GenerateCheckInToken(testMemberID, "expired-token", -time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateCheckInToken: %v", err)
+	}
+
+	if _, err := scan(service, code); !errors.Is(err, ErrInvalidCheckInCode) {
+		t.Errorf("scan error = %v, want %v", err, ErrInvalidCheckInCode)
+	}
+	if len(visits.redeemed) != 0 {
+		t.Error("an expired code was redeemed")
+	}
+}
+
+func TestScanCodeRejectsOtherTokenTypes(t *testing.T) {
+	service, visits := newCheckInTest()
+
+	pending, err := middleware.GenerateMFAPendingToken(testMemberID, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateMFAPendingToken: %v", err)
+	}
+	access, refresh, err := middleware.GenerateTokens(middleware.TokenSubject{UserID: testMemberID, Role: CUSTOMER}, "refresh-token")
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+
+	for name, code := range map[string]string{"mfa pending": pending, "access": access, "refresh": refresh, "garbage": "not-a-token"} {
+		if _, err := scan(service, code); !errors.Is(err, ErrInvalidCheckInCode) {
+			t.Errorf("scan of %s token error = %v, want %v", name, err, ErrInvalidCheckInCode)
+		}
+	}
+	if len(visits.redeemed) != 0 {
+		t.Error("a token that is not a check-in code was redeemed")
+	}
+}
@@ -0,0 +1,603 @@
+// internal/services/class_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/notifications"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+const (
+	// ClassGenerationDays is how far ahead sessions are generated when a class
+	// is created or changed
+	ClassGenerationDays = 28
+	// MaxClassGenerationDays bounds how far ahead sessions may be generated
+	MaxClassGenerationDays = 90
+
+	defaultSessionListDays = 7
+	maxSessionListDays     = 93
+)
+
+var (
+	ErrClassNotFound       = errors.New(CLASS_NOT_FOUND)
+	ErrInvalidClass        = errors.New(INVALID_CLASS_INPUT)
+	ErrSessionNotFound     = errors.New(SESSION_NOT_FOUND)
+	ErrSessionNotScheduled = errors.New(SESSION_NOT_SCHEDULED)
+	ErrTrainerDoubleBooked = errors.New(TRAINER_DOUBLE_BOOKED)
+)
+
+type ClassService struct {
+	classRepository   repository.ClassRepositoryInterface
+	bookingRepository repository.BookingRepositoryInterface
+	trainerRepository repository.TrainerRepositoryInterface
+	serviceRepository repository.FitServiceRepositoryInterface
+	fitCrewService    *FitCrewService
+	smsSender         notifications.SMSSender
+}
+
+func NewClassService(classRepository repository.ClassRepositoryInterface, bookingRepository repository.BookingRepositoryInterface, trainerRepository repository.TrainerRepositoryInterface, serviceRepository repository.FitServiceRepositoryInterface, fitCrewService *FitCrewService, smsSender notifications.SMSSender) *ClassService {
+	return &ClassService{
+		classRepository:   classRepository,
+		bookingRepository: bookingRepository,
+		trainerRepository: trainerRepository,
+		serviceRepository: serviceRepository,
+		fitCrewService:    fitCrewService,
+		smsSender:         smsSender,
+	}
+}
+
+// CreateTemplate adds a weekly class to the crew and generates its sessions
+// for the next ClassGenerationDays days
+func (s *ClassService) CreateTemplate(ctx context.Context, requester Requester, crewID uint, input dtos.CreateClassTemplateRequest) (*models.ClassTemplate, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.ClassTemplate{
+		CrewID:    int(crew.ID),
+		IsActive:  true,
+		CreatedBy: int(requester.UserID),
+		UpdatedBy: int(requester.UserID),
+	}
+	if err := s.prepareTemplate(crew, template, input); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := generateSessions(crew, template, now, now.AddDate(0, 0, ClassGenerationDays), requester.UserID)
+	if err := s.classRepository.CreateTemplate(template, sessions, trainerCheck(crew, sessions, 0, nil)); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetTemplate returns a class of the crew when the requester may manage the crew
+func (s *ClassService) GetTemplate(ctx context.Context, requester Requester, crewID, templateID uint) (*models.ClassTemplate, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	return s.findTemplate(crewID, templateID)
+}
+
+func (s *ClassService) ListTemplates(ctx context.Context, requester Requester, crewID uint) ([]models.ClassTemplate, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	return s.classRepository.ListTemplates(crewID)
+}
+
+// UpdateTemplate changes a class. Its upcoming sessions that were not edited
+// or cancelled on their own are generated again, as far ahead as before.
+// Booked sessions the change leaves as they were are kept; the other booked
+// ones are cancelled and their customers told, and their slots generated again.
+func (s *ClassService) UpdateTemplate(ctx context.Context, requester Requester, crewID, templateID uint, input dtos.UpdateClassTemplateRequest) (*models.ClassTemplate, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.findTemplate(crewID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareTemplate(crew, template, input.CreateClassTemplateRequest); err != nil {
+		return nil, err
+	}
+	if input.IsActive != nil {
+		template.IsActive = *input.IsActive
+	}
+	template.UpdatedBy = int(requester.UserID)
+
+	now := time.Now()
+	until := now.AddDate(0, 0, ClassGenerationDays)
+	last, err := s.classRepository.LastSessionStart(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && last.After(until) {
+		until = *last
+	}
+
+	sessions := generateSessions(crew, template, now, until, requester.UserID)
+	check := trainerCheck(crew, sessions, template.ID, &now)
+	// checked before any booked session is cancelled, and again under the
+	// trainer's lock when the sessions are replaced
+	if len(sessions) > 0 {
+		existing, err := s.classRepository.TrainerSessions(template.TrainerID, sessions[0].StartsAt, sessions[len(sessions)-1].EndsAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := check(existing); err != nil {
+			return nil, err
+		}
+	}
+
+	booked, err := s.classRepository.BookedGeneratedSessions(template.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	var changed []models.ClassSession
+	for _, session := range booked {
+		if !sameSchedule(&session, sessions) {
+			changed = append(changed, session)
+		}
+	}
+	if err := s.cancelGeneratedSessions(ctx, crew, changed, "The class schedule changed", requester.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.classRepository.UpdateTemplate(template, now, sessions, check); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes a class and its upcoming sessions that were not
+// edited on their own. Booked sessions are cancelled and their customers told.
+// Past sessions are kept.
+func (s *ClassService) DeleteTemplate(ctx context.Context, requester Requester, crewID, templateID uint) error {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findTemplate(crewID, templateID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	booked, err := s.classRepository.BookedGeneratedSessions(templateID, now)
+	if err != nil {
+		return err
+	}
+	if err := s.cancelGeneratedSessions(ctx, crew, booked, "The class was discontinued", requester.UserID); err != nil {
+		return err
+	}
+	return s.classRepository.DeleteTemplate(templateID, now)
+}
+
+// GenerateSessions generates the sessions of an active class up to the date
+// included, at most MaxClassGenerationDays ahead, and returns how many were added
+func (s *ClassService) GenerateSessions(ctx context.Context, requester Requester, crewID, templateID uint, input dtos.GenerateSessionsRequest) (int64, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return 0, err
+	}
+	template, err := s.findTemplate(crewID, templateID)
+	if err != nil {
+		return 0, err
+	}
+	if !template.IsActive {
+		return 0, fmt.Errorf("%w: class is not active", ErrInvalidClass)
+	}
+
+	now := time.Now()
+	untilDate, err := parseCrewDate(crew, "until", input.Until)
+	if err != nil {
+		return 0, err
+	}
+	until := untilDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	if until.After(now.AddDate(0, 0, MaxClassGenerationDays)) {
+		return 0, fmt.Errorf("%w: sessions can be generated at most %d days ahead", ErrInvalidClass, MaxClassGenerationDays)
+	}
+
+	sessions := generateSessions(crew, template, now, until, requester.UserID)
+	return s.classRepository.AddSessions(sessions, trainerCheck(crew, sessions, template.ID, nil))
+}
+
+// GetSession returns a session of the crew when the requester may manage the crew
+func (s *ClassService) GetSession(ctx context.Context, requester Requester, crewID, sessionID uint) (*models.ClassSession, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	return s.findSession(crewID, sessionID)
+}
+
+// ListSessions returns a page of the crew's sessions in start order, between
+// two dates on the crew's clock
+func (s *ClassService) ListSessions(ctx context.Context, requester Requester, crewID uint, filter dtos.ClassSessionFilter) ([]models.ClassSession, int64, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	from := calendarDayIn(time.Now(), crew.Location())
+	if filter.From != "" {
+		if from, err = parseCrewDate(crew, "from", filter.From); err != nil {
+			return nil, 0, err
+		}
+	}
+	to := from.AddDate(0, 0, defaultSessionListDays-1)
+	if filter.To != "" {
+		if to, err = parseCrewDate(crew, "to", filter.To); err != nil {
+			return nil, 0, err
+		}
+	}
+	if to.Before(from) {
+		return nil, 0, fmt.Errorf("%w: to cannot be before from", ErrInvalidClass)
+	}
+	if to.After(from.AddDate(0, 0, maxSessionListDays)) {
+		return nil, 0, fmt.Errorf("%w: the date range is limited to %d days", ErrInvalidClass, maxSessionListDays)
+	}
+	end := to.AddDate(0, 0, 1)
+
+	options := repository.ClassSessionListOptions{
+		Filters: map[string]interface{}{
+			"offset": filter.Page,
+			"limit":  filter.Limit,
+		},
+		CrewID:    &crewID,
+		TrainerID: filter.TrainerID,
+		From:      &from,
+		To:        &end,
+	}
+	if filter.ServiceID != nil {
+		options.Filters["fit_service_id"] = map[string]interface{}{"Op": "eq", "value": *filter.ServiceID}
+	}
+	if filter.Status != "" {
+		status := CLASSSESSIONSTATUS(strings.ToUpper(filter.Status))
+		if status != CLASS_SESSION_SCHEDULED && status != CLASS_SESSION_CANCELLED {
+			return nil, 0, fmt.Errorf("%w: unknown status %q", ErrInvalidClass, filter.Status)
+		}
+		options.Filters["status"] = map[string]interface{}{"Op": "eq", "value": status}
+	}
+	return s.classRepository.ListSessions(options)
+}
+
+// UpdateSession edits one scheduled session. The session is marked as edited
+// so later changes to its class leave it alone. Its capacity cannot drop below
+// the customers already booked; spots it gains go to the waitlist.
+func (s *ClassService) UpdateSession(ctx context.Context, requester Requester, crewID, sessionID uint, input dtos.UpdateClassSessionRequest) (*models.ClassSession, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.findSession(crewID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != CLASS_SESSION_SCHEDULED {
+		return nil, ErrSessionNotScheduled
+	}
+
+	location := crew.Location()
+	localStart := session.StartsAt.In(location)
+	date := localStart.Format(time.DateOnly)
+	if input.Date != nil {
+		date = strings.TrimSpace(*input.Date)
+	}
+	startTime := localStart.Format(models.ClockLayout)
+	if input.StartTime != nil {
+		startTime = strings.TrimSpace(*input.StartTime)
+	}
+	startsAt, err := time.ParseInLocation(time.DateOnly+" "+models.ClockLayout, date+" "+startTime, location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD and start_time HH:MM", ErrInvalidClass)
+	}
+	duration := session.EndsAt.Sub(session.StartsAt)
+	if input.DurationMinutes != nil {
+		duration = time.Duration(*input.DurationMinutes) * time.Minute
+	}
+
+	if input.TrainerID != nil && *input.TrainerID != session.TrainerID {
+		trainer, err := s.crewTrainer(crew, *input.TrainerID)
+		if err != nil {
+			return nil, err
+		}
+		session.TrainerID = trainer.ID
+		session.Trainer = *trainer
+	}
+	if input.Room != nil {
+		session.Room = strings.TrimSpace(*input.Room)
+	}
+	if input.Capacity != nil {
+		session.Capacity = *input.Capacity
+	}
+	session.StartsAt = startsAt
+	session.EndsAt = startsAt.Add(duration)
+
+	session.Modified = true
+	session.UpdatedBy = int(requester.UserID)
+	outcome, promoted, err := s.bookingRepository.SaveSession(session, time.Now(), func(existing []models.ClassSession) error {
+		for _, other := range existing {
+			if other.ID != session.ID {
+				return doubleBooked(&other, location)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if outcome == repository.BookingOverCapacity {
+		return nil, fmt.Errorf("%w: capacity cannot be below the customers already booked", ErrInvalidClass)
+	}
+	notifyPromoted(ctx, s.smsSender, crew, session, promoted)
+	return session, nil
+}
+
+// CancelSession cancels one scheduled session and its bookings, and texts the
+// booked and waitlisted customers
+func (s *ClassService) CancelSession(ctx context.Context, requester Requester, crewID, sessionID uint, input dtos.CancelClassSessionRequest) (*models.ClassSession, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.findSession(crewID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != CLASS_SESSION_SCHEDULED {
+		return nil, ErrSessionNotScheduled
+	}
+
+	session.Status = CLASS_SESSION_CANCELLED
+	session.CancelReason = strings.TrimSpace(input.Reason)
+	session.Modified = true
+	session.UpdatedBy = int(requester.UserID)
+	cancelled, err := s.bookingRepository.CancelSession(session, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	notifyCancelled(ctx, s.smsSender, crew, session, cancelled)
+	return session, nil
+}
+
+// cancelGeneratedSessions cancels booked sessions a template change removes
+// and texts their customers. The sessions give up their slot, so the template
+// can generate it again, and are marked as edited so later changes leave them
+// alone.
+func (s *ClassService) cancelGeneratedSessions(ctx context.Context, crew *models.FitCrew, sessions []models.ClassSession, reason string, updatedBy uint) error {
+	for i := range sessions {
+		session := &sessions[i]
+		session.Status = CLASS_SESSION_CANCELLED
+		session.CancelReason = reason
+		session.OccursAt = nil
+		session.Modified = true
+		session.UpdatedBy = int(updatedBy)
+		cancelled, err := s.bookingRepository.CancelSession(session, time.Now())
+		if err != nil {
+			return err
+		}
+		notifyCancelled(ctx, s.smsSender, crew, session, cancelled)
+	}
+	return nil
+}
+
+func (s *ClassService) findTemplate(crewID, templateID uint) (*models.ClassTemplate, error) {
+	template, err := s.classRepository.FindTemplateByID(templateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrClassNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(template.CrewID) != crewID {
+		return nil, ErrClassNotFound
+	}
+	return template, nil
+}
+
+func (s *ClassService) findSession(crewID, sessionID uint) (*models.ClassSession, error) {
+	session, err := s.classRepository.FindSessionByID(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(session.CrewID) != crewID {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// prepareTemplate validates the class input and sets it on the template with
+// its service and trainer
+func (s *ClassService) prepareTemplate(crew *models.FitCrew, template *models.ClassTemplate, input dtos.CreateClassTemplateRequest) error {
+	template.Name = strings.TrimSpace(input.Name)
+	template.Description = input.Description
+	template.StartTime = strings.TrimSpace(input.StartTime)
+	template.DurationMinutes = input.DurationMinutes
+	template.Room = strings.TrimSpace(input.Room)
+	template.Capacity = input.Capacity
+	if template.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidClass)
+	}
+	if !validClock(template.StartTime) {
+		return fmt.Errorf("%w: start_time must be an HH:MM time", ErrInvalidClass)
+	}
+	if template.DurationMinutes < 5 || template.DurationMinutes > 480 {
+		return fmt.Errorf("%w: duration must be between 5 and 480 minutes", ErrInvalidClass)
+	}
+	if template.Capacity < 1 {
+		return fmt.Errorf("%w: capacity must be at least 1", ErrInvalidClass)
+	}
+
+	days := make([]WEEKDAY, 0, len(input.Weekdays))
+	seen := make(map[WEEKDAY]bool, len(input.Weekdays))
+	for _, name := range input.Weekdays {
+		day := WEEKDAY(strings.ToUpper(strings.TrimSpace(name)))
+		if !day.IsValid() {
+			return fmt.Errorf("%w: unknown weekday %q", ErrInvalidClass, name)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("%w: at least one weekday is required", ErrInvalidClass)
+	}
+	template.SetDays(days)
+
+	startsOn, err := parseCrewDate(crew, "starts_on", input.StartsOn)
+	if err != nil {
+		return err
+	}
+	template.StartsOn = startsOn
+	template.EndsOn = nil
+	if input.EndsOn != "" {
+		endsOn, err := parseCrewDate(crew, "ends_on", input.EndsOn)
+		if err != nil {
+			return err
+		}
+		if endsOn.Before(startsOn) {
+			return fmt.Errorf("%w: ends_on cannot be before starts_on", ErrInvalidClass)
+		}
+		template.EndsOn = &endsOn
+	}
+
+	service, err := s.serviceRepository.FindByID(input.ServiceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !service.IsActive) {
+		return fmt.Errorf("%w: service not found", ErrInvalidClass)
+	}
+	if err != nil {
+		return err
+	}
+	template.ServiceID = service.ID
+	template.FitService = *service
+
+	trainer, err := s.crewTrainer(crew, input.TrainerID)
+	if err != nil {
+		return err
+	}
+	template.TrainerID = trainer.ID
+	template.Trainer = *trainer
+	return nil
+}
+
+// crewTrainer returns a visible trainer of the crew
+func (s *ClassService) crewTrainer(crew *models.FitCrew, trainerID uint) (*models.TrainerProfile, error) {
+	trainer, err := s.trainerRepository.FindByID(trainerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: trainer not found", ErrInvalidClass)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(trainer.CrewID) != crew.ID || !trainer.IsVisible() {
+		return nil, fmt.Errorf("%w: trainer not found", ErrInvalidClass)
+	}
+	return trainer, nil
+}
+
+// trainerCheck rejects new sessions that overlap a scheduled session of the
+// trainer. Sessions of the template in the same slot are not new. With
+// replaceFrom set, the template's unedited sessions from then on are about to
+// be replaced and are ignored.
+func trainerCheck(crew *models.FitCrew, sessions []models.ClassSession, templateID uint, replaceFrom *time.Time) repository.TrainerCheck {
+	return func(existing []models.ClassSession) error {
+		for _, other := range existing {
+			ownTemplate := other.TemplateID != nil && *other.TemplateID == templateID
+			if ownTemplate && replaceFrom != nil && !other.Modified && !other.StartsAt.Before(*replaceFrom) {
+				continue
+			}
+			for _, session := range sessions {
+				if ownTemplate && other.OccursAt != nil && other.OccursAt.Equal(*session.OccursAt) {
+					continue
+				}
+				if other.OverlapsWith(session.StartsAt, session.EndsAt) {
+					return doubleBooked(&other, crew.Location())
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// generateSessions builds the sessions of an active template from..until
+func generateSessions(crew *models.FitCrew, template *models.ClassTemplate, from, until time.Time, createdBy uint) []models.ClassSession {
+	if !template.IsActive {
+		return nil
+	}
+	duration := time.Duration(template.DurationMinutes) * time.Minute
+	starts := template.Occurrences(from, until, crew.Location())
+
+	sessions := make([]models.ClassSession, 0, len(starts))
+	for _, start := range starts {
+		slot := start
+		sessions = append(sessions, models.ClassSession{
+			OccursAt:  &slot,
+			CrewID:    template.CrewID,
+			ServiceID: template.ServiceID,
+			TrainerID: template.TrainerID,
+			Name:      template.Name,
+			Room:      template.Room,
+			Capacity:  template.Capacity,
+			StartsAt:  start,
+			EndsAt:    start.Add(duration),
+			Status:    CLASS_SESSION_SCHEDULED,
+			UpdatedBy: int(createdBy),
+		})
+	}
+	return sessions
+}
+
+// sameSchedule reports whether the sessions generated for a template include
+// the session's slot unchanged, so its bookings still hold
+func sameSchedule(session *models.ClassSession, generated []models.ClassSession) bool {
+	for i := range generated {
+		next := &generated[i]
+		if session.OccursAt == nil || !next.OccursAt.Equal(*session.OccursAt) {
+			continue
+		}
+		return next.StartsAt.Equal(session.StartsAt) && next.EndsAt.Equal(session.EndsAt) &&
+			next.TrainerID == session.TrainerID && next.ServiceID == session.ServiceID &&
+			next.Name == session.Name && next.Room == session.Room && next.Capacity == session.Capacity
+	}
+	return false
+}
+
+// notifyCancelled texts the customers whose bookings a session's cancellation cancelled
+func notifyCancelled(ctx context.Context, sender notifications.SMSSender, crew *models.FitCrew, session *models.ClassSession, cancelled []models.ClassBooking) {
+	notifyBookings(ctx, sender, cancelled, fmt.Sprintf("%s on %s at %s has been cancelled.",
+		session.Name, session.StartsAt.In(crew.Location()).Format(bookingTimeLayout), crew.GymName))
+}
+
+func doubleBooked(other *models.ClassSession, location *time.Location) error {
+	return fmt.Errorf("%w: %s at %s", ErrTrainerDoubleBooked, other.Name, other.StartsAt.In(location).Format(time.DateOnly+" "+models.ClockLayout))
+}
+
+// parseCrewDate parses a YYYY-MM-DD date as midnight on the crew's clock
+func parseCrewDate(crew *models.FitCrew, field, value string) (time.Time, error) {
+	date, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(value), crew.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a YYYY-MM-DD date", ErrInvalidClass, field)
+	}
+	return date, nil
+}
+
+func calendarDayIn(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
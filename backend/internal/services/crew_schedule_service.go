@@ -0,0 +1,196 @@
+// internal/services/crew_schedule_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSchedule = errors.New(INVALID_SCHEDULE_INPUT)
+	ErrHolidayNotFound = errors.New(HOLIDAY_NOT_FOUND)
+)
+
+// CrewScheduleService manages the weekly opening hours and holidays of crews
+// and answers whether a crew is open
+type CrewScheduleService struct {
+	scheduleRepository repository.CrewScheduleRepositoryInterface
+	fitCrewService     *FitCrewService
+}
+
+func NewCrewScheduleService(scheduleRepository repository.CrewScheduleRepositoryInterface, fitCrewService *FitCrewService) *CrewScheduleService {
+	return &CrewScheduleService{
+		scheduleRepository: scheduleRepository,
+		fitCrewService:     fitCrewService,
+	}
+}
+
+// GetSchedule returns the crew with its weekly hours and the holidays that
+// have not ended yet
+func (s *CrewScheduleService) GetSchedule(crewID uint) (*models.FitCrew, error) {
+	return s.findCrew(crewID, time.Now())
+}
+
+// IsOpen reports whether the crew is open at t on its own clock
+func (s *CrewScheduleService) IsOpen(crewID uint, t time.Time) (bool, error) {
+	crew, err := s.findCrew(crewID, t)
+	if err != nil {
+		return false, err
+	}
+	return crew.OpenAt(t), nil
+}
+
+// SetOpeningHours replaces the weekly hours of the crew. Intervals of a day
+// may not overlap; a day without intervals is closed.
+func (s *CrewScheduleService) SetOpeningHours(ctx context.Context, requester Requester, crewID uint, input dtos.SetOpeningHoursRequest) (*models.FitCrew, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+
+	hours := make([]models.CrewOpeningHour, 0, len(input.Hours))
+	for _, interval := range input.Hours {
+		hour := models.CrewOpeningHour{
+			CrewID:   int(crewID),
+			Weekday:  WEEKDAY(strings.ToUpper(strings.TrimSpace(interval.Weekday))),
+			OpensAt:  strings.TrimSpace(interval.OpensAt),
+			ClosesAt: strings.TrimSpace(interval.ClosesAt),
+		}
+		if !hour.Weekday.IsValid() {
+			return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidSchedule, interval.Weekday)
+		}
+		if err := validateInterval(hour.OpensAt, hour.ClosesAt); err != nil {
+			return nil, err
+		}
+		hours = append(hours, hour)
+	}
+
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].Weekday != hours[j].Weekday {
+			return hours[i].Weekday < hours[j].Weekday
+		}
+		return hours[i].OpensAt < hours[j].OpensAt
+	})
+	for i := 1; i < len(hours); i++ {
+		if hours[i].Weekday == hours[i-1].Weekday && hours[i].OpensAt < hours[i-1].ClosesAt {
+			return nil, fmt.Errorf("%w: intervals on %s overlap", ErrInvalidSchedule, hours[i].Weekday)
+		}
+	}
+
+	if err := s.scheduleRepository.ReplaceOpeningHours(crewID, hours); err != nil {
+		return nil, err
+	}
+	return s.GetSchedule(crewID)
+}
+
+// AddHoliday closes the crew, or gives it special hours, over a range of dates
+func (s *CrewScheduleService) AddHoliday(ctx context.Context, requester Requester, crewID uint, input dtos.CreateCrewHolidayRequest) (*models.CrewHoliday, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+
+	holiday := &models.CrewHoliday{
+		CrewID: int(crewID),
+		Name:   strings.TrimSpace(input.Name),
+	}
+	if holiday.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+
+	fromDate, err := parseScheduleDate("from_date", input.FromDate)
+	if err != nil {
+		return nil, err
+	}
+	toDate := fromDate
+	if input.ToDate != "" {
+		if toDate, err = parseScheduleDate("to_date", input.ToDate); err != nil {
+			return nil, err
+		}
+	}
+	if toDate.Before(fromDate) {
+		return nil, fmt.Errorf("%w: to_date cannot be before from_date", ErrInvalidSchedule)
+	}
+	holiday.FromDate, holiday.ToDate = fromDate, toDate
+
+	if (input.OpensAt == nil) != (input.ClosesAt == nil) {
+		return nil, fmt.Errorf("%w: opens_at and closes_at must be given together", ErrInvalidSchedule)
+	}
+	if input.OpensAt != nil {
+		opensAt, closesAt := strings.TrimSpace(*input.OpensAt), strings.TrimSpace(*input.ClosesAt)
+		if err := validateInterval(opensAt, closesAt); err != nil {
+			return nil, err
+		}
+		holiday.OpensAt, holiday.ClosesAt = &opensAt, &closesAt
+	}
+
+	if err := s.scheduleRepository.CreateHoliday(holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *CrewScheduleService) RemoveHoliday(ctx context.Context, requester Requester, crewID, holidayID uint) error {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return err
+	}
+
+	holiday, err := s.scheduleRepository.FindHolidayByID(holidayID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrHolidayNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if uint(holiday.CrewID) != crewID {
+		return ErrHolidayNotFound
+	}
+	return s.scheduleRepository.DeleteHoliday(holidayID)
+}
+
+// findCrew loads the crew's schedule with the holidays that may still cover t.
+// Holidays ending the day before t are kept, as t may fall on an earlier date
+// on the crew's clock.
+func (s *CrewScheduleService) findCrew(crewID uint, t time.Time) (*models.FitCrew, error) {
+	crew, err := s.scheduleRepository.FindCrewWithSchedule(crewID, t.AddDate(0, 0, -1))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCrewNotFound
+	}
+	return crew, err
+}
+
+// validateInterval requires HH:MM times with the opening before the closing;
+// only the closing may be 24:00
+func validateInterval(opensAt, closesAt string) error {
+	if !validClock(opensAt) {
+		return fmt.Errorf("%w: opens_at must be an HH:MM time", ErrInvalidSchedule)
+	}
+	if closesAt != models.EndOfDay && !validClock(closesAt) {
+		return fmt.Errorf("%w: closes_at must be an HH:MM time or %s", ErrInvalidSchedule, models.EndOfDay)
+	}
+	if opensAt >= closesAt {
+		return fmt.Errorf("%w: opens_at must be before closes_at", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+func validClock(value string) bool {
+	_, err := time.Parse(models.ClockLayout, value)
+	return err == nil && len(value) == len(models.ClockLayout)
+}
+
+func parseScheduleDate(field, value string) (time.Time, error) {
+	date, err := time.ParseInLocation(membershipDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a YYYY-MM-DD date", ErrInvalidSchedule, field)
+	}
+	return date, nil
+}
@@ -0,0 +1,263 @@
+// internal/services/fit_allie_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Commission is a whole percentage of what the allie earns through the platform
+const (
+	MinCommissionRate = 0
+	MaxCommissionRate = 100
+)
+
+var (
+	ErrAllieNotFound         = errors.New(ALLIE_NOT_FOUND)
+	ErrAllieAccessDenied     = errors.New(ALLIE_ACCESS_DENIED)
+	ErrInvalidAllie          = errors.New(INVALID_ALLIE_INPUT)
+	ErrUserAlreadyRegistered = errors.New(USER_ALREADY_REGISTERED)
+)
+
+// Requester identifies the signed-in user a service call is made for
+type Requester struct {
+	UserID uint
+	Role   USERROLE
+}
+
+// CanAccessAllie reports whether the requester may manage the allie. Roles that
+// can read every allie manage all of them; anyone else only the allie linked to
+// their own user.
+func (r Requester) CanAccessAllie(allie *models.FitAllie) bool {
+	if r.Role.HasPermission(PERM_ALLIE_READ) {
+		return true
+	}
+	return allie.UserID != 0 && uint(allie.UserID) == r.UserID
+}
+
+type FitAllieService struct {
+	allieRepository repository.FitAllieRepositoryInterface
+	userRepository  repository.UserRepositoryInterface
+}
+
+func NewFitAllieService(allieRepository repository.FitAllieRepositoryInterface, userRepository repository.UserRepositoryInterface) *FitAllieService {
+	return &FitAllieService{
+		allieRepository: allieRepository,
+		userRepository:  userRepository,
+	}
+}
+
+// CreateAllie onboards a gym partner. When an account is given, the allie's GYM
+// user is created in the same transaction and must change its password at
+// first login.
+func (s *FitAllieService) CreateAllie(ctx context.Context, input dtos.CreateFitAllieRequest, createdBy uint) (*models.FitAllie, error) {
+	allie := &models.FitAllie{
+		BusinessName:      strings.TrimSpace(input.BusinessName),
+		OwnerFirstName:    strings.TrimSpace(input.OwnerFirstName),
+		OwnerMiddleName:   input.OwnerMiddleName,
+		OwnerLastName:     input.OwnerLastName,
+		CoOwnerFirstName:  input.CoOwnerFirstName,
+		CoOwnerMiddleName: input.CoOwnerMiddleName,
+		CoOwnerLastName:   input.CoOwnerLastName,
+		Email:             strings.TrimSpace(input.Email),
+		Mobile:            strings.TrimSpace(input.Mobile),
+		AlternateMobile:   input.AlternateMobile,
+		CommissionRate:    input.CommissionRate,
+		NoOfBranch:        input.NoOfBranch,
+		IsActive:          true,
+		Address:           input.Address,
+		City:              input.City,
+		State:             input.State,
+		PinCode:           input.PinCode,
+		GSTIN:             strings.ToUpper(strings.TrimSpace(input.GSTIN)),
+		CreatedBy:         int(createdBy),
+		UpdatedBy:         int(createdBy),
+	}
+	if allie.NoOfBranch == 0 {
+		allie.NoOfBranch = 1
+	}
+	if err := validateAllie(allie); err != nil {
+		return nil, err
+	}
+
+	if input.Account == nil {
+		if err := s.allieRepository.Create(allie); err != nil {
+			return nil, err
+		}
+		return allie, nil
+	}
+
+	owner, err := s.newOwnerUser(allie, input.Account, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allieRepository.CreateWithOwner(allie, owner); err != nil {
+		return nil, err
+	}
+
+	logging.Log.Info("Allie onboarded with owner account",
+		zap.Uint("allie_id", allie.ID),
+		zap.Uint("user_id", owner.ID),
+		zap.Uint("created_by", createdBy),
+	)
+	return allie, nil
+}
+
+func (s *FitAllieService) GetAllie(ctx context.Context, id uint) (*models.FitAllie, error) {
+	allie, err := s.allieRepository.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAllieNotFound
+	}
+	return allie, err
+}
+
+// AuthorizeAllie returns the allie when the requester may manage it
+func (s *FitAllieService) AuthorizeAllie(ctx context.Context, requester Requester, id uint) (*models.FitAllie, error) {
+	allie, err := s.GetAllie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanAccessAllie(allie) {
+		return nil, ErrAllieAccessDenied
+	}
+	return allie, nil
+}
+
+func (s *FitAllieService) UpdateAllie(ctx context.Context, id uint, input dtos.UpdateFitAllieRequest, updatedBy uint) (*models.FitAllie, error) {
+	allie, err := s.GetAllie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allie.BusinessName = strings.TrimSpace(input.BusinessName)
+	allie.OwnerFirstName = strings.TrimSpace(input.OwnerFirstName)
+	allie.OwnerMiddleName = input.OwnerMiddleName
+	allie.OwnerLastName = input.OwnerLastName
+	allie.CoOwnerFirstName = input.CoOwnerFirstName
+	allie.CoOwnerMiddleName = input.CoOwnerMiddleName
+	allie.CoOwnerLastName = input.CoOwnerLastName
+	allie.Email = strings.TrimSpace(input.Email)
+	allie.Mobile = strings.TrimSpace(input.Mobile)
+	allie.AlternateMobile = input.AlternateMobile
+	allie.CommissionRate = input.CommissionRate
+	if input.NoOfBranch != 0 {
+		allie.NoOfBranch = input.NoOfBranch
+	}
+	allie.Address = input.Address
+	allie.City = input.City
+	allie.State = input.State
+	allie.PinCode = input.PinCode
+	allie.GSTIN = strings.ToUpper(strings.TrimSpace(input.GSTIN))
+	if input.IsActive != nil {
+		allie.IsActive = *input.IsActive
+	}
+	allie.UpdatedBy = int(updatedBy)
+
+	if err := validateAllie(allie); err != nil {
+		return nil, err
+	}
+	if err := s.allieRepository.Update(allie); err != nil {
+		return nil, err
+	}
+	return allie, nil
+}
+
+// DeleteAllie removes an allie and deactivates its crews and owner account
+func (s *FitAllieService) DeleteAllie(ctx context.Context, id uint) error {
+	if _, err := s.GetAllie(ctx, id); err != nil {
+		return err
+	}
+	return s.allieRepository.Delete(id)
+}
+
+// ListAllies returns a page of allies matching the filter and the total match count
+func (s *FitAllieService) ListAllies(ctx context.Context, filter dtos.FitAllieFilter) ([]models.FitAllie, int64, error) {
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if filter.City != "" {
+		filters["city"] = map[string]interface{}{"Op": "eq", "value": filter.City}
+	}
+	if filter.State != "" {
+		filters["state"] = map[string]interface{}{"Op": "eq", "value": filter.State}
+	}
+	if filter.IsActive != nil {
+		filters["is_active"] = map[string]interface{}{"Op": "eq", "value": *filter.IsActive}
+	}
+
+	return s.allieRepository.List(filters, strings.TrimSpace(filter.Search))
+}
+
+// newOwnerUser builds the GYM user for an allie, refusing logins already in use
+func (s *FitAllieService) newOwnerUser(allie *models.FitAllie, account *dtos.FitAllieAccountRequest, createdBy uint) (*models.User, error) {
+	if allie.OwnerLastName == "" {
+		return nil, fmt.Errorf("%w: owner last name is required to create the owner account", ErrInvalidAllie)
+	}
+	for _, identifier := range []string{account.Username, allie.Email, allie.Mobile} {
+		_, err := s.userRepository.FindByLogin(identifier)
+		if err == nil {
+			return nil, ErrUserAlreadyRegistered
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(account.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	return &models.User{
+		FirstName:          allie.OwnerFirstName,
+		MiddleName:         allie.OwnerMiddleName,
+		LastName:           allie.OwnerLastName,
+		Email:              allie.Email,
+		Mobile:             allie.Mobile,
+		Username:           account.Username,
+		PasswordHash:       string(passwordHash),
+		UserType:           GYM,
+		IsActive:           true,
+		MustChangePassword: true,
+		CreatedBy:          int(createdBy),
+		UpdatedBy:          int(createdBy),
+	}, nil
+}
+
+func validateAllie(allie *models.FitAllie) error {
+	if allie.BusinessName == "" {
+		return fmt.Errorf("%w: business name is required", ErrInvalidAllie)
+	}
+	if allie.OwnerFirstName == "" {
+		return fmt.Errorf("%w: owner first name is required", ErrInvalidAllie)
+	}
+	if allie.Email == "" {
+		return fmt.Errorf("%w: email is required", ErrInvalidAllie)
+	}
+	if allie.Mobile == "" {
+		return fmt.Errorf("%w: mobile is required", ErrInvalidAllie)
+	}
+	if allie.CommissionRate < MinCommissionRate || allie.CommissionRate > MaxCommissionRate {
+		return fmt.Errorf("%w: commission rate must be between %d and %d", ErrInvalidAllie, MinCommissionRate, MaxCommissionRate)
+	}
+	if allie.NoOfBranch < 1 {
+		return fmt.Errorf("%w: number of branches must be at least 1", ErrInvalidAllie)
+	}
+	if allie.GSTIN != "" && !invoicing.ValidGSTIN(allie.GSTIN) {
+		return fmt.Errorf("%w: gstin is not a valid GST identification number", ErrInvalidAllie)
+	}
+	return nil
+}
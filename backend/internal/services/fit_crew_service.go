@@ -0,0 +1,268 @@
+// internal/services/fit_crew_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DefaultCrewTimezone is used for crews created without a timezone
+const DefaultCrewTimezone = "Asia/Kolkata"
+
+var (
+	ErrCrewNotFound       = errors.New(CREW_NOT_FOUND)
+	ErrInvalidCrew        = errors.New(INVALID_CREW_INPUT)
+	ErrBranchLimitReached = errors.New(BRANCH_LIMIT_REACHED)
+)
+
+type FitCrewService struct {
+	crewRepository  repository.FitCrewRepositoryInterface
+	fitAllieService *FitAllieService
+}
+
+func NewFitCrewService(crewRepository repository.FitCrewRepositoryInterface, fitAllieService *FitAllieService) *FitCrewService {
+	return &FitCrewService{
+		crewRepository:  crewRepository,
+		fitAllieService: fitAllieService,
+	}
+}
+
+// CreateCrew opens a branch for the allie, within the allie's NoOfBranch limit
+func (s *FitCrewService) CreateCrew(ctx context.Context, requester Requester, allieID uint, input dtos.CreateFitCrewRequest) (*models.FitCrew, error) {
+	allie, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID)
+	if err != nil {
+		return nil, err
+	}
+
+	crew := &models.FitCrew{
+		AllieID:           int(allie.ID),
+		GymName:           strings.TrimSpace(input.GymName),
+		ManagerFirstName:  strings.TrimSpace(input.ManagerFirstName),
+		ManagerMiddleName: input.ManagerMiddleName,
+		ManagerLastName:   input.ManagerLastName,
+		Email:             strings.TrimSpace(input.Email),
+		Mobile:            strings.TrimSpace(input.Mobile),
+		AlternateMobile:   input.AlternateMobile,
+		IsActive:          true,
+		Address:           input.Address,
+		City:              input.City,
+		State:             input.State,
+		PinCode:           input.PinCode,
+		Capacity:          input.Capacity,
+		Timezone:          strings.TrimSpace(input.Timezone),
+		CreatedBy:         int(requester.UserID),
+		UpdatedBy:         int(requester.UserID),
+	}
+	if crew.Lat, crew.Long, err = parseCoordinates(input.Lat, input.Long); err != nil {
+		return nil, err
+	}
+	if err := validateCrew(crew); err != nil {
+		return nil, err
+	}
+
+	created, err := s.crewRepository.CreateWithinLimit(crew, allie.NoOfBranch)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrBranchLimitReached
+	}
+	return crew, nil
+}
+
+// GetCrew returns a crew of the allie when the requester may manage the allie
+func (s *FitCrewService) GetCrew(ctx context.Context, requester Requester, allieID, crewID uint) (*models.FitCrew, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+
+	crew, err := s.crewRepository.FindByID(crewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCrewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(crew.AllieID) != allieID {
+		return nil, ErrCrewNotFound
+	}
+	return crew, nil
+}
+
+// AuthorizeCrew returns the crew when the requester may manage its allie
+func (s *FitCrewService) AuthorizeCrew(ctx context.Context, requester Requester, crewID uint) (*models.FitCrew, error) {
+	crew, err := s.crewRepository.FindByID(crewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCrewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, uint(crew.AllieID)); err != nil {
+		return nil, err
+	}
+	return crew, nil
+}
+
+func (s *FitCrewService) ListCrews(ctx context.Context, requester Requester, allieID uint) ([]models.FitCrew, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	return s.crewRepository.ListByAllie(allieID)
+}
+
+// SearchCrews returns a page of the gyms open to customers, optionally only
+// those where a service is offered
+func (s *FitCrewService) SearchCrews(filter dtos.FitCrewSearchFilter) ([]models.FitCrew, int64, error) {
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if city := strings.TrimSpace(filter.City); city != "" {
+		filters["city"] = map[string]interface{}{"Op": "eq", "value": city}
+	}
+	if state := strings.TrimSpace(filter.State); state != "" {
+		filters["state"] = map[string]interface{}{"Op": "eq", "value": state}
+	}
+	return s.crewRepository.Search(filters, strings.TrimSpace(filter.Search), filter.ServiceID)
+}
+
+// NearbyCrews returns a page of the gyms open to customers within the radius of
+// the point, nearest first, optionally only those where a service is offered,
+// that are open now or that have room left
+func (s *FitCrewService) NearbyCrews(filter dtos.NearbyFitCrewFilter) ([]models.FitCrewDistance, int64, error) {
+	options := repository.NearbyOptions{
+		Filters: map[string]interface{}{
+			"offset": filter.Page,
+			"limit":  filter.Limit,
+		},
+		Lat:       *filter.Lat,
+		Lng:       *filter.Lng,
+		RadiusKm:  filter.Radius,
+		ServiceID: filter.ServiceID,
+	}
+	if filter.OpenNow {
+		now := time.Now()
+		options.OpenAt = &now
+	}
+	if filter.HasSpace {
+		since := time.Now().Add(-models.MaxVisitDuration)
+		options.VisitsSince = &since
+	}
+	return s.crewRepository.Nearby(options)
+}
+
+// UpdateCrew changes a crew. Deactivating it hides its trainers, and
+// reactivating it shows them again.
+func (s *FitCrewService) UpdateCrew(ctx context.Context, requester Requester, allieID, crewID uint, input dtos.UpdateFitCrewRequest) (*models.FitCrew, error) {
+	crew, err := s.GetCrew(ctx, requester, allieID, crewID)
+	if err != nil {
+		return nil, err
+	}
+	wasActive := crew.IsActive
+
+	crew.GymName = strings.TrimSpace(input.GymName)
+	crew.ManagerFirstName = strings.TrimSpace(input.ManagerFirstName)
+	crew.ManagerMiddleName = input.ManagerMiddleName
+	crew.ManagerLastName = input.ManagerLastName
+	crew.Email = strings.TrimSpace(input.Email)
+	crew.Mobile = strings.TrimSpace(input.Mobile)
+	crew.AlternateMobile = input.AlternateMobile
+	crew.Address = input.Address
+	crew.City = input.City
+	crew.State = input.State
+	crew.PinCode = input.PinCode
+	if crew.Lat, crew.Long, err = parseCoordinates(input.Lat, input.Long); err != nil {
+		return nil, err
+	}
+	crew.Capacity = input.Capacity
+	if timezone := strings.TrimSpace(input.Timezone); timezone != "" {
+		crew.Timezone = timezone
+	}
+	if input.IsActive != nil {
+		crew.IsActive = *input.IsActive
+	}
+	crew.UpdatedBy = int(requester.UserID)
+
+	if err := validateCrew(crew); err != nil {
+		return nil, err
+	}
+	if err := s.crewRepository.Update(crew); err != nil {
+		return nil, err
+	}
+
+	if wasActive != crew.IsActive {
+		logging.Log.Info("Crew activation changed",
+			zap.Uint("crew_id", crew.ID),
+			zap.Bool("is_active", crew.IsActive),
+			zap.Uint("updated_by", requester.UserID),
+		)
+	}
+	return crew, nil
+}
+
+func (s *FitCrewService) DeleteCrew(ctx context.Context, requester Requester, allieID, crewID uint) error {
+	crew, err := s.GetCrew(ctx, requester, allieID, crewID)
+	if err != nil {
+		return err
+	}
+	return s.crewRepository.Delete(crew)
+}
+
+func validateCrew(crew *models.FitCrew) error {
+	if crew.GymName == "" {
+		return fmt.Errorf("%w: gym name is required", ErrInvalidCrew)
+	}
+	if crew.ManagerFirstName == "" {
+		return fmt.Errorf("%w: manager first name is required", ErrInvalidCrew)
+	}
+	if crew.Email == "" {
+		return fmt.Errorf("%w: email is required", ErrInvalidCrew)
+	}
+	if crew.Mobile == "" {
+		return fmt.Errorf("%w: mobile is required", ErrInvalidCrew)
+	}
+	if crew.Capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidCrew)
+	}
+	if crew.Timezone == "" {
+		crew.Timezone = DefaultCrewTimezone
+	}
+	if _, err := time.LoadLocation(crew.Timezone); err != nil || crew.Timezone == "Local" {
+		return fmt.Errorf("%w: timezone must be an IANA zone such as %s", ErrInvalidCrew, DefaultCrewTimezone)
+	}
+	return nil
+}
+
+// parseCoordinates parses the decimal degrees of a crew's location. Both must be
+// given, or neither for a crew without a location.
+func parseCoordinates(lat, long string) (*float64, *float64, error) {
+	lat, long = strings.TrimSpace(lat), strings.TrimSpace(long)
+	if lat == "" && long == "" {
+		return nil, nil, nil
+	}
+	if lat == "" || long == "" {
+		return nil, nil, fmt.Errorf("%w: lat and long must be given together", ErrInvalidCrew)
+	}
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, nil, fmt.Errorf("%w: lat must be between -90 and 90", ErrInvalidCrew)
+	}
+	longitude, err := strconv.ParseFloat(long, 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, nil, fmt.Errorf("%w: long must be between -180 and 180", ErrInvalidCrew)
+	}
+	return &latitude, &longitude, nil
+}
@@ -0,0 +1,261 @@
+// internal/services/fit_service_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrServiceNotFound  = errors.New(SERVICE_NOT_FOUND)
+	ErrServiceNameTaken = errors.New(SERVICE_NAME_TAKEN)
+	ErrInvalidService   = errors.New(INVALID_SERVICE_INPUT)
+	ErrOfferingNotFound = errors.New(OFFERING_NOT_FOUND)
+	ErrOfferingExists   = errors.New(OFFERING_EXISTS)
+)
+
+// FitServiceService manages the global service catalog and the services each
+// allie offers from it
+type FitServiceService struct {
+	serviceRepository repository.FitServiceRepositoryInterface
+	fitAllieService   *FitAllieService
+	fitCrewService    *FitCrewService
+}
+
+func NewFitServiceService(serviceRepository repository.FitServiceRepositoryInterface, fitAllieService *FitAllieService, fitCrewService *FitCrewService) *FitServiceService {
+	return &FitServiceService{
+		serviceRepository: serviceRepository,
+		fitAllieService:   fitAllieService,
+		fitCrewService:    fitCrewService,
+	}
+}
+
+func (s *FitServiceService) CreateService(input dtos.CreateFitServiceRequest) (*models.FitService, error) {
+	service := &models.FitService{
+		Name:        strings.TrimSpace(input.Name),
+		Category:    strings.TrimSpace(input.Category),
+		Icon:        strings.TrimSpace(input.Icon),
+		Description: input.Description,
+		IsActive:    true,
+	}
+	if err := s.checkName(service); err != nil {
+		return nil, err
+	}
+
+	if err := s.serviceRepository.Create(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// GetService returns a catalog service; inactive services are only returned
+// when includeInactive is set
+func (s *FitServiceService) GetService(serviceID uint, includeInactive bool) (*models.FitService, error) {
+	service, err := s.serviceRepository.FindByID(serviceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrServiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !service.IsActive && !includeInactive {
+		return nil, ErrServiceNotFound
+	}
+	return service, nil
+}
+
+func (s *FitServiceService) ListServices(filter dtos.FitServiceFilter, includeInactive bool) ([]models.FitService, error) {
+	return s.serviceRepository.List(strings.TrimSpace(filter.Category), !includeInactive, strings.TrimSpace(filter.Search))
+}
+
+// UpdateService changes a catalog service. Deactivating it hides it from the
+// catalog and from the gym search, but keeps the allies' offerings.
+func (s *FitServiceService) UpdateService(serviceID uint, input dtos.UpdateFitServiceRequest) (*models.FitService, error) {
+	service, err := s.GetService(serviceID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Name = strings.TrimSpace(input.Name)
+	service.Category = strings.TrimSpace(input.Category)
+	service.Icon = strings.TrimSpace(input.Icon)
+	service.Description = input.Description
+	if input.IsActive != nil {
+		service.IsActive = *input.IsActive
+	}
+	if err := s.checkName(service); err != nil {
+		return nil, err
+	}
+
+	if err := s.serviceRepository.Update(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// DeleteService removes a service from the catalog together with its offerings
+func (s *FitServiceService) DeleteService(serviceID uint) error {
+	if _, err := s.GetService(serviceID, true); err != nil {
+		return err
+	}
+	return s.serviceRepository.Delete(serviceID)
+}
+
+// checkName requires a name no other catalog service uses, ignoring case
+func (s *FitServiceService) checkName(service *models.FitService) error {
+	if service.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidService)
+	}
+	existing, err := s.serviceRepository.FindByName(service.Name)
+	if err == nil && existing.ID != service.ID {
+		return ErrServiceNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// AddOffering offers an active catalog service at every crew of the allie, or
+// only at one of its crews when a crew is given. A price makes the service
+// paid per visit; without one it is included in the membership.
+func (s *FitServiceService) AddOffering(ctx context.Context, requester Requester, allieID uint, input dtos.CreateOfferingRequest) (*models.FitAllieService, error) {
+	allie, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkOfferingCrew(ctx, requester, allieID, input.CrewID); err != nil {
+		return nil, err
+	}
+
+	service, err := s.GetService(input.ServiceID, true)
+	if err != nil {
+		return nil, err
+	}
+	if !service.IsActive {
+		return nil, fmt.Errorf("%w: service is not active", ErrInvalidService)
+	}
+
+	_, err = s.serviceRepository.FindOffering(allieID, service.ID, input.CrewID)
+	if err == nil {
+		return nil, ErrOfferingExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	offering := &models.FitAllieService{
+		FitAllieID: int(allie.ID),
+		ServiceID:  int(service.ID),
+		CrewID:     input.CrewID,
+		IsActive:   true,
+	}
+	if err := setOfferingPrice(offering, input.PriceMinor, input.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := s.serviceRepository.CreateOffering(offering); err != nil {
+		return nil, err
+	}
+	return offering, nil
+}
+
+// GetOffering returns an offering of the allie when the requester may manage the allie
+func (s *FitServiceService) GetOffering(ctx context.Context, requester Requester, allieID, offeringID uint) (*models.FitAllieService, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+
+	offering, err := s.serviceRepository.FindOfferingByID(offeringID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOfferingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(offering.FitAllieID) != allieID {
+		return nil, ErrOfferingNotFound
+	}
+	return offering, nil
+}
+
+// ListOfferings returns the allie's offerings; with a crew, only those available at that crew
+func (s *FitServiceService) ListOfferings(ctx context.Context, requester Requester, allieID uint, filter dtos.OfferingFilter) ([]models.FitAllieService, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	return s.serviceRepository.ListOfferings(allieID, filter.CrewID)
+}
+
+func (s *FitServiceService) UpdateOffering(ctx context.Context, requester Requester, allieID, offeringID uint, input dtos.UpdateOfferingRequest) (*models.FitAllieService, error) {
+	offering, err := s.GetOffering(ctx, requester, allieID, offeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setOfferingPrice(offering, input.PriceMinor, input.Currency); err != nil {
+		return nil, err
+	}
+	if input.IsActive != nil {
+		offering.IsActive = *input.IsActive
+	}
+
+	if err := s.serviceRepository.UpdateOffering(offering); err != nil {
+		return nil, err
+	}
+	return offering, nil
+}
+
+func (s *FitServiceService) RemoveOffering(ctx context.Context, requester Requester, allieID, offeringID uint) error {
+	if _, err := s.GetOffering(ctx, requester, allieID, offeringID); err != nil {
+		return err
+	}
+	return s.serviceRepository.DeleteOffering(offeringID)
+}
+
+// checkOfferingCrew requires the crew of a crew-specific offering to belong to the allie
+func (s *FitServiceService) checkOfferingCrew(ctx context.Context, requester Requester, allieID uint, crewID *int) error {
+	if crewID == nil {
+		return nil
+	}
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, uint(*crewID))
+	if errors.Is(err, ErrCrewNotFound) {
+		return fmt.Errorf("%w: crew not found", ErrInvalidService)
+	}
+	if err != nil {
+		return err
+	}
+	if uint(crew.AllieID) != allieID {
+		return fmt.Errorf("%w: crew belongs to another allie", ErrInvalidService)
+	}
+	return nil
+}
+
+// setOfferingPrice sets the per-visit price; a nil price marks the service as
+// included in the membership and clears the currency
+func setOfferingPrice(offering *models.FitAllieService, priceMinor *int64, currency string) error {
+	offering.PriceMinor = priceMinor
+	offering.Currency = ""
+	if priceMinor == nil {
+		return nil
+	}
+	if *priceMinor < 0 {
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidService)
+	}
+	offering.Currency = strings.ToUpper(strings.TrimSpace(currency))
+	if offering.Currency == "" {
+		offering.Currency = DefaultCurrency
+	}
+	if !currencyPattern.MatchString(offering.Currency) {
+		return fmt.Errorf("%w: currency must be a 3-letter ISO code", ErrInvalidService)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// internal/services/impersonation_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound           = errors.New(USER_NOT_FOUND)
+	ErrImpersonationForbidden = errors.New(IMPERSONATION_FORBIDDEN)
+)
+
+// ImpersonationToken is an access-only token letting an administrator act as another user
+type ImpersonationToken struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	Target      *models.User
+}
+
+type ImpersonationService struct {
+	userRepository          repository.UserRepositoryInterface
+	impersonationRepository repository.ImpersonationRepositoryInterface
+	ttl                     time.Duration
+}
+
+func NewImpersonationService(userRepository repository.UserRepositoryInterface, impersonationRepository repository.ImpersonationRepositoryInterface, ttl time.Duration) *ImpersonationService {
+	return &ImpersonationService{
+		userRepository:          userRepository,
+		impersonationRepository: impersonationRepository,
+		ttl:                     ttl,
+	}
+}
+
+// Impersonate records the request and issues a short-lived token for the target
+// carrying the actor. SUPERADMIN accounts, including the actor's own, cannot be
+// impersonated. The token cannot be refreshed.
+func (s *ImpersonationService) Impersonate(ctx context.Context, actorID, targetID uint, reason, clientIP string) (*ImpersonationToken, error) {
+	actor, err := s.userRepository.FindByID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserType != SUPERADMIN {
+		return nil, ErrImpersonationForbidden
+	}
+
+	target, err := s.userRepository.FindByID(targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if target.UserType == SUPERADMIN || target.ID == actor.ID {
+		return nil, ErrImpersonationForbidden
+	}
+	if !target.IsActive || target.IsDeleted {
+		return nil, ErrAccountInactive
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	record := &models.Impersonation{
+		ActorID:   actor.ID,
+		TargetID:  target.ID,
+		Reason:    reason,
+		ClientIP:  clientIP,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.impersonationRepository.Create(record); err != nil {
+		return nil, err
+	}
+
+	// Forced password changes and MFA enrollment are the target's to complete,
+	// so they do not restrict the impersonated session
+	accessToken, _, err := middleware.GenerateTokens(middleware.TokenSubject{
+		UserID:   target.ID,
+		Username: target.Username,
+		Role:     target.UserType,
+		Actor: &middleware.TokenActor{
+			UserID:   actor.ID,
+			Username: actor.Username,
+		},
+		AccessTTL: s.ttl,
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Log.Warn("Impersonation started",
+		zap.Uint("impersonation_id", record.ID),
+		zap.Uint("actor_id", actor.ID),
+		zap.Uint("target_id", target.ID),
+		zap.String("reason", reason),
+		zap.String("client_ip", clientIP),
+		zap.Time("expires_at", expiresAt),
+	)
+
+	return &ImpersonationToken{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+		Target:      target,
+	}, nil
+}
@@ -0,0 +1,358 @@
+// internal/services/invoice_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvoiceNotFound    = errors.New(INVOICE_NOT_FOUND)
+	ErrInvalidInvoice     = errors.New(INVALID_INVOICE_INPUT)
+	ErrInvoiceNotIssuable = errors.New(INVOICE_NOT_ISSUABLE)
+)
+
+// InvoiceService issues the GST tax invoice of a paid order and a credit note
+// for each of its refunds. The allie is the seller: numbers run in series per
+// allie and financial year, and the tax is split into CGST and SGST when the
+// crew and the customer's billing state are the same, IGST otherwise.
+type InvoiceService struct {
+	invoiceRepository  repository.InvoiceRepositoryInterface
+	paymentRepository  repository.PaymentRepositoryInterface
+	customerRepository repository.CustomerRepositoryInterface
+	crewRepository     repository.FitCrewRepositoryInterface
+	fitAllieService    *FitAllieService
+	fitCrewService     *FitCrewService
+}
+
+func NewInvoiceService(
+	invoiceRepository repository.InvoiceRepositoryInterface,
+	paymentRepository repository.PaymentRepositoryInterface,
+	customerRepository repository.CustomerRepositoryInterface,
+	crewRepository repository.FitCrewRepositoryInterface,
+	fitAllieService *FitAllieService,
+	fitCrewService *FitCrewService,
+) *InvoiceService {
+	return &InvoiceService{
+		invoiceRepository:  invoiceRepository,
+		paymentRepository:  paymentRepository,
+		customerRepository: customerRepository,
+		crewRepository:     crewRepository,
+		fitAllieService:    fitAllieService,
+		fitCrewService:     fitCrewService,
+	}
+}
+
+// IssueForOrder issues whatever an order of the crew is missing: its invoice
+// once paid and a credit note for each refund made. Documents issued before
+// are returned as they are.
+func (s *InvoiceService) IssueForOrder(ctx context.Context, requester Requester, crewID, orderID uint) ([]models.Invoice, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	order, err := s.paymentRepository.FindOrderByID(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(order.CrewID) != crewID {
+		return nil, ErrOrderNotFound
+	}
+	return s.issueDocuments(ctx, order, requester.UserID)
+}
+
+// IssueDocuments issues the documents an order is missing after a payment or
+// refund settled. It is called by the payment flow, which has no requester.
+func (s *InvoiceService) IssueDocuments(ctx context.Context, orderID uint) ([]models.Invoice, error) {
+	order, err := s.paymentRepository.FindOrderByID(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issueDocuments(ctx, order, 0)
+}
+
+// GetInvoice returns an invoice or credit note of the crew
+func (s *InvoiceService) GetInvoice(ctx context.Context, requester Requester, crewID, invoiceID uint) (*models.Invoice, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	invoice, err := s.findInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if uint(invoice.CrewID) != crewID {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// ListInvoices returns a page of the crew's invoices and credit notes, latest first
+func (s *InvoiceService) ListInvoices(ctx context.Context, requester Requester, crewID uint, filter dtos.InvoiceFilter) ([]models.Invoice, int64, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, 0, err
+	}
+	filters, err := invoiceFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.invoiceRepository.List(crewID, filters)
+}
+
+// GetOwnInvoice returns one of the signed-in customer's invoices or credit notes
+func (s *InvoiceService) GetOwnInvoice(ctx context.Context, requester Requester, invoiceID uint) (*models.Invoice, error) {
+	invoice, err := s.findInvoice(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.customerRepository.FindByID(invoice.CustomerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if customer.UserID != requester.UserID {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// ListOwnInvoices returns a page of the signed-in customer's invoices and credit notes, latest first
+func (s *InvoiceService) ListOwnInvoices(ctx context.Context, requester Requester, filter dtos.InvoiceFilter) ([]models.Invoice, int64, error) {
+	filters, err := invoiceFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.invoiceRepository.ListForUser(requester.UserID, filters)
+}
+
+// issueDocuments issues the invoice of a paid order and the credit notes of
+// its settled refunds that were not issued yet
+func (s *InvoiceService) issueDocuments(ctx context.Context, order *models.PaymentOrder, by uint) ([]models.Invoice, error) {
+	if order.PaidAt == nil {
+		return nil, fmt.Errorf("%w: the order is not paid", ErrInvoiceNotIssuable)
+	}
+	existing, err := s.invoiceRepository.ListForOrder(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var original *models.Invoice
+	credited := make(map[uint]bool)
+	for i := range existing {
+		switch {
+		case existing[i].Kind == INVOICE_KIND_TAX_INVOICE:
+			original = &existing[i]
+		case existing[i].RefundID != nil:
+			credited[*existing[i].RefundID] = true
+		}
+	}
+
+	now := time.Now()
+	if original == nil {
+		invoice, err := s.taxInvoice(ctx, order, now, by)
+		if err != nil {
+			return nil, err
+		}
+		if original, err = s.issue(invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	var ledger []models.LedgerEntry
+	for i := range order.Refunds {
+		refund := &order.Refunds[i]
+		if refund.Status != REFUND_STATUS_SUCCEEDED || credited[refund.ID] {
+			continue
+		}
+		if ledger == nil {
+			if ledger, err = s.paymentRepository.LedgerForOrder(order.ID); err != nil {
+				return nil, err
+			}
+		}
+		note, err := creditNote(original, refund, ledger, now, by)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.issue(note); err != nil {
+			return nil, err
+		}
+	}
+	return s.invoiceRepository.ListForOrder(order.ID)
+}
+
+func (s *InvoiceService) issue(invoice *models.Invoice) (*models.Invoice, error) {
+	stored, issued, err := s.invoiceRepository.Issue(invoice)
+	if err != nil {
+		return nil, err
+	}
+	if issued {
+		logging.Log.Info("Invoice issued",
+			zap.String("number", stored.Number),
+			zap.String("kind", string(stored.Kind)),
+			zap.Int("allie_id", stored.AllieID),
+			zap.Uint("order_id", stored.OrderID),
+		)
+	}
+	return stored, nil
+}
+
+// taxInvoice builds the invoice of a paid order from the allie, the crew and
+// the customer as they are now. The supply is made from the crew's state, or
+// the allie's when the crew has none; the place of supply is the customer's
+// billing state, or the seller's own when the customer gave none.
+func (s *InvoiceService) taxInvoice(ctx context.Context, order *models.PaymentOrder, now time.Time, by uint) (*models.Invoice, error) {
+	crew, err := s.crewRepository.FindByID(uint(order.CrewID))
+	if err != nil {
+		return nil, err
+	}
+	allie, err := s.fitAllieService.GetAllie(ctx, uint(order.AllieID))
+	if err != nil {
+		return nil, err
+	}
+
+	sellerState, ok := invoicing.StateCode(crew.State)
+	if !ok {
+		sellerState, ok = invoicing.StateCode(allie.State)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: the state of crew %d is not an Indian state or union territory", ErrInvoiceNotIssuable, crew.ID)
+	}
+	placeOfSupply, ok := invoicing.StateCode(order.Customer.State)
+	if !ok {
+		placeOfSupply = sellerState
+	}
+
+	customer := order.Customer
+	split := invoicing.SplitTax(order.TaxMinor, sellerState, placeOfSupply)
+	return &models.Invoice{
+		AllieID:         order.AllieID,
+		Kind:            INVOICE_KIND_TAX_INVOICE,
+		FiscalYear:      invoicing.FiscalYear(now),
+		IssuedAt:        now,
+		CrewID:          order.CrewID,
+		CustomerID:      order.CustomerID,
+		OrderID:         order.ID,
+		SellerName:      allie.BusinessName,
+		SellerAddress:   joinAddress(crew.GymName, crew.Address, crew.City, crew.State, crew.PinCode),
+		SellerGSTIN:     allie.GSTIN,
+		SellerStateCode: sellerState,
+		BuyerName:       joinName(customer.FirstName, customer.MiddleName, customer.LastName),
+		BuyerEmail:      customer.Email,
+		BuyerMobile:     customer.Mobile,
+		PlaceOfSupply:   placeOfSupply,
+		Description:     fmt.Sprintf("%s membership, %d days", order.PlanName, order.DurationDays),
+		SAC:             invoicing.SACFitness,
+		TaxRateBps:      order.TaxRateBps,
+		TaxableMinor:    order.PriceMinor,
+		CGSTMinor:       split.CGSTMinor,
+		SGSTMinor:       split.SGSTMinor,
+		IGSTMinor:       split.IGSTMinor,
+		TotalMinor:      order.AmountMinor,
+		Currency:        order.Currency,
+		CreatedBy:       by,
+	}, nil
+}
+
+// creditNote builds the credit note of a refund against the order's invoice.
+// The refund is split between price and tax as the ledger posted it, and the
+// tax is split the way the invoice charged it.
+func creditNote(original *models.Invoice, refund *models.PaymentRefund, ledger []models.LedgerEntry, now time.Time, by uint) (*models.Invoice, error) {
+	ref := refundTransactionRef(refund)
+	var posted bool
+	var taxable, tax int64
+	for _, entry := range ledger {
+		if entry.TransactionRef != ref {
+			continue
+		}
+		posted = true
+		switch entry.Account {
+		case LEDGER_MEMBERSHIP_REVENUE:
+			taxable += entry.DebitMinor
+		case LEDGER_TAX_PAYABLE:
+			tax += entry.DebitMinor
+		}
+	}
+	if !posted {
+		return nil, fmt.Errorf("%w: refund %s is not in the ledger", ErrInvoiceNotIssuable, refund.Reference)
+	}
+
+	split := invoicing.SplitTax(tax, original.SellerStateCode, original.PlaceOfSupply)
+	note := *original
+	note.BaseModel = models.BaseModel{}
+	note.Kind = INVOICE_KIND_CREDIT_NOTE
+	note.FiscalYear = invoicing.FiscalYear(now)
+	note.Sequence = 0
+	note.Number = ""
+	note.IssuedAt = now
+	note.RefundID = &refund.ID
+	note.OriginalID = &original.ID
+	note.OriginalNumber = original.Number
+	note.Description = "Refund of " + original.Description
+	note.TaxableMinor = taxable
+	note.CGSTMinor = split.CGSTMinor
+	note.SGSTMinor = split.SGSTMinor
+	note.IGSTMinor = split.IGSTMinor
+	note.TotalMinor = refund.AmountMinor
+	note.CreatedBy = by
+	return &note, nil
+}
+
+func (s *InvoiceService) findInvoice(id uint) (*models.Invoice, error) {
+	invoice, err := s.invoiceRepository.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, err
+}
+
+func invoiceFilters(filter dtos.InvoiceFilter) (map[string]interface{}, error) {
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if filter.Kind != "" {
+		kind := INVOICEKIND(strings.ToUpper(strings.TrimSpace(filter.Kind)))
+		if kind != INVOICE_KIND_TAX_INVOICE && kind != INVOICE_KIND_CREDIT_NOTE {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidInvoice, filter.Kind)
+		}
+		filters["kind"] = map[string]interface{}{"Op": "eq", "value": kind}
+	}
+	if filter.OrderID != nil {
+		filters["order_id"] = map[string]interface{}{"Op": "eq", "value": *filter.OrderID}
+	}
+	return filters, nil
+}
+
+// joinName joins the non-empty parts of a person's name
+func joinName(parts ...string) string {
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// joinAddress joins the non-empty lines of an address with commas
+func joinAddress(parts ...string) string {
+	lines := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			lines = append(lines, part)
+		}
+	}
+	return strings.Join(lines, ", ")
+}
@@ -0,0 +1,163 @@
+// internal/services/lockout_service.go
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+)
+
+const (
+	throttleScopeAccount = "account"
+	throttleScopeIP      = "ip"
+)
+
+// LockoutPolicy holds the brute-force protection thresholds
+type LockoutPolicy struct {
+	MaxAccountFailures int           // failures before an account is locked
+	MaxIPFailures      int           // failures before a client IP is locked
+	FailureWindow      time.Duration // failures older than this are forgotten
+	LockoutDuration    time.Duration // how long a lock lasts
+	BackoffBase        time.Duration // delay after the first failure, doubled after each one
+}
+
+// LoginThrottledError is returned when a login is refused before credentials are checked
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return ACCOUNT_LOCKED
+	}
+	return TOO_MANY_LOGIN_ATTEMPTS
+}
+
+type LockoutService struct {
+	loginThrottleRepository repository.LoginThrottleRepositoryInterface
+	policy                  LockoutPolicy
+}
+
+func NewLockoutService(loginThrottleRepository repository.LoginThrottleRepositoryInterface, policy LockoutPolicy) *LockoutService {
+	return &LockoutService{
+		loginThrottleRepository: loginThrottleRepository,
+		policy:                  policy,
+	}
+}
+
+// CheckIP refuses the attempt if the client IP is locked or backing off
+func (s *LockoutService) CheckIP(ctx context.Context, ip string) error {
+	return s.check(throttleScopeIP, ip)
+}
+
+// CheckAccount refuses the attempt if the account is locked or backing off
+func (s *LockoutService) CheckAccount(ctx context.Context, userID uint) error {
+	return s.check(throttleScopeAccount, accountKey(userID))
+}
+
+// RecordFailure counts a failed login against the IP and, when known, the account
+func (s *LockoutService) RecordFailure(ctx context.Context, ip string, userID uint) error {
+	if err := s.recordFailure(throttleScopeIP, ip, s.policy.MaxIPFailures); err != nil {
+		return err
+	}
+	if userID == 0 {
+		return nil
+	}
+	return s.recordFailure(throttleScopeAccount, accountKey(userID), s.policy.MaxAccountFailures)
+}
+
+// RecordSuccess clears the account's counter after a successful login. The IP
+// counter is left to expire on its own, so logging into an account of one's
+// own between guesses at others does not lift the IP throttle.
+func (s *LockoutService) RecordSuccess(ctx context.Context, userID uint) error {
+	return s.loginThrottleRepository.Reset(throttleScopeAccount, accountKey(userID))
+}
+
+// Unlock clears an account lock on behalf of an administrator
+func (s *LockoutService) Unlock(ctx context.Context, userID uint, unlockedBy uint) error {
+	if err := s.loginThrottleRepository.Reset(throttleScopeAccount, accountKey(userID)); err != nil {
+		return err
+	}
+	logging.Log.Info("Account unlocked",
+		zap.Uint("user_id", userID),
+		zap.Uint("unlocked_by", unlockedBy),
+	)
+	return nil
+}
+
+func (s *LockoutService) check(scope, key string) error {
+	throttle, err := s.loginThrottleRepository.Find(scope, key)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return &LoginThrottledError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if s.isStale(throttle, now) || throttle.FailedCount == 0 {
+		return nil
+	}
+
+	nextAttempt := throttle.LastFailedAt.Add(s.backoff(throttle.FailedCount))
+	if now.Before(nextAttempt) {
+		return &LoginThrottledError{RetryAfter: nextAttempt.Sub(now)}
+	}
+	return nil
+}
+
+func (s *LockoutService) recordFailure(scope, key string, maxFailures int) error {
+	now := time.Now()
+	throttle, err := s.loginThrottleRepository.Update(scope, key, func(t *models.LoginThrottle) {
+		if s.isStale(t, now) || (t.LockedUntil != nil && !now.Before(*t.LockedUntil)) {
+			t.FailedCount = 0
+			t.LockedUntil = nil
+		}
+		t.FailedCount++
+		t.LastFailedAt = &now
+		if maxFailures > 0 && t.FailedCount >= maxFailures && t.LockedUntil == nil {
+			lockedUntil := now.Add(s.policy.LockoutDuration)
+			t.LockedUntil = &lockedUntil
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if throttle.LockedUntil != nil && throttle.FailedCount == maxFailures {
+		logging.Log.Warn("Login locked after repeated failures",
+			zap.String("scope", scope),
+			zap.String("key", key),
+			zap.Int("failed_count", throttle.FailedCount),
+			zap.Time("locked_until", *throttle.LockedUntil),
+		)
+	}
+	return nil
+}
+
+func (s *LockoutService) isStale(throttle *models.LoginThrottle, now time.Time) bool {
+	return throttle.LastFailedAt == nil || now.Sub(*throttle.LastFailedAt) > s.policy.FailureWindow
+}
+
+// backoff doubles the delay after each failure, capped at the lockout duration
+func (s *LockoutService) backoff(failures int) time.Duration {
+	delay := s.policy.BackoffBase
+	for i := 1; i < failures && delay < s.policy.LockoutDuration; i++ {
+		delay *= 2
+	}
+	if delay > s.policy.LockoutDuration {
+		delay = s.policy.LockoutDuration
+	}
+	return delay
+}
+
+func accountKey(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
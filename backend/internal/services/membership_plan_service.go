@@ -0,0 +1,222 @@
+// internal/services/membership_plan_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"backend/internal/dtos"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+// DefaultCurrency is used for plans created without a currency
+const DefaultCurrency = "INR"
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var (
+	ErrPlanNotFound     = errors.New(PLAN_NOT_FOUND)
+	ErrInvalidPlan      = errors.New(INVALID_PLAN_INPUT)
+	ErrPlanNotAvailable = errors.New(PLAN_NOT_AVAILABLE)
+)
+
+type MembershipPlanService struct {
+	planRepository  repository.MembershipPlanRepositoryInterface
+	fitAllieService *FitAllieService
+	fitCrewService  *FitCrewService
+}
+
+func NewMembershipPlanService(planRepository repository.MembershipPlanRepositoryInterface, fitAllieService *FitAllieService, fitCrewService *FitCrewService) *MembershipPlanService {
+	return &MembershipPlanService{
+		planRepository:  planRepository,
+		fitAllieService: fitAllieService,
+		fitCrewService:  fitCrewService,
+	}
+}
+
+func (s *MembershipPlanService) CreatePlan(ctx context.Context, requester Requester, allieID uint, input dtos.CreateMembershipPlanRequest) (*models.MembershipPlan, error) {
+	allie, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.MembershipPlan{
+		AllieID:      int(allie.ID),
+		CrewID:       input.CrewID,
+		Name:         strings.TrimSpace(input.Name),
+		Description:  input.Description,
+		DurationDays: input.DurationDays,
+		PriceMinor:   input.PriceMinor,
+		Currency:     strings.ToUpper(strings.TrimSpace(input.Currency)),
+		TaxRateBps:   input.TaxRateBps,
+		FreezeDays:   input.FreezeDays,
+		VisitLimit:   input.VisitLimit,
+		VisitsPerDay: input.VisitsPerDay,
+		IsActive:     true,
+		CreatedBy:    int(requester.UserID),
+		UpdatedBy:    int(requester.UserID),
+	}
+	if err := s.preparePlan(ctx, requester, plan, input.ServiceIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.planRepository.Create(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// GetPlan returns a plan of the allie when the requester may manage the allie
+func (s *MembershipPlanService) GetPlan(ctx context.Context, requester Requester, allieID, planID uint) (*models.MembershipPlan, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.findPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	if uint(plan.AllieID) != allieID {
+		return nil, ErrPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *MembershipPlanService) ListPlans(ctx context.Context, requester Requester, allieID uint, filter dtos.MembershipPlanFilter) ([]models.MembershipPlan, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	return s.planRepository.ListByAllie(allieID, filter.CrewID, filter.ActiveOnly)
+}
+
+// UpdatePlan changes a plan. Customers who already bought it keep the price and
+// entitlements they bought.
+func (s *MembershipPlanService) UpdatePlan(ctx context.Context, requester Requester, allieID, planID uint, input dtos.UpdateMembershipPlanRequest) (*models.MembershipPlan, error) {
+	plan, err := s.GetPlan(ctx, requester, allieID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.CrewID = input.CrewID
+	plan.Name = strings.TrimSpace(input.Name)
+	plan.Description = input.Description
+	plan.DurationDays = input.DurationDays
+	plan.PriceMinor = input.PriceMinor
+	plan.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	plan.TaxRateBps = input.TaxRateBps
+	plan.FreezeDays = input.FreezeDays
+	plan.VisitLimit = input.VisitLimit
+	plan.VisitsPerDay = input.VisitsPerDay
+	if input.IsActive != nil {
+		plan.IsActive = *input.IsActive
+	}
+	plan.UpdatedBy = int(requester.UserID)
+
+	if err := s.preparePlan(ctx, requester, plan, input.ServiceIDs); err != nil {
+		return nil, err
+	}
+	if err := s.planRepository.Update(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *MembershipPlanService) DeletePlan(ctx context.Context, requester Requester, allieID, planID uint) error {
+	if _, err := s.GetPlan(ctx, requester, allieID, planID); err != nil {
+		return err
+	}
+	return s.planRepository.Delete(planID)
+}
+
+// PlanForCrew returns a plan that may be sold at the crew
+func (s *MembershipPlanService) PlanForCrew(ctx context.Context, planID uint, crew *models.FitCrew) (*models.MembershipPlan, error) {
+	plan, err := s.findPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.AvailableAt(crew) {
+		return nil, ErrPlanNotAvailable
+	}
+	return plan, nil
+}
+
+func (s *MembershipPlanService) findPlan(id uint) (*models.MembershipPlan, error) {
+	plan, err := s.planRepository.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPlanNotFound
+	}
+	return plan, err
+}
+
+// preparePlan validates the plan, checks its crew belongs to the plan's allie
+// and loads the included services
+func (s *MembershipPlanService) preparePlan(ctx context.Context, requester Requester, plan *models.MembershipPlan, serviceIDs []uint) error {
+	if plan.Currency == "" {
+		plan.Currency = DefaultCurrency
+	}
+	if err := validatePlan(plan); err != nil {
+		return err
+	}
+
+	if plan.CrewID != nil {
+		crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, uint(*plan.CrewID))
+		if errors.Is(err, ErrCrewNotFound) {
+			return fmt.Errorf("%w: crew not found", ErrInvalidPlan)
+		}
+		if err != nil {
+			return err
+		}
+		if crew.AllieID != plan.AllieID {
+			return fmt.Errorf("%w: crew belongs to another allie", ErrInvalidPlan)
+		}
+	}
+
+	services, err := s.planRepository.FindServices(serviceIDs)
+	if err != nil {
+		return err
+	}
+	if len(services) != len(uniqueIDs(serviceIDs)) {
+		return fmt.Errorf("%w: unknown service", ErrInvalidPlan)
+	}
+	plan.Services = services
+	return nil
+}
+
+func validatePlan(plan *models.MembershipPlan) error {
+	if plan.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPlan)
+	}
+	if plan.DurationDays < 1 {
+		return fmt.Errorf("%w: duration must be at least 1 day", ErrInvalidPlan)
+	}
+	if plan.PriceMinor < 0 {
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidPlan)
+	}
+	if !currencyPattern.MatchString(plan.Currency) {
+		return fmt.Errorf("%w: currency must be a 3-letter ISO code", ErrInvalidPlan)
+	}
+	if plan.TaxRateBps < 0 || plan.TaxRateBps > 10000 {
+		return fmt.Errorf("%w: tax rate must be between 0 and 10000 basis points", ErrInvalidPlan)
+	}
+	if plan.FreezeDays < 0 || plan.VisitLimit < 0 || plan.VisitsPerDay < 0 {
+		return fmt.Errorf("%w: allowances cannot be negative", ErrInvalidPlan)
+	}
+	if plan.VisitLimit > 0 && plan.VisitsPerDay > plan.VisitLimit {
+		return fmt.Errorf("%w: visits per day cannot exceed the visit limit", ErrInvalidPlan)
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uint) map[uint]struct{} {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return unique
+}
@@ -0,0 +1,542 @@
+// internal/services/membership_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+// MaxFreezeDays caps a single freeze of a membership
+const MaxFreezeDays = 90
+
+// membershipDateLayout is the format of dates in membership requests
+const membershipDateLayout = "2006-01-02"
+
+var (
+	ErrCustomerNotFound        = errors.New(CUSTOMER_NOT_FOUND)
+	ErrCustomerAlreadyEnrolled = errors.New(CUSTOMER_ALREADY_ENROLLED)
+	ErrInvalidMembership       = errors.New(INVALID_MEMBERSHIP_INPUT)
+	ErrMembershipState         = errors.New(MEMBERSHIP_STATE_CONFLICT)
+)
+
+// MembershipService moves customers' memberships through their lifecycle.
+// Every transition is saved together with a history entry, and the stored
+// IsActive flag is always recomputed from the dates. Terms are sold through
+// PaymentService and only start once their payment is captured.
+type MembershipService struct {
+	customerRepository repository.CustomerRepositoryInterface
+	userRepository     repository.UserRepositoryInterface
+	fitCrewService     *FitCrewService
+	planService        *MembershipPlanService
+	paymentService     *PaymentService
+}
+
+func NewMembershipService(customerRepository repository.CustomerRepositoryInterface, userRepository repository.UserRepositoryInterface, fitCrewService *FitCrewService, planService *MembershipPlanService, paymentService *PaymentService) *MembershipService {
+	return &MembershipService{
+		customerRepository: customerRepository,
+		userRepository:     userRepository,
+		fitCrewService:     fitCrewService,
+		planService:        planService,
+		paymentService:     paymentService,
+	}
+}
+
+// Enroll makes a person a customer of the crew on a plan sold there and places
+// an order for the first term, storing both together so no customer is left
+// without its order. The customer awaits payment until the order is captured;
+// with a method, checked before anything is stored, the order is paid right
+// away, and when that payment fails the enrollment stands with the order left
+// to pay again.
+// The CUSTOMER user is matched by mobile, or created without a password so the
+// customer signs in with a mobile code.
+func (s *MembershipService) Enroll(ctx context.Context, requester Requester, crewID uint, input dtos.EnrollCustomerRequest) (*models.Customer, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	if !crew.IsActive {
+		return nil, fmt.Errorf("%w: crew is inactive", ErrInvalidMembership)
+	}
+	plan, err := s.planService.PlanForCrew(ctx, input.PlanID, crew)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := now
+	if input.StartDate != "" {
+		if start, err = parseMembershipDate("start_date", input.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	var dateOfBirth time.Time
+	if input.DateOfBirth != "" {
+		if dateOfBirth, err = parseMembershipDate("date_of_birth", input.DateOfBirth); err != nil {
+			return nil, err
+		}
+	}
+	state := strings.TrimSpace(input.State)
+	if _, ok := invoicing.StateCode(state); state != "" && !ok {
+		return nil, fmt.Errorf("%w: state %q is not an Indian state or union territory", ErrInvalidMembership, state)
+	}
+
+	customer := &models.Customer{
+		CrewID:          int(crew.ID),
+		FirstName:       strings.TrimSpace(input.FirstName),
+		MiddleName:      input.MiddleName,
+		LastName:        strings.TrimSpace(input.LastName),
+		Email:           strings.TrimSpace(input.Email),
+		Mobile:          strings.TrimSpace(input.Mobile),
+		AlternateMobile: input.AlternateMobile,
+		DateOfBirth:     dateOfBirth,
+		State:           state,
+		MembershipStart: start,
+		MembershipEnd:   start,
+		PlanID:          &plan.ID,
+		AwaitingPayment: true,
+	}
+	if customer.FirstName == "" || customer.LastName == "" {
+		return nil, fmt.Errorf("%w: first and last name are required", ErrInvalidMembership)
+	}
+
+	user, err := s.customerUser(customer, requester.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID != 0 {
+		_, err := s.customerRepository.FindByUserAndCrew(user.ID, customer.CrewID)
+		if err == nil {
+			return nil, ErrCustomerAlreadyEnrolled
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	order, err := s.paymentService.newOrder(ctx, crew, customer, &plan.ID, requester.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.paymentService.checkSale(order, input.Method); err != nil {
+		return nil, err
+	}
+
+	customer.IsActive = customer.MembershipActiveAt(now)
+	history := &models.MembershipHistory{
+		Action:      MEMBERSHIP_ENROLLED,
+		ToCrewID:    customer.CrewID,
+		PlanID:      &plan.ID,
+		PlanName:    plan.Name,
+		PerformedBy: requester.UserID,
+	}
+	if err := s.customerRepository.Enroll(user, customer, history, order); err != nil {
+		return nil, err
+	}
+	order.Customer = *customer
+
+	if _, err := s.paymentService.settleSale(ctx, order, input.Method, requester.UserID); err != nil {
+		return nil, err
+	}
+	return s.reload(customer.ID)
+}
+
+// GetCustomer returns a customer of the crew when the requester may manage the crew
+func (s *MembershipService) GetCustomer(ctx context.Context, requester Requester, crewID, customerID uint) (*models.Customer, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepository.FindByID(customerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(customer.CrewID) != crewID {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// ListCustomers returns a page of the crew's customers after bringing their
+// stored activity in line with today
+func (s *MembershipService) ListCustomers(ctx context.Context, requester Requester, crewID uint, filter dtos.CustomerFilter) ([]models.Customer, int64, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, 0, err
+	}
+	if err := s.customerRepository.SyncActive(crewID, time.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if filter.IsActive != nil {
+		filters["is_active"] = map[string]interface{}{"Op": "eq", "value": *filter.IsActive}
+	}
+	return s.customerRepository.List(crewID, filters, strings.TrimSpace(filter.Search))
+}
+
+// History returns the customer's membership transitions, oldest first
+func (s *MembershipService) History(ctx context.Context, requester Requester, crewID, customerID uint) ([]models.MembershipHistory, error) {
+	if _, err := s.GetCustomer(ctx, requester, crewID, customerID); err != nil {
+		return nil, err
+	}
+	return s.customerRepository.ListHistory(customerID)
+}
+
+// Renew sells another term of a plan, the customer's current one by default,
+// paid with the given method. The term is added by the capture of its payment:
+// a running membership gets it and its allowances appended; a lapsed or
+// cancelled one starts again with the plan's allowances. A payment still
+// pending leaves the membership as it is.
+func (s *MembershipService) Renew(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.RenewMembershipRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.paymentService.sell(ctx, crew, customer, input.PlanID, input.Method, requester.UserID); err != nil {
+		return nil, err
+	}
+	return s.reload(customer.ID)
+}
+
+// Extend adds days to a running membership without starting a new term
+func (s *MembershipService) Extend(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.ExtendMembershipRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if customer.CancelledAt != nil || customer.AwaitingPayment || !now.Before(customer.MembershipEnd) {
+		return nil, ErrMembershipState
+	}
+
+	previousEnd := customer.MembershipEnd
+	customer.MembershipEnd = customer.MembershipEnd.AddDate(0, 0, input.Days)
+
+	return customer, s.save(customer, requester, now, &models.MembershipHistory{
+		Action:      MEMBERSHIP_EXTENDED,
+		PreviousEnd: timePtr(previousEnd),
+		NewEnd:      timePtr(customer.MembershipEnd),
+		Days:        input.Days,
+		Note:        input.Note,
+	})
+}
+
+// Freeze pauses a running membership for the given days and pushes its end
+// date back by the same amount. Only one freeze may be pending or in effect,
+// and memberships bought on a plan may only use the plan's freeze allowance.
+func (s *MembershipService) Freeze(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.FreezeMembershipRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Days > MaxFreezeDays {
+		return nil, fmt.Errorf("%w: a freeze lasts at most %d days", ErrInvalidMembership, MaxFreezeDays)
+	}
+
+	now := time.Now()
+	from := now
+	if input.From != "" {
+		if from, err = parseMembershipDate("from", input.From); err != nil {
+			return nil, err
+		}
+		if from.Before(startOfDay(now)) {
+			return nil, fmt.Errorf("%w: a freeze cannot start in the past", ErrInvalidMembership)
+		}
+	}
+
+	if customer.CancelledAt != nil || customer.AwaitingPayment || !now.Before(customer.MembershipEnd) {
+		return nil, ErrMembershipState
+	}
+	if customer.FrozenUntil != nil && now.Before(*customer.FrozenUntil) {
+		return nil, ErrMembershipState
+	}
+	if from.Before(customer.MembershipStart) || !from.Before(customer.MembershipEnd) {
+		return nil, fmt.Errorf("%w: the freeze must start within the membership", ErrInvalidMembership)
+	}
+	if customer.PlanID != nil {
+		left := customer.FreezeDaysAllowed - customer.FreezeDaysUsed
+		if input.Days > left {
+			return nil, fmt.Errorf("%w: only %d freeze days are left", ErrInvalidMembership, left)
+		}
+		customer.FreezeDaysUsed += input.Days
+	}
+
+	previousEnd := customer.MembershipEnd
+	until := from.AddDate(0, 0, input.Days)
+	customer.FrozenFrom = &from
+	customer.FrozenUntil = &until
+	customer.MembershipEnd = customer.MembershipEnd.AddDate(0, 0, input.Days)
+
+	return customer, s.save(customer, requester, now, &models.MembershipHistory{
+		Action:      MEMBERSHIP_FROZEN,
+		PreviousEnd: timePtr(previousEnd),
+		NewEnd:      timePtr(customer.MembershipEnd),
+		Days:        input.Days,
+		Note:        input.Note,
+	})
+}
+
+// Unfreeze ends a freeze early. The end date gives back the unused freeze time,
+// so only the days actually spent frozen stay added, and whole unused days
+// return to the freeze allowance.
+func (s *MembershipService) Unfreeze(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.MembershipNoteRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if customer.CancelledAt != nil || customer.FrozenFrom == nil || customer.FrozenUntil == nil || !now.Before(*customer.FrozenUntil) {
+		return nil, ErrMembershipState
+	}
+
+	previousEnd := customer.MembershipEnd
+	resumeAt := now
+	if now.Before(*customer.FrozenFrom) {
+		resumeAt = *customer.FrozenFrom
+	}
+	unused := customer.FrozenUntil.Sub(resumeAt)
+	customer.MembershipEnd = customer.MembershipEnd.Add(-unused)
+	if customer.PlanID != nil {
+		customer.FreezeDaysUsed -= int(unused / (24 * time.Hour))
+		if customer.FreezeDaysUsed < 0 {
+			customer.FreezeDaysUsed = 0
+		}
+	}
+	if resumeAt.Equal(*customer.FrozenFrom) {
+		customer.FrozenFrom = nil
+		customer.FrozenUntil = nil
+	} else {
+		customer.FrozenUntil = &resumeAt
+	}
+
+	return customer, s.save(customer, requester, now, &models.MembershipHistory{
+		Action:      MEMBERSHIP_UNFROZEN,
+		PreviousEnd: timePtr(previousEnd),
+		NewEnd:      timePtr(customer.MembershipEnd),
+		Note:        input.Note,
+	})
+}
+
+// Transfer moves a membership to another crew of the same allie, keeping its dates
+func (s *MembershipService) Transfer(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.TransferMembershipRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if input.CrewID == crewID {
+		return nil, fmt.Errorf("%w: the customer is already at this crew", ErrInvalidMembership)
+	}
+
+	source, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.fitCrewService.AuthorizeCrew(ctx, requester, input.CrewID)
+	if err != nil {
+		return nil, err
+	}
+	if target.AllieID != source.AllieID {
+		return nil, fmt.Errorf("%w: memberships can only move between crews of the same allie", ErrInvalidMembership)
+	}
+	if !target.IsActive {
+		return nil, fmt.Errorf("%w: target crew is inactive", ErrInvalidMembership)
+	}
+
+	now := time.Now()
+	if customer.CancelledAt != nil || customer.AwaitingPayment || !now.Before(customer.MembershipEnd) {
+		return nil, ErrMembershipState
+	}
+	_, err = s.customerRepository.FindByUserAndCrew(customer.UserID, int(target.ID))
+	if err == nil {
+		return nil, ErrCustomerAlreadyEnrolled
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	customer.CrewID = int(target.ID)
+
+	return customer, s.save(customer, requester, now, &models.MembershipHistory{
+		Action:     MEMBERSHIP_TRANSFERRED,
+		FromCrewID: int(source.ID),
+		ToCrewID:   int(target.ID),
+		Note:       input.Note,
+	})
+}
+
+// Cancel ends a membership now. It can be started again with Renew.
+func (s *MembershipService) Cancel(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.MembershipNoteRequest) (*models.Customer, error) {
+	customer, err := s.GetCustomer(ctx, requester, crewID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.CancelledAt != nil {
+		return nil, ErrMembershipState
+	}
+
+	now := time.Now()
+	customer.CancelledAt = &now
+
+	return customer, s.save(customer, requester, now, &models.MembershipHistory{
+		Action:      MEMBERSHIP_CANCELLED,
+		PreviousEnd: timePtr(customer.MembershipEnd),
+		Note:        input.Note,
+	})
+}
+
+// reload returns the customer as stored after a payment changed it
+func (s *MembershipService) reload(customerID uint) (*models.Customer, error) {
+	customer, err := s.customerRepository.FindByID(customerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+// save recomputes IsActive from the dates and stores the transition
+func (s *MembershipService) save(customer *models.Customer, requester Requester, now time.Time, history *models.MembershipHistory) error {
+	customer.IsActive = customer.MembershipActiveAt(now)
+	history.PerformedBy = requester.UserID
+	if history.FromCrewID == 0 && history.ToCrewID == 0 {
+		history.ToCrewID = customer.CrewID
+	}
+	return s.customerRepository.SaveWithHistory(customer, history)
+}
+
+// applyPlan copies the plan's entitlements onto the customer. When the term is
+// appended to a running one, the allowances add up; an unlimited visit limit on
+// either side stays unlimited.
+func applyPlan(customer *models.Customer, plan *models.MembershipPlan, appended bool) {
+	customer.PlanID = &plan.ID
+	customer.VisitsPerDay = plan.VisitsPerDay
+	if !appended {
+		customer.FreezeDaysAllowed = plan.FreezeDays
+		customer.FreezeDaysUsed = 0
+		customer.VisitLimit = plan.VisitLimit
+		return
+	}
+
+	customer.FreezeDaysAllowed += plan.FreezeDays
+	if customer.VisitLimit == 0 || plan.VisitLimit == 0 {
+		customer.VisitLimit = 0
+	} else {
+		customer.VisitLimit += plan.VisitLimit
+	}
+}
+
+// renewTerm adds a term of the plan to the customer's membership and returns
+// its history entry. A running membership gets the term and its allowances
+// appended; a lapsed or cancelled one starts again at now. The first term of an
+// enrollment starts on its start date, or at now when that has passed.
+func renewTerm(customer *models.Customer, plan *models.MembershipPlan, now time.Time) *models.MembershipHistory {
+	previousEnd := customer.MembershipEnd
+	running := customer.CancelledAt == nil && !customer.AwaitingPayment && now.Before(customer.MembershipEnd)
+	if running {
+		customer.MembershipEnd = customer.MembershipEnd.AddDate(0, 0, plan.DurationDays)
+	} else {
+		start := now
+		if customer.AwaitingPayment && customer.CancelledAt == nil && now.Before(customer.MembershipStart) {
+			start = customer.MembershipStart
+		}
+		customer.CancelledAt = nil
+		customer.AwaitingPayment = false
+		customer.FrozenFrom = nil
+		customer.FrozenUntil = nil
+		customer.MembershipStart = start
+		customer.MembershipEnd = start.AddDate(0, 0, plan.DurationDays)
+	}
+	applyPlan(customer, plan, running)
+
+	return purchase(plan, &models.MembershipHistory{
+		Action:      MEMBERSHIP_RENEWED,
+		PreviousEnd: timePtr(previousEnd),
+		NewEnd:      timePtr(customer.MembershipEnd),
+	})
+}
+
+// purchase records the plan's price on the history entry of the term it bought
+func purchase(plan *models.MembershipPlan, history *models.MembershipHistory) *models.MembershipHistory {
+	history.PlanID = &plan.ID
+	history.PlanName = plan.Name
+	history.PriceMinor = plan.PriceMinor
+	history.TaxMinor = plan.TaxMinor()
+	history.Currency = plan.Currency
+	history.Days = plan.DurationDays
+	return history
+}
+
+// customerUser finds the CUSTOMER user owning the mobile number or builds a new
+// one. Logins held by other users or non-customers are refused.
+func (s *MembershipService) customerUser(customer *models.Customer, createdBy uint) (*models.User, error) {
+	user, err := s.userRepository.FindByMobile(customer.Mobile)
+	if err == nil {
+		if user.UserType != CUSTOMER {
+			return nil, ErrUserAlreadyRegistered
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	for _, identifier := range []string{customer.Email, customer.Mobile} {
+		_, err := s.userRepository.FindByLogin(identifier)
+		if err == nil {
+			return nil, ErrUserAlreadyRegistered
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	return &models.User{
+		FirstName: customer.FirstName,
+		LastName:  customer.LastName,
+		Email:     customer.Email,
+		Mobile:    customer.Mobile,
+		Username:  customer.Mobile,
+		UserType:  CUSTOMER,
+		IsActive:  true,
+		CreatedBy: int(createdBy),
+		UpdatedBy: int(createdBy),
+	}, nil
+}
+
+func parseMembershipDate(field, value string) (time.Time, error) {
+	date, err := time.ParseInLocation(membershipDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a YYYY-MM-DD date", ErrInvalidMembership, field)
+	}
+	return date, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
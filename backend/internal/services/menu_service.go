@@ -0,0 +1,260 @@
+// internal/services/menu_service.go
+package services
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// HomeRoute is the route the admin panel opens after login
+const HomeRoute = "home"
+
+var (
+	ErrMenuNotFound      = errors.New(MENU_NOT_FOUND)
+	ErrMenuNameTaken     = errors.New(MENU_NAME_TAKEN)
+	ErrMenuHasChildren   = errors.New(MENU_HAS_CHILDREN)
+	ErrMenuInvalidParent = errors.New(MENU_INVALID_PARENT)
+	ErrInvalidPermission = errors.New(INVALID_PERMISSION)
+)
+
+// defaultMenu is a seeded entry; parents are referenced by route name
+type defaultMenu struct {
+	parent string
+	menu   models.Menu
+}
+
+// defaultMenus are created on first start so a fresh install has a usable panel
+var defaultMenus = []defaultMenu{
+	{menu: models.Menu{Name: "login", Path: "/login/:module(pwd-login|code-login|register|reset-pwd|bind-wechat)?", Component: "layout.blank$view.login", Title: "login", I18nKey: "route.login", Constant: true, HideInMenu: true}},
+	{menu: models.Menu{Name: "403", Path: "/403", Component: "layout.blank$view.403", Title: "403", I18nKey: "route.403", Constant: true, HideInMenu: true}},
+	{menu: models.Menu{Name: "404", Path: "/404", Component: "layout.blank$view.404", Title: "404", I18nKey: "route.404", Constant: true, HideInMenu: true}},
+	{menu: models.Menu{Name: "500", Path: "/500", Component: "layout.blank$view.500", Title: "500", I18nKey: "route.500", Constant: true, HideInMenu: true}},
+	{menu: models.Menu{Name: HomeRoute, Path: "/home", Component: "layout.base$view.home", Title: "home", I18nKey: "route.home", Icon: "mdi:monitor-dashboard", Order: 1}},
+	{menu: models.Menu{Name: "allie", Path: "/allie", Component: "layout.base$view.allie", Title: "allie", I18nKey: "route.allie", Icon: "mdi:handshake-outline", Order: 2, Permission: string(PERM_ALLIE_READ)}},
+	{menu: models.Menu{Name: "manage", Path: "/manage", Component: "layout.base", Title: "manage", I18nKey: "route.manage", Icon: "carbon:cloud-service-management", Order: 9, Permission: string(PERM_USER_READ)}},
+	{parent: "manage", menu: models.Menu{Name: "manage_user", Path: "/manage/user", Component: "view.manage_user", Title: "manage_user", I18nKey: "route.manage_user", Icon: "ic:round-manage-accounts", Order: 1, Permission: string(PERM_USER_READ)}},
+	{parent: "manage", menu: models.Menu{Name: "manage_menu", Path: "/manage/menu", Component: "view.manage_menu", Title: "manage_menu", I18nKey: "route.manage_menu", Icon: "material-symbols:route", Order: 2, Permission: string(PERM_MENU_READ)}},
+}
+
+type MenuService struct {
+	menuRepository repository.MenuRepositoryInterface
+}
+
+func NewMenuService(menuRepository repository.MenuRepositoryInterface) *MenuService {
+	return &MenuService{
+		menuRepository: menuRepository,
+	}
+}
+
+// UserRoutes returns the active entries the role may see. Entries whose parent
+// is hidden from the role are dropped when the tree is built.
+func (s *MenuService) UserRoutes(ctx context.Context, role USERROLE) ([]models.Menu, error) {
+	menus, err := s.menuRepository.ListActive(false)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]models.Menu, 0, len(menus))
+	for _, menu := range menus {
+		if menu.Permission == "" || role.HasPermission(PERMISSION(menu.Permission)) {
+			allowed = append(allowed, menu)
+		}
+	}
+	return allowed, nil
+}
+
+// ConstantRoutes returns the entries served to everyone, signed in or not
+func (s *MenuService) ConstantRoutes(ctx context.Context) ([]models.Menu, error) {
+	return s.menuRepository.ListActive(true)
+}
+
+// RouteExists reports whether a menu entry with the route name exists
+func (s *MenuService) RouteExists(ctx context.Context, name string) (bool, error) {
+	_, err := s.menuRepository.FindByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *MenuService) ListMenus(ctx context.Context) ([]models.Menu, error) {
+	return s.menuRepository.ListAll()
+}
+
+func (s *MenuService) CreateMenu(ctx context.Context, input dtos.CreateMenuRequest) (*models.Menu, error) {
+	if err := s.checkName(input.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(input.ParentID, 0); err != nil {
+		return nil, err
+	}
+	if err := checkMenuPermission(input.Permission); err != nil {
+		return nil, err
+	}
+
+	menu := &models.Menu{
+		ParentID:   input.ParentID,
+		Name:       input.Name,
+		Path:       input.Path,
+		Component:  input.Component,
+		Title:      input.Title,
+		I18nKey:    input.I18nKey,
+		Icon:       input.Icon,
+		Order:      input.Order,
+		Permission: input.Permission,
+		Constant:   input.Constant,
+		HideInMenu: input.HideInMenu,
+		KeepAlive:  input.KeepAlive,
+		IsActive:   true,
+	}
+	if err := s.menuRepository.Create(menu); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+func (s *MenuService) UpdateMenu(ctx context.Context, id uint, input dtos.UpdateMenuRequest) (*models.Menu, error) {
+	menu, err := s.findMenu(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkName(input.Name, id); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(input.ParentID, id); err != nil {
+		return nil, err
+	}
+	if err := checkMenuPermission(input.Permission); err != nil {
+		return nil, err
+	}
+
+	menu.ParentID = input.ParentID
+	menu.Name = input.Name
+	menu.Path = input.Path
+	menu.Component = input.Component
+	menu.Title = input.Title
+	menu.I18nKey = input.I18nKey
+	menu.Icon = input.Icon
+	menu.Order = input.Order
+	menu.Permission = input.Permission
+	menu.Constant = input.Constant
+	menu.HideInMenu = input.HideInMenu
+	menu.KeepAlive = input.KeepAlive
+	if input.IsActive != nil {
+		menu.IsActive = *input.IsActive
+	}
+
+	if err := s.menuRepository.Update(menu); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+// DeleteMenu removes a leaf entry; entries with children must be emptied first
+func (s *MenuService) DeleteMenu(ctx context.Context, id uint) error {
+	if _, err := s.findMenu(id); err != nil {
+		return err
+	}
+
+	children, err := s.menuRepository.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrMenuHasChildren
+	}
+	return s.menuRepository.Delete(id)
+}
+
+// SeedDefaults creates the default entries that do not exist yet. Existing
+// entries are left alone so administrators' changes survive restarts.
+func (s *MenuService) SeedDefaults(ctx context.Context) error {
+	created := 0
+	for _, entry := range defaultMenus {
+		exists, err := s.RouteExists(ctx, entry.menu.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		menu := entry.menu
+		menu.IsActive = true
+		if entry.parent != "" {
+			parent, err := s.menuRepository.FindByName(entry.parent)
+			if err != nil {
+				return err
+			}
+			menu.ParentID = &parent.ID
+		}
+		if err := s.menuRepository.Create(&menu); err != nil {
+			return err
+		}
+		created++
+	}
+
+	if created > 0 {
+		logging.Log.Info("Seeded default menus", zap.Int("created", created))
+	}
+	return nil
+}
+
+func (s *MenuService) findMenu(id uint) (*models.Menu, error) {
+	menu, err := s.menuRepository.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMenuNotFound
+	}
+	return menu, err
+}
+
+// checkName rejects a route name already used by another entry
+func (s *MenuService) checkName(name string, id uint) error {
+	existing, err := s.menuRepository.FindByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return ErrMenuNameTaken
+	}
+	return nil
+}
+
+// checkParent rejects a parent that does not exist or would create a cycle
+func (s *MenuService) checkParent(parentID *uint, id uint) error {
+	for next := parentID; next != nil; {
+		if id != 0 && *next == id {
+			return ErrMenuInvalidParent
+		}
+		parent, err := s.menuRepository.FindByID(*next)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMenuInvalidParent
+		}
+		if err != nil {
+			return err
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
+
+func checkMenuPermission(permission string) error {
+	if permission != "" && !IsValidPermission(PERMISSION(permission)) {
+		return ErrInvalidPermission
+	}
+	return nil
+}
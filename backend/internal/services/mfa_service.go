@@ -0,0 +1,248 @@
+// internal/services/mfa_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"backend/pkg/totp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts codes from one step either side to absorb clock drift
+	totpSkew = 1
+)
+
+var (
+	ErrInvalidMFACode    = errors.New(MFA_CODE_INVALID)
+	ErrMFANotEnrolled    = errors.New(MFA_NOT_ENROLLED)
+	ErrMFAAlreadyEnabled = errors.New(MFA_ALREADY_ENABLED)
+	ErrInvalidMFAPending = errors.New(UNAUTHORIZED_ACCESS)
+)
+
+// MFAPolicy holds the two-factor authentication settings
+type MFAPolicy struct {
+	Issuer        string        // shown by authenticator apps next to the account
+	RequiredRoles []USERROLE    // roles that must enroll before using the panel
+	PendingTTL    time.Duration // lifetime of the token between the two login steps
+}
+
+// MFAEnrollment is what a user needs to add the account to an authenticator app
+type MFAEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type MFAService struct {
+	userRepository repository.UserRepositoryInterface
+	mfaRepository  repository.MFARepositoryInterface
+	lockoutService *LockoutService
+	policy         MFAPolicy
+}
+
+func NewMFAService(userRepository repository.UserRepositoryInterface, mfaRepository repository.MFARepositoryInterface, lockoutService *LockoutService, policy MFAPolicy) *MFAService {
+	return &MFAService{
+		userRepository: userRepository,
+		mfaRepository:  mfaRepository,
+		lockoutService: lockoutService,
+		policy:         policy,
+	}
+}
+
+// IsEnabled reports whether the user must give a code after the password
+func (s *MFAService) IsEnabled(ctx context.Context, userID uint) (bool, error) {
+	return s.mfaRepository.IsEnabled(userID)
+}
+
+// BeginEnrollment generates a new secret for the user. It replaces any
+// unconfirmed secret but never an enabled one.
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID uint) (*MFAEnrollment, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.mfaRepository.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepository.SaveEnrollment(&models.UserMFA{
+		UserID: user.ID,
+		Secret: secret,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.policy.Issuer, user.Username),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves the authenticator works and
+// returns the recovery codes. The plain codes are only available here.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error) {
+	mfa, err := s.mfaRepository.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	counter, ok := totp.Validate(mfa.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	enabled, err := s.mfaRepository.Enable(userID, counter)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	codes, records, err := generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepository.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+
+	logging.Log.Info("MFA enabled", zap.Uint("user_id", userID))
+	return codes, nil
+}
+
+// IssuePendingToken returns the token a client exchanges, together with a code,
+// for a session once the password step succeeded
+func (s *MFAService) IssuePendingToken(ctx context.Context, user *models.User) (string, error) {
+	return middleware.GenerateMFAPendingToken(user.ID, s.policy.PendingTTL)
+}
+
+// VerifyLogin completes the second login step with either a TOTP code or a
+// recovery code. Wrong codes count towards the account lockout.
+func (s *MFAService) VerifyLogin(ctx context.Context, pendingToken, code, recoveryCode, clientIP string) (*models.User, error) {
+	userID, err := middleware.ValidateMFAPendingToken(pendingToken)
+	if err != nil {
+		return nil, ErrInvalidMFAPending
+	}
+
+	if err := s.lockoutService.CheckAccount(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepository.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt == nil {
+		return nil, ErrMFANotEnrolled
+	}
+
+	ok, err := s.checkCode(mfa, code, recoveryCode)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.lockoutService.RecordFailure(ctx, clientIP, userID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidMFAPending
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.IsDeleted {
+		return nil, ErrAccountInactive
+	}
+
+	if err := s.lockoutService.RecordSuccess(ctx, userID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkCode accepts a TOTP code at most once, or consumes a recovery code
+func (s *MFAService) checkCode(mfa *models.UserMFA, code, recoveryCode string) (bool, error) {
+	if code != "" {
+		counter, ok := totp.Validate(mfa.Secret, code, time.Now(), totpSkew)
+		if !ok {
+			return false, nil
+		}
+		return s.mfaRepository.AdvanceCounter(mfa.UserID, counter)
+	}
+
+	if recoveryCode == "" {
+		return false, nil
+	}
+	used, err := s.mfaRepository.ConsumeRecoveryCode(mfa.UserID, hashToken(normalizeRecoveryCode(recoveryCode)))
+	if err != nil {
+		return false, err
+	}
+	if used {
+		logging.Log.Info("MFA recovery code used", zap.Uint("user_id", mfa.UserID))
+	}
+	return used, nil
+}
+
+// generateRecoveryCodes returns plain codes formatted as xxxx-xxxx-xxxx-xxxx
+// together with the records storing their hashes
+func generateRecoveryCodes(userID uint) ([]string, []models.MFARecoveryCode, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %v", err)
+		}
+		raw := encoding.EncodeToString(b)
+		codes = append(codes, strings.ToLower(raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16]))
+		records = append(records, models.MFARecoveryCode{
+			UserID:   userID,
+			CodeHash: hashToken(raw),
+		})
+	}
+	return codes, records, nil
+}
+
+// normalizeRecoveryCode strips separators and case so users can type codes loosely
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
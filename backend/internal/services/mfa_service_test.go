@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/totp"
+)
+
+// fakeMFARepository keeps the last accepted time step in memory, with the
+// semantics of MFARepository.AdvanceCounter
+type fakeMFARepository struct {
+	repository.MFARepositoryInterface
+	lastUsed map[uint]int64
+}
+
+func (r *fakeMFARepository) AdvanceCounter(userID uint, counter int64) (bool, error) {
+	if last, ok := r.lastUsed[userID]; ok && last >= counter {
+		return false, nil
+	}
+	r.lastUsed[userID] = counter
+	return true, nil
+}
+
+func newMFATest(t *testing.T) (*MFAService, *models.UserMFA, int64) {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	service := &MFAService{mfaRepository: &fakeMFARepository{lastUsed: make(map[uint]int64)}}
+	return service, &models.UserMFA{UserID: 7, Secret: secret}, totp.Counter(time.Now())
+}
+
+func codeAt(t *testing.T, secret string, counter int64) string {
+	t.Helper()
+	code, err := totp.Code(secret, counter)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	return code
+}
+
+func TestCheckCodeRejectsReplay(t *testing.T) {
+	service, mfa, now := newMFATest(t)
+	code := codeAt(t, mfa.Secret, now)
+
+	ok, err := service.checkCode(mfa, code, "")
+	if err != nil || !ok {
+		t.Fatalf("first use = %v, %v; want accepted", ok, err)
+	}
+	ok, err = service.checkCode(mfa, code, "")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if ok {
+		t.Error("the same code was accepted twice")
+	}
+}
+
+func TestCheckCodeRejectsEarlierStepAfterLaterOne(t *testing.T) {
+	service, mfa, now := newMFATest(t)
+
+	if ok, err := service.checkCode(mfa, codeAt(t, mfa.Secret, now+1), ""); err != nil || !ok {
+		t.Fatalf("next step code = %v, %v; want accepted within skew", ok, err)
+	}
+	if ok, _ := service.checkCode(mfa, codeAt(t, mfa.Secret, now-1), ""); ok {
+		t.Error("a code older than the last accepted one was accepted")
+	}
+}
+
+func TestCheckCodeAcceptsLaterStep(t *testing.T) {
+	service, mfa, now := newMFATest(t)
+
+	if ok, err := service.checkCode(mfa, codeAt(t, mfa.Secret, now-1), ""); err != nil || !ok {
+		t.Fatalf("previous step code = %v, %v; want accepted within skew", ok, err)
+	}
+	if ok, err := service.checkCode(mfa, codeAt(t, mfa.Secret, now), ""); err != nil || !ok {
+		t.Errorf("current step code after the previous one = %v, %v; want accepted", ok, err)
+	}
+}
+
+func TestCheckCodeRejectsOutsideSkew(t *testing.T) {
+	service, mfa, now := newMFATest(t)
+
+	for _, counter := range []int64{now - 3, now + 3} {
+		if ok, _ := service.checkCode(mfa, codeAt(t, mfa.Secret, counter), ""); ok {
+			t.Errorf("code of step %+d was accepted", counter-now)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// internal/services/otp_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/notifications"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidOTP = errors.New(OTP_INVALID)
+
+// OTPPolicy holds the one-time code settings
+type OTPPolicy struct {
+	Length          int
+	TTL             time.Duration
+	ResendInterval  time.Duration // minimum gap between two codes to one number
+	MaxSendsPerHour int
+	MaxAttempts     int // wrong guesses before a code is burned
+}
+
+// OTPResendError is returned when a code was requested too soon after the last one
+type OTPResendError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OTPResendError) Error() string {
+	return OTP_RESEND_TOO_SOON
+}
+
+type OTPService struct {
+	userRepository repository.UserRepositoryInterface
+	otpRepository  repository.OTPRepositoryInterface
+	smsSender      notifications.SMSSender
+	policy         OTPPolicy
+}
+
+func NewOTPService(userRepository repository.UserRepositoryInterface, otpRepository repository.OTPRepositoryInterface, smsSender notifications.SMSSender, policy OTPPolicy) *OTPService {
+	return &OTPService{
+		userRepository: userRepository,
+		otpRepository:  otpRepository,
+		smsSender:      smsSender,
+		policy:         policy,
+	}
+}
+
+// RequestCode sends a login code to an active customer's mobile number. Numbers
+// that do not belong to an active customer get no message but are recorded and
+// throttled like any other, so the endpoint answers the same either way and
+// cannot be used to discover accounts.
+func (s *OTPService) RequestCode(ctx context.Context, mobile string) error {
+	if err := s.checkResend(mobile); err != nil {
+		return err
+	}
+
+	user, err := s.userRepository.FindByMobile(mobile)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	eligible := err == nil && user.UserType == CUSTOMER && user.IsActive
+
+	code, err := s.generateCode()
+	if err != nil {
+		return err
+	}
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash code: %v", err)
+	}
+
+	if err := s.otpRepository.InvalidateForMobile(mobile); err != nil {
+		return err
+	}
+	now := time.Now()
+	record := &models.OTPCode{
+		Mobile:    mobile,
+		CodeHash:  string(codeHash),
+		ExpiresAt: now.Add(s.policy.TTL),
+	}
+	if !eligible {
+		record.ConsumedAt = &now
+		return s.otpRepository.Create(record)
+	}
+	record.UserID = &user.ID
+	if err := s.otpRepository.Create(record); err != nil {
+		return err
+	}
+
+	return s.smsSender.SendSMS(ctx, mobile, fmt.Sprintf(
+		"%s is your FlexioFit login code. It expires in %d minutes.",
+		code, int(s.policy.TTL.Minutes())))
+}
+
+// VerifyCode consumes a valid code and returns the user it was issued to
+func (s *OTPService) VerifyCode(ctx context.Context, mobile, code string) (*models.User, error) {
+	record, err := s.otpRepository.Verify(mobile, func(otp *models.OTPCode) bool {
+		if otp.Attempts > s.policy.MaxAttempts {
+			now := time.Now()
+			otp.ConsumedAt = &now
+			return false
+		}
+		return bcrypt.CompareHashAndPassword([]byte(otp.CodeHash), []byte(code)) == nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOTP
+		}
+		return nil, err
+	}
+
+	if record.UserID == nil {
+		return nil, ErrInvalidOTP
+	}
+	user, err := s.userRepository.FindByID(*record.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOTP
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.IsDeleted {
+		return nil, ErrAccountInactive
+	}
+	return user, nil
+}
+
+func (s *OTPService) checkResend(mobile string) error {
+	latest, err := s.otpRepository.FindLatest(mobile)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if latest != nil {
+		if wait := time.Until(latest.CreatedAt.Add(s.policy.ResendInterval)); wait > 0 {
+			return &OTPResendError{RetryAfter: wait}
+		}
+	}
+
+	if s.policy.MaxSendsPerHour > 0 {
+		sent, err := s.otpRepository.CountSince(mobile, time.Now().Add(-time.Hour))
+		if err != nil {
+			return err
+		}
+		if sent >= int64(s.policy.MaxSendsPerHour) {
+			return &OTPResendError{RetryAfter: time.Hour}
+		}
+	}
+	return nil
+}
+
+// generateCode returns a uniformly random numeric code of the configured length
+func (s *OTPService) generateCode() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(s.policy.Length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code: %v", err)
+	}
+	return fmt.Sprintf("%0*d", s.policy.Length, n), nil
+}
@@ -0,0 +1,153 @@
+// internal/services/password_service.go
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/notifications"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidResetToken = errors.New(PASSWORD_RESET_TOKEN_INVALID)
+	ErrIncorrectPassword = errors.New(INCORRECT_CURRENT_PASSWORD)
+	ErrPasswordUnchanged = errors.New(PASSWORD_UNCHANGED)
+)
+
+type PasswordService struct {
+	userRepository          repository.UserRepositoryInterface
+	passwordResetRepository repository.PasswordResetRepositoryInterface
+	refreshTokenRepository  repository.RefreshTokenRepositoryInterface
+	notifier                notifications.Notifier
+	resetTokenTTL           time.Duration
+}
+
+func NewPasswordService(
+	userRepository repository.UserRepositoryInterface,
+	passwordResetRepository repository.PasswordResetRepositoryInterface,
+	refreshTokenRepository repository.RefreshTokenRepositoryInterface,
+	notifier notifications.Notifier,
+	resetTokenTTL time.Duration,
+) *PasswordService {
+	return &PasswordService{
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		refreshTokenRepository:  refreshTokenRepository,
+		notifier:                notifier,
+		resetTokenTTL:           resetTokenTTL,
+	}
+}
+
+// RequestReset issues a reset token to the user matching the identifier. Unknown
+// or inactive users are ignored without error so the endpoint cannot be used to
+// discover accounts.
+func (s *PasswordService) RequestReset(ctx context.Context, identifier string) error {
+	user, err := s.userRepository.FindByLogin(identifier)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	if err := s.passwordResetRepository.InvalidateForUser(user.ID); err != nil {
+		return err
+	}
+
+	token, err := newTokenID()
+	if err != nil {
+		return err
+	}
+	record := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.resetTokenTTL),
+	}
+	if err := s.passwordResetRepository.Create(record); err != nil {
+		return err
+	}
+
+	return s.notifier.Send(ctx, notifications.Message{
+		To:      user.Email,
+		Subject: "Reset your FlexioFit password",
+		Body: fmt.Sprintf("Use this code to reset your password: %s\nIt expires in %d minutes.",
+			token, int(s.resetTokenTTL.Minutes())),
+	})
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere
+func (s *PasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	record, err := s.passwordResetRepository.Consume(hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	user, err := s.userRepository.FindByID(record.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if err := s.setPassword(user, newPassword); err != nil {
+		return err
+	}
+
+	logging.Log.Info("Password reset", zap.Uint("user_id", user.ID))
+	return s.refreshTokenRepository.RevokeAllForUser(user.ID)
+}
+
+// ChangePassword replaces the password of a signed-in user and clears any
+// pending forced change
+func (s *PasswordService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) (*models.User, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return nil, ErrPasswordUnchanged
+	}
+
+	if err := s.setPassword(user, newPassword); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *PasswordService) setPassword(user *models.User, password string) error {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+	user.PasswordHash = string(passwordHash)
+	user.MustChangePassword = false
+	return s.userRepository.Update(user)
+}
+
+// hashToken returns the hex SHA-256 of a high-entropy token for storage
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
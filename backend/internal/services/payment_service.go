@@ -0,0 +1,731 @@
+// internal/services/payment_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/payments"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotFound        = errors.New(ORDER_NOT_FOUND)
+	ErrInvalidPayment       = errors.New(INVALID_PAYMENT_INPUT)
+	ErrInvalidPaymentMethod = errors.New(INVALID_PAYMENT_METHOD)
+	ErrOrderNotPayable      = errors.New(ORDER_NOT_PAYABLE)
+	ErrPaymentInProgress    = errors.New(PAYMENT_IN_PROGRESS)
+	ErrPaymentFailed        = errors.New(PAYMENT_FAILED)
+	ErrRefundNotAllowed     = errors.New(REFUND_NOT_ALLOWED)
+	ErrRefundFailed         = errors.New(REFUND_FAILED)
+	ErrInvalidWebhook       = errors.New(INVALID_WEBHOOK_SIGNATURE)
+	ErrUnknownGateway       = errors.New(UNKNOWN_PAYMENT_GATEWAY)
+)
+
+// Payment methods staff record at a crew's desk instead of charging through the
+// gateway. Their attempts are captured at once under DeskGateway.
+const (
+	DeskGateway             = "desk"
+	DeskMethodCash          = "cash"          // paid in cash at the desk
+	DeskMethodComplimentary = "complimentary" // a free plan handed out at the desk
+)
+
+// PaymentService sells membership terms, through the payment gateway or at the
+// desk. An order copies the plan when placed; the term is only added to the
+// membership when a payment is captured, in the same transaction that posts it
+// to the ledger. Captures and refunds have their invoice and credit notes
+// issued by InvoiceService.
+type PaymentService struct {
+	paymentRepository  repository.PaymentRepositoryInterface
+	customerRepository repository.CustomerRepositoryInterface
+	crewRepository     repository.FitCrewRepositoryInterface
+	fitCrewService     *FitCrewService
+	planService        *MembershipPlanService
+	invoiceService     *InvoiceService
+	gateway            payments.PaymentGateway
+}
+
+func NewPaymentService(
+	paymentRepository repository.PaymentRepositoryInterface,
+	customerRepository repository.CustomerRepositoryInterface,
+	crewRepository repository.FitCrewRepositoryInterface,
+	fitCrewService *FitCrewService,
+	planService *MembershipPlanService,
+	invoiceService *InvoiceService,
+	gateway payments.PaymentGateway,
+) *PaymentService {
+	return &PaymentService{
+		paymentRepository:  paymentRepository,
+		customerRepository: customerRepository,
+		crewRepository:     crewRepository,
+		fitCrewService:     fitCrewService,
+		planService:        planService,
+		invoiceService:     invoiceService,
+		gateway:            gateway,
+	}
+}
+
+// CreateOrder orders a membership term for a customer of the crew and pays it
+func (s *PaymentService) CreateOrder(ctx context.Context, requester Requester, crewID, customerID uint, input dtos.CreateOrderRequest) (*models.PaymentOrder, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.customerRepository.FindByID(customerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(customer.CrewID) != crewID {
+		return nil, ErrCustomerNotFound
+	}
+
+	return s.sell(ctx, crew, customer, input.PlanID, input.Method, requester.UserID)
+}
+
+// CreateOwnOrder lets the signed-in customer buy a term of their membership at the crew
+func (s *PaymentService) CreateOwnOrder(ctx context.Context, requester Requester, crewID uint, input dtos.CreateOrderRequest) (*models.PaymentOrder, error) {
+	customer, err := s.customerRepository.FindByUserAndCrew(requester.UserID, int(crewID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	crew, err := s.crewRepository.FindByID(crewID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.newOrder(ctx, crew, customer, input.PlanID, requester.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := paymentGateway(s.gateway, order, input.Method, false); err != nil {
+		return nil, err
+	}
+	if err := s.createOrder(order, customer); err != nil {
+		return nil, err
+	}
+	return s.pay(ctx, order, input.Method, requester.UserID, false)
+}
+
+// PayOrder tries again to pay an order of the crew
+func (s *PaymentService) PayOrder(ctx context.Context, requester Requester, crewID, orderID uint, input dtos.PayOrderRequest) (*models.PaymentOrder, error) {
+	order, err := s.crewOrder(ctx, requester, crewID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.pay(ctx, order, input.Method, requester.UserID, true)
+}
+
+// PayOwnOrder lets the signed-in customer try again to pay one of their orders
+func (s *PaymentService) PayOwnOrder(ctx context.Context, requester Requester, orderID uint, input dtos.PayOrderRequest) (*models.PaymentOrder, error) {
+	order, err := s.findOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Customer.UserID != requester.UserID {
+		return nil, ErrOrderNotFound
+	}
+	return s.pay(ctx, order, input.Method, requester.UserID, false)
+}
+
+// GetOrder returns an order of the crew with its ledger entries
+func (s *PaymentService) GetOrder(ctx context.Context, requester Requester, crewID, orderID uint) (*models.PaymentOrder, []models.LedgerEntry, error) {
+	order, err := s.crewOrder(ctx, requester, crewID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := s.paymentRepository.LedgerForOrder(order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, entries, nil
+}
+
+// ListOrders returns a page of the crew's orders, latest first
+func (s *PaymentService) ListOrders(ctx context.Context, requester Requester, crewID uint, filter dtos.OrderFilter) ([]models.PaymentOrder, int64, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, 0, err
+	}
+	filters, err := orderFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.paymentRepository.ListOrders(crewID, filters)
+}
+
+// ListOwnOrders returns a page of the signed-in customer's orders, latest first
+func (s *PaymentService) ListOwnOrders(ctx context.Context, requester Requester, filter dtos.OrderFilter) ([]models.PaymentOrder, int64, error) {
+	filters, err := orderFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.paymentRepository.ListOrdersForUser(requester.UserID, filters)
+}
+
+// Refund returns part or all of a paid order of the crew to the customer. The
+// membership is left as it is; cancel it separately when the refund ends it.
+func (s *PaymentService) Refund(ctx context.Context, requester Requester, crewID, orderID uint, input dtos.RefundOrderRequest) (*models.PaymentOrder, error) {
+	order, err := s.crewOrder(ctx, requester, crewID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var captured *models.PaymentAttempt
+	for i := range order.Attempts {
+		if order.Attempts[i].Status == ATTEMPT_STATUS_CAPTURED {
+			captured = &order.Attempts[i]
+		}
+	}
+	if captured == nil {
+		return nil, fmt.Errorf("%w: the order is not paid", ErrRefundNotAllowed)
+	}
+	if captured.Gateway != s.gateway.Name() && captured.Gateway != DeskGateway {
+		return nil, fmt.Errorf("%w: the order was paid through the %s gateway", ErrRefundNotAllowed, captured.Gateway)
+	}
+	amount := order.RefundableMinor()
+	if input.AmountMinor != nil {
+		amount = *input.AmountMinor
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: nothing is left to refund", ErrRefundNotAllowed)
+	}
+
+	reference, err := paymentReference("re")
+	if err != nil {
+		return nil, err
+	}
+	refund := &models.PaymentRefund{
+		OrderID:     order.ID,
+		AttemptID:   captured.ID,
+		Gateway:     captured.Gateway,
+		Reference:   reference,
+		AmountMinor: amount,
+		Currency:    order.Currency,
+		Status:      REFUND_STATUS_PENDING,
+		Reason:      strings.TrimSpace(input.Reason),
+		RequestedBy: requester.UserID,
+	}
+	outcome, err := s.paymentRepository.CreateRefund(refund)
+	if err != nil {
+		return nil, err
+	}
+	switch outcome {
+	case repository.PaymentSettled:
+		return nil, fmt.Errorf("%w: the order is not paid", ErrRefundNotAllowed)
+	case repository.PaymentOverRefund:
+		return nil, fmt.Errorf("%w: the amount exceeds what is left to refund", ErrRefundNotAllowed)
+	}
+	if refund.Gateway == DeskGateway {
+		// handed back at the desk, so there is nothing to wait for
+		if err := s.settleRefund(ctx, refund, "", time.Now()); err != nil {
+			return nil, err
+		}
+		return s.findOrder(order.ID)
+	}
+
+	result, err := s.gateway.Refund(ctx, payments.RefundRequest{
+		Reference:   refund.Reference,
+		ChargeRef:   captured.ProviderRef,
+		AmountMinor: refund.AmountMinor,
+		Currency:    refund.Currency,
+	})
+	if err != nil {
+		if _, failErr := s.paymentRepository.FailRefund(refund.ID, "", err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+	switch result.Status {
+	case payments.RefundSucceeded:
+		if err := s.settleRefund(ctx, refund, result.ProviderRef, time.Now()); err != nil {
+			return nil, err
+		}
+	case payments.RefundFailed:
+		if _, err := s.paymentRepository.FailRefund(refund.ID, result.ProviderRef, result.FailureReason); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrRefundFailed, result.FailureReason)
+	}
+	return s.findOrder(order.ID)
+}
+
+// HandleWebhook applies a verified webhook of the gateway. It reports true for
+// an event that was already processed, which gateways may redeliver.
+func (s *PaymentService) HandleWebhook(ctx context.Context, gatewayName string, payload []byte, signature string) (bool, error) {
+	if gatewayName != s.gateway.Name() {
+		return false, ErrUnknownGateway
+	}
+	event, err := s.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		logging.Log.Warn("Payment webhook rejected", zap.String("gateway", gatewayName), zap.Error(err))
+		return false, ErrInvalidWebhook
+	}
+
+	stored, err := s.paymentRepository.RecordWebhookEvent(&models.PaymentWebhookEvent{
+		Gateway: gatewayName,
+		EventID: event.ID,
+		Type:    event.Type,
+		Payload: string(payload),
+	})
+	if err != nil {
+		return false, err
+	}
+	if stored.ProcessedAt != nil {
+		return true, nil
+	}
+
+	now := time.Now()
+	switch event.Type {
+	case payments.EventPaymentCaptured, payments.EventPaymentFailed:
+		err = s.applyPaymentEvent(ctx, event, now)
+	case payments.EventRefundSucceeded, payments.EventRefundFailed:
+		err = s.applyRefundEvent(ctx, event, now)
+	default:
+		logging.Log.Info("Payment webhook ignored", zap.String("event_id", event.ID), zap.String("type", event.Type))
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, s.paymentRepository.MarkWebhookProcessed(stored.ID, now)
+}
+
+func (s *PaymentService) applyPaymentEvent(ctx context.Context, event *payments.WebhookEvent, now time.Time) error {
+	attempt, err := s.paymentRepository.FindAttemptByReference(event.Reference)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.Warn("Payment webhook for an unknown attempt", zap.String("event_id", event.ID), zap.String("reference", event.Reference))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if event.Type == payments.EventPaymentFailed {
+		reason := event.FailureReason
+		if reason == "" {
+			reason = PAYMENT_FAILED
+		}
+		_, err := s.paymentRepository.FailAttempt(attempt.ID, reason)
+		return err
+	}
+	order, err := s.findOrder(attempt.OrderID)
+	if err != nil {
+		return err
+	}
+	return s.capture(ctx, order, attempt, event.ProviderRef, now)
+}
+
+func (s *PaymentService) applyRefundEvent(ctx context.Context, event *payments.WebhookEvent, now time.Time) error {
+	refund, err := s.paymentRepository.FindRefundByReference(event.Reference)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.Warn("Refund webhook for an unknown refund", zap.String("event_id", event.ID), zap.String("reference", event.Reference))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if event.Type == payments.EventRefundFailed {
+		reason := event.FailureReason
+		if reason == "" {
+			reason = REFUND_FAILED
+		}
+		_, err := s.paymentRepository.FailRefund(refund.ID, event.ProviderRef, reason)
+		return err
+	}
+	return s.settleRefund(ctx, refund, event.ProviderRef, now)
+}
+
+// sell places an order of a term for a customer of the crew, and pays it when
+// a method is given. Without one the order stays pending for the customer or
+// the desk to pay later. Staff are selling, so the desk methods are allowed.
+// The method is checked before the order is stored, so a refused one leaves
+// no order behind.
+func (s *PaymentService) sell(ctx context.Context, crew *models.FitCrew, customer *models.Customer, planID *uint, method string, by uint) (*models.PaymentOrder, error) {
+	order, err := s.newOrder(ctx, crew, customer, planID, by)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSale(order, method); err != nil {
+		return nil, err
+	}
+	if err := s.createOrder(order, customer); err != nil {
+		return nil, err
+	}
+	return s.settleSale(ctx, order, method, by)
+}
+
+// checkSale checks the method a sale by staff is to be paid with, if any
+func (s *PaymentService) checkSale(order *models.PaymentOrder, method string) error {
+	if strings.TrimSpace(method) == "" {
+		return nil
+	}
+	_, err := paymentGateway(s.gateway, order, method, true)
+	return err
+}
+
+// settleSale pays a stored sale with the method, if any
+func (s *PaymentService) settleSale(ctx context.Context, order *models.PaymentOrder, method string, by uint) (*models.PaymentOrder, error) {
+	if strings.TrimSpace(method) == "" {
+		return order, nil
+	}
+	return s.pay(ctx, order, method, by, true)
+}
+
+// placeOrder stores a pending order copying the plan as it is sold at the crew today
+func (s *PaymentService) placeOrder(ctx context.Context, crew *models.FitCrew, customer *models.Customer, planID *uint, createdBy uint) (*models.PaymentOrder, error) {
+	order, err := s.newOrder(ctx, crew, customer, planID, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.createOrder(order, customer); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *PaymentService) createOrder(order *models.PaymentOrder, customer *models.Customer) error {
+	if err := s.paymentRepository.CreateOrder(order); err != nil {
+		return err
+	}
+	order.Customer = *customer
+	return nil
+}
+
+// newOrder returns a pending order copying the plan as it is sold at the crew
+// today, without storing it
+func (s *PaymentService) newOrder(ctx context.Context, crew *models.FitCrew, customer *models.Customer, planID *uint, createdBy uint) (*models.PaymentOrder, error) {
+	if !crew.IsActive {
+		return nil, fmt.Errorf("%w: crew is inactive", ErrInvalidPayment)
+	}
+	if planID == nil {
+		planID = customer.PlanID
+	}
+	if planID == nil {
+		return nil, fmt.Errorf("%w: plan_id is required", ErrInvalidPayment)
+	}
+	plan, err := s.planService.PlanForCrew(ctx, *planID, crew)
+	if err != nil {
+		return nil, err
+	}
+	tax := plan.TaxMinor()
+	order := &models.PaymentOrder{
+		CustomerID:   customer.ID,
+		CrewID:       int(crew.ID),
+		AllieID:      crew.AllieID,
+		Status:       ORDER_STATUS_PENDING,
+		PlanID:       plan.ID,
+		PlanName:     plan.Name,
+		DurationDays: plan.DurationDays,
+		FreezeDays:   plan.FreezeDays,
+		VisitLimit:   plan.VisitLimit,
+		VisitsPerDay: plan.VisitsPerDay,
+		PriceMinor:   plan.PriceMinor,
+		TaxRateBps:   plan.TaxRateBps,
+		TaxMinor:     tax,
+		AmountMinor:  plan.PriceMinor + tax,
+		Currency:     plan.Currency,
+		CreatedBy:    createdBy,
+	}
+	return order, nil
+}
+
+// paymentGateway returns the gateway an order is paid through with the method.
+// Desk methods are only accepted from staff: cash for paid orders,
+// complimentary for free ones. Free orders can only be paid at the desk.
+func paymentGateway(gateway payments.PaymentGateway, order *models.PaymentOrder, method string, staff bool) (string, error) {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		return "", ErrInvalidPaymentMethod
+	}
+	switch method {
+	case DeskMethodCash, DeskMethodComplimentary:
+		if !staff {
+			return "", fmt.Errorf("%w: %s is recorded by the crew's staff", ErrInvalidPaymentMethod, method)
+		}
+		if (method == DeskMethodComplimentary) != (order.AmountMinor == 0) {
+			return "", fmt.Errorf("%w: complimentary is for free plans and cash for paid ones", ErrInvalidPaymentMethod)
+		}
+		return DeskGateway, nil
+	default:
+		if order.AmountMinor == 0 {
+			return "", fmt.Errorf("%w: the plan is free; record it as %s", ErrInvalidPaymentMethod, DeskMethodComplimentary)
+		}
+		return gateway.Name(), nil
+	}
+}
+
+// pay charges a pending order through the gateway. A charge captured at once
+// activates the term right away; a pending one waits for the gateway's webhook.
+// Desk methods are only accepted from staff and are captured at once: cash for
+// paid orders, complimentary for free ones.
+func (s *PaymentService) pay(ctx context.Context, order *models.PaymentOrder, method string, by uint, staff bool) (*models.PaymentOrder, error) {
+	gateway, err := paymentGateway(s.gateway, order, method, staff)
+	if err != nil {
+		return nil, err
+	}
+	method = strings.ToLower(strings.TrimSpace(method))
+	reference, err := paymentReference("pay")
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := &models.PaymentAttempt{
+		OrderID:     order.ID,
+		Gateway:     gateway,
+		Reference:   reference,
+		Method:      method,
+		AmountMinor: order.AmountMinor,
+		Currency:    order.Currency,
+		Status:      ATTEMPT_STATUS_PENDING,
+		CreatedBy:   by,
+	}
+	outcome, err := s.paymentRepository.CreateAttempt(attempt)
+	if err != nil {
+		return nil, err
+	}
+	switch outcome {
+	case repository.PaymentSettled:
+		return nil, ErrOrderNotPayable
+	case repository.PaymentInProgress:
+		return nil, ErrPaymentInProgress
+	}
+	if gateway == DeskGateway {
+		if err := s.capture(ctx, order, attempt, "", time.Now()); err != nil {
+			return nil, err
+		}
+		return s.findOrder(order.ID)
+	}
+
+	charge, err := s.gateway.Charge(ctx, payments.ChargeRequest{
+		Reference:   attempt.Reference,
+		AmountMinor: attempt.AmountMinor,
+		Currency:    attempt.Currency,
+		Method:      method,
+		Description: fmt.Sprintf("%s membership, order #%d", order.PlanName, order.ID),
+	})
+	if err != nil {
+		if _, failErr := s.paymentRepository.FailAttempt(attempt.ID, err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		if errors.Is(err, payments.ErrInvalidMethod) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPaymentMethod, method)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+	}
+
+	switch charge.Status {
+	case payments.ChargeCaptured:
+		if err := s.capture(ctx, order, attempt, charge.ProviderRef, time.Now()); err != nil {
+			return nil, err
+		}
+	case payments.ChargePending:
+		if err := s.paymentRepository.SetAttemptProviderRef(attempt.ID, charge.ProviderRef); err != nil {
+			return nil, err
+		}
+	default:
+		reason := charge.FailureReason
+		if reason == "" {
+			reason = PAYMENT_FAILED
+		}
+		if _, err := s.paymentRepository.FailAttempt(attempt.ID, reason); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrPaymentFailed, reason)
+	}
+	return s.findOrder(order.ID)
+}
+
+// capture records the captured payment of an attempt, adds the bought term to
+// the customer's membership and invoices the order. Capturing an attempt twice
+// changes nothing.
+func (s *PaymentService) capture(ctx context.Context, order *models.PaymentOrder, attempt *models.PaymentAttempt, providerRef string, now time.Time) error {
+	entries := captureEntries(order, attempt, now)
+	outcome, err := s.paymentRepository.CaptureAttempt(attempt.ID, providerRef, now, entries, func(customer *models.Customer) *models.MembershipHistory {
+		history := renewTerm(customer, order.Plan(), now)
+		customer.IsActive = customer.MembershipActiveAt(now)
+		history.ToCrewID = customer.CrewID
+		history.Note = fmt.Sprintf("Paid by order #%d", order.ID)
+		history.PerformedBy = order.CreatedBy
+		return history
+	})
+	if err != nil {
+		return err
+	}
+	if outcome == repository.PaymentSettled {
+		// money the gateway took for an attempt we already gave up on has to be returned by hand
+		logging.Log.Warn("Capture of a settled payment attempt ignored",
+			zap.Uint("order_id", order.ID),
+			zap.String("reference", attempt.Reference),
+			zap.String("provider_ref", providerRef),
+		)
+		return nil
+	}
+	logging.Log.Info("Payment captured",
+		zap.Uint("order_id", order.ID),
+		zap.Uint("customer_id", order.CustomerID),
+		zap.Int64("amount_minor", order.AmountMinor),
+	)
+	s.issueDocuments(ctx, order.ID)
+	return nil
+}
+
+// settleRefund records a refund the gateway completed and issues its credit note
+func (s *PaymentService) settleRefund(ctx context.Context, refund *models.PaymentRefund, providerRef string, now time.Time) error {
+	order, err := s.findOrder(refund.OrderID)
+	if err != nil {
+		return err
+	}
+	outcome, err := s.paymentRepository.SettleRefund(refund.ID, providerRef, now, refundEntries(order, refund, now))
+	if err != nil {
+		return err
+	}
+	if outcome == repository.PaymentApplied {
+		s.issueDocuments(ctx, order.ID)
+	}
+	return nil
+}
+
+// issueDocuments issues the invoice or credit notes an order is missing once
+// money moved. A failure is logged, not returned: the payment stands, and staff
+// can issue the documents of the order again.
+func (s *PaymentService) issueDocuments(ctx context.Context, orderID uint) {
+	if _, err := s.invoiceService.IssueDocuments(ctx, orderID); err != nil {
+		logging.Log.Error("Failed to issue invoice documents", zap.Uint("order_id", orderID), zap.Error(err))
+	}
+}
+
+// captureEntries posts a captured payment: the gateway or the desk holds the
+// amount, the price is membership revenue and the tax is owed. A free order
+// moves no money and posts nothing.
+func captureEntries(order *models.PaymentOrder, attempt *models.PaymentAttempt, now time.Time) []models.LedgerEntry {
+	if order.AmountMinor == 0 {
+		return nil
+	}
+	ref := "capture:" + attempt.Reference
+	description := fmt.Sprintf("Payment of order #%d", order.ID)
+	entries := []models.LedgerEntry{
+		ledgerEntry(order, ref, holdingAccount(attempt.Gateway), order.AmountMinor, 0, description, now),
+		ledgerEntry(order, ref, LEDGER_MEMBERSHIP_REVENUE, 0, order.PriceMinor, description, now),
+	}
+	if order.TaxMinor > 0 {
+		entries = append(entries, ledgerEntry(order, ref, LEDGER_TAX_PAYABLE, 0, order.TaxMinor, description, now))
+	}
+	return entries
+}
+
+// refundEntries reverses a refunded amount, splitting it between revenue and
+// tax in the order's proportion. The tax share is taken cumulatively so the
+// refunds of an order never return more tax than was collected.
+func refundEntries(order *models.PaymentOrder, refund *models.PaymentRefund, now time.Time) []models.LedgerEntry {
+	taxBefore := proportion(order.RefundedMinor, order.TaxMinor, order.AmountMinor)
+	tax := proportion(order.RefundedMinor+refund.AmountMinor, order.TaxMinor, order.AmountMinor) - taxBefore
+
+	ref := refundTransactionRef(refund)
+	description := fmt.Sprintf("Refund of order #%d", order.ID)
+	entries := []models.LedgerEntry{
+		ledgerEntry(order, ref, LEDGER_MEMBERSHIP_REVENUE, refund.AmountMinor-tax, 0, description, now),
+	}
+	if tax > 0 {
+		entries = append(entries, ledgerEntry(order, ref, LEDGER_TAX_PAYABLE, tax, 0, description, now))
+	}
+	return append(entries, ledgerEntry(order, ref, holdingAccount(refund.Gateway), 0, refund.AmountMinor, description, now))
+}
+
+// holdingAccount returns the account holding the money taken through a gateway
+func holdingAccount(gateway string) LEDGERACCOUNT {
+	if gateway == DeskGateway {
+		return LEDGER_DESK_CASH
+	}
+	return LEDGER_GATEWAY_CLEARING
+}
+
+// refundTransactionRef returns the ledger transaction that posts a refund
+func refundTransactionRef(refund *models.PaymentRefund) string {
+	return "refund:" + refund.Reference
+}
+
+func ledgerEntry(order *models.PaymentOrder, ref string, account LEDGERACCOUNT, debit, credit int64, description string, now time.Time) models.LedgerEntry {
+	return models.LedgerEntry{
+		TransactionRef: ref,
+		Account:        account,
+		OrderID:        order.ID,
+		AllieID:        order.AllieID,
+		CrewID:         order.CrewID,
+		DebitMinor:     debit,
+		CreditMinor:    credit,
+		Currency:       order.Currency,
+		Description:    description,
+		PostedAt:       now,
+	}
+}
+
+// proportion returns amount * part / whole rounded half up
+func proportion(amount, part, whole int64) int64 {
+	if whole == 0 {
+		return 0
+	}
+	return (2*amount*part + whole) / (2 * whole)
+}
+
+// crewOrder returns an order of the crew when the requester may manage the crew
+func (s *PaymentService) crewOrder(ctx context.Context, requester Requester, crewID, orderID uint) (*models.PaymentOrder, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+	order, err := s.findOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if uint(order.CrewID) != crewID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *PaymentService) findOrder(id uint) (*models.PaymentOrder, error) {
+	order, err := s.paymentRepository.FindOrderByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	return order, err
+}
+
+func orderFilters(filter dtos.OrderFilter) (map[string]interface{}, error) {
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if filter.Status != "" {
+		status := ORDERSTATUS(strings.ToUpper(strings.TrimSpace(filter.Status)))
+		switch status {
+		case ORDER_STATUS_PENDING, ORDER_STATUS_PAID, ORDER_STATUS_PARTIALLY_REFUNDED, ORDER_STATUS_REFUNDED:
+		default:
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidPayment, filter.Status)
+		}
+		filters["status"] = map[string]interface{}{"Op": "eq", "value": status}
+	}
+	return filters, nil
+}
+
+// paymentReference returns a unique reference we give the gateway
+func paymentReference(prefix string) (string, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	return prefix + "_" + id, nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/payments"
+	. "backend/internal/resources/constants"
+)
+
+// ledgerOrder is an order of price plus tax as a crew sells it
+func ledgerOrder(price, tax int64) *models.PaymentOrder {
+	order := &models.PaymentOrder{
+		AllieID:     2,
+		CrewID:      3,
+		PriceMinor:  price,
+		TaxMinor:    tax,
+		AmountMinor: price + tax,
+		Currency:    DefaultCurrency,
+	}
+	order.ID = 11
+	return order
+}
+
+// checkBalanced fails the test unless the entries form one transaction whose
+// debits equal its credits, and returns the net debit of every account
+func checkBalanced(t *testing.T, entries []models.LedgerEntry) map[LEDGERACCOUNT]int64 {
+	t.Helper()
+	var debits, credits int64
+	net := make(map[LEDGERACCOUNT]int64)
+	for _, entry := range entries {
+		if entry.TransactionRef != entries[0].TransactionRef {
+			t.Fatalf("entries of transactions %s and %s mixed", entries[0].TransactionRef, entry.TransactionRef)
+		}
+		if entry.DebitMinor < 0 || entry.CreditMinor < 0 {
+			t.Fatalf("negative amount in %+v", entry)
+		}
+		debits += entry.DebitMinor
+		credits += entry.CreditMinor
+		net[entry.Account] += entry.DebitMinor - entry.CreditMinor
+	}
+	if debits != credits {
+		t.Fatalf("debits %d, credits %d", debits, credits)
+	}
+	return net
+}
+
+func TestCaptureEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		price, tax int64
+		gateway    string
+		holding    LEDGERACCOUNT
+	}{
+		{"taxed card payment", 1000, 180, "local", LEDGER_GATEWAY_CLEARING},
+		{"untaxed card payment", 1000, 0, "local", LEDGER_GATEWAY_CLEARING},
+		{"cash at the desk", 99999, 18000, DeskGateway, LEDGER_DESK_CASH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := ledgerOrder(tt.price, tt.tax)
+			entries := captureEntries(order, &models.PaymentAttempt{Gateway: tt.gateway, Reference: "pay_1"}, time.Now())
+			net := checkBalanced(t, entries)
+
+			if net[tt.holding] != order.AmountMinor {
+				t.Errorf("%s debited %d, want %d", tt.holding, net[tt.holding], order.AmountMinor)
+			}
+			if net[LEDGER_MEMBERSHIP_REVENUE] != -tt.price {
+				t.Errorf("revenue credited %d, want %d", -net[LEDGER_MEMBERSHIP_REVENUE], tt.price)
+			}
+			if net[LEDGER_TAX_PAYABLE] != -tt.tax {
+				t.Errorf("tax credited %d, want %d", -net[LEDGER_TAX_PAYABLE], tt.tax)
+			}
+		})
+	}
+}
+
+func TestCaptureEntriesOfFreeOrder(t *testing.T) {
+	entries := captureEntries(ledgerOrder(0, 0), &models.PaymentAttempt{Gateway: DeskGateway, Reference: "pay_1"}, time.Now())
+	if len(entries) != 0 {
+		t.Errorf("a free order posted %d entries, want none", len(entries))
+	}
+}
+
+// refundAll posts the refunds of the order one after the other, checking each
+// transaction balances, and returns the tax they gave back in total
+func refundAll(t *testing.T, order *models.PaymentOrder, gateway string, amounts []int64) int64 {
+	t.Helper()
+	var tax int64
+	for i, amount := range amounts {
+		refund := &models.PaymentRefund{Gateway: gateway, Reference: "re_" + string(rune('a'+i)), AmountMinor: amount}
+		net := checkBalanced(t, refundEntries(order, refund, time.Now()))
+
+		if credit := -net[holdingAccount(gateway)]; credit != amount {
+			t.Fatalf("refund %d credited %s with %d, want %d", i, holdingAccount(gateway), credit, amount)
+		}
+		if net[LEDGER_TAX_PAYABLE] < 0 || net[LEDGER_MEMBERSHIP_REVENUE] < 0 {
+			t.Fatalf("refund %d credited revenue or tax: %v", i, net)
+		}
+		tax += net[LEDGER_TAX_PAYABLE]
+		if tax > order.TaxMinor {
+			t.Fatalf("refunds %v returned %d of tax after refund %d, more than the %d collected", amounts, tax, i, order.TaxMinor)
+		}
+		order.RefundedMinor += amount
+	}
+	return tax
+}
+
+func TestRefundEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		price, tax int64
+		gateway    string
+		refunds    []int64
+		wantTax    int64
+	}{
+		{"full refund", 1000, 180, "local", []int64{1180}, 180},
+		{"half refund", 1000, 180, "local", []int64{590}, 90},
+		{"thirds", 1000, 180, "local", []int64{393, 393, 394}, 180},
+		{"single paisa refunds", 2, 1, "local", []int64{1, 1, 1}, 1},
+		{"odd paisa split", 999, 179, "local", []int64{1, 588, 589}, 179},
+		{"untaxed", 1000, 0, "local", []int64{300, 700}, 0},
+		{"cash handed back at the desk", 1000, 180, DeskGateway, []int64{500, 680}, 180},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundAll(t, ledgerOrder(tt.price, tt.tax), tt.gateway, tt.refunds); got != tt.wantTax {
+				t.Errorf("refunds returned %d of tax, want %d", got, tt.wantTax)
+			}
+		})
+	}
+}
+
+func TestRefundEntriesNeverReturnMoreTaxThanCollected(t *testing.T) {
+	// every way of refunding the order in two parts, and in steps of each size
+	const price, tax = 101, 18
+	for first := int64(1); first < price+tax; first++ {
+		order := ledgerOrder(price, tax)
+		if got := refundAll(t, order, "local", []int64{first, price + tax - first}); got != tax {
+			t.Fatalf("refunding %d then the rest returned %d of tax, want %d", first, got, tax)
+		}
+
+		var steps []int64
+		for left := int64(price + tax); left > 0; left -= first {
+			steps = append(steps, min(first, left))
+		}
+		if got := refundAll(t, ledgerOrder(price, tax), "local", steps); got != tax {
+			t.Fatalf("refunding in steps of %d returned %d of tax, want %d", first, got, tax)
+		}
+	}
+}
+
+func TestProportion(t *testing.T) {
+	tests := []struct {
+		amount, part, whole int64
+		want                int64
+	}{
+		{590, 180, 1180, 90},
+		{1, 1, 2, 1}, // a half rounds up
+		{1, 1, 3, 0}, // a third rounds down
+		{2, 1, 3, 1}, // two thirds round up
+		{1180, 180, 1180, 180},
+		{0, 180, 1180, 0},
+		{500, 0, 1000, 0},
+		{500, 10, 0, 0}, // nothing to share
+	}
+
+	for _, tt := range tests {
+		if got := proportion(tt.amount, tt.part, tt.whole); got != tt.want {
+			t.Errorf("proportion(%d, %d, %d) = %d, want %d", tt.amount, tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
+
+func TestPaymentGateway(t *testing.T) {
+	gateway := payments.NewLocalGateway("")
+	paid, free := ledgerOrder(1000, 180), ledgerOrder(0, 0)
+
+	tests := []struct {
+		name   string
+		order  *models.PaymentOrder
+		method string
+		staff  bool
+		want   string
+	}{
+		{"card by the customer", paid, "card", false, "local"},
+		{"card by staff", paid, " Card ", true, "local"},
+		{"cash by staff", paid, "cash", true, DeskGateway},
+		{"complimentary free plan by staff", free, "complimentary", true, DeskGateway},
+		{"cash by the customer", paid, "cash", false, ""},
+		{"complimentary by the customer", free, "complimentary", false, ""},
+		{"cash for a free plan", free, "cash", true, ""},
+		{"complimentary for a paid plan", paid, "complimentary", true, ""},
+		{"card for a free plan", free, "card", true, ""},
+		{"no method", paid, " ", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paymentGateway(gateway, tt.order, tt.method, tt.staff)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidPaymentMethod) {
+					t.Errorf("error = %v, want %v", err, ErrInvalidPaymentMethod)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("paymentGateway = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSaleWithoutMethod(t *testing.T) {
+	service := &PaymentService{gateway: payments.NewLocalGateway("")}
+	if err := service.checkSale(ledgerOrder(1000, 180), ""); err != nil {
+		t.Errorf("a sale left to pay later was refused: %v", err)
+	}
+	if err := service.checkSale(ledgerOrder(0, 0), "card"); !errors.Is(err, ErrInvalidPaymentMethod) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidPaymentMethod)
+	}
+}
@@ -2,23 +2,107 @@
 package services
 
 import (
+	"time"
+
+	"backend/internal/notifications"
+	"backend/internal/payments"
 	"backend/internal/repository"
 	"gorm.io/gorm"
 )
 
+// Settings carries the configuration values services depend on
+type Settings struct {
+	Lockout          LockoutPolicy
+	PasswordResetTTL time.Duration
+	OTP              OTPPolicy
+	MFA              MFAPolicy
+	ImpersonationTTL time.Duration
+	CheckInCodeTTL   time.Duration           // lifetime of the QR codes customers show at kiosks
+	PaymentGateway   payments.PaymentGateway // takes the payments of orders, see config.NewPaymentGateway
+	Notifier         notifications.Notifier  // delivers password reset emails, see config.NewNotifier
+	SMSSender        notifications.SMSSender // delivers login codes and class notices, see config.NewSMSSender
+}
+
 type Services struct {
-	UserService *UserService
+	UserService           *UserService
+	AuthService           *AuthService
+	LockoutService        *LockoutService
+	PasswordService       *PasswordService
+	OTPService            *OTPService
+	MFAService            *MFAService
+	MenuService           *MenuService
+	ImpersonationService  *ImpersonationService
+	FitAllieService       *FitAllieService
+	FitCrewService        *FitCrewService
+	MembershipService     *MembershipService
+	MembershipPlanService *MembershipPlanService
+	TrainerService        *TrainerService
+	FitServiceService     *FitServiceService
+	CrewScheduleService   *CrewScheduleService
+	ClassService          *ClassService
+	BookingService        *BookingService
+	CheckInService        *CheckInService
+	PaymentService        *PaymentService
+	InvoiceService        *InvoiceService
+	SettlementService     *SettlementService
 	// OtherService    *OtherService  // Add more services if needed
 }
 
-func NewServices(gormDB *gorm.DB) *Services {
+func NewServices(gormDB *gorm.DB, settings Settings) *Services {
 	// Instantiate multiple repositories
 	userRepository := repository.NewUserRepository(gormDB)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(gormDB)
+	loginThrottleRepository := repository.NewLoginThrottleRepository(gormDB)
+	passwordResetRepository := repository.NewPasswordResetRepository(gormDB)
+	otpRepository := repository.NewOTPRepository(gormDB)
+	mfaRepository := repository.NewMFARepository(gormDB)
+	menuRepository := repository.NewMenuRepository(gormDB)
+	impersonationRepository := repository.NewImpersonationRepository(gormDB)
+	fitAllieRepository := repository.NewFitAllieRepository(gormDB)
+	fitCrewRepository := repository.NewFitCrewRepository(gormDB)
+	customerRepository := repository.NewCustomerRepository(gormDB)
+	membershipPlanRepository := repository.NewMembershipPlanRepository(gormDB)
+	trainerRepository := repository.NewTrainerRepository(gormDB)
+	fitServiceRepository := repository.NewFitServiceRepository(gormDB)
+	crewScheduleRepository := repository.NewCrewScheduleRepository(gormDB)
+	classRepository := repository.NewClassRepository(gormDB)
+	bookingRepository := repository.NewBookingRepository(gormDB)
+	crewVisitRepository := repository.NewCrewVisitRepository(gormDB)
+	paymentRepository := repository.NewPaymentRepository(gormDB)
+	invoiceRepository := repository.NewInvoiceRepository(gormDB)
+	settlementRepository := repository.NewSettlementRepository(gormDB)
 	// otherRepository := repository.NewOtherRepository(gormDB) // Another repository instance
 
+	lockoutService := NewLockoutService(loginThrottleRepository, settings.Lockout)
+	fitAllieService := NewFitAllieService(fitAllieRepository, userRepository)
+	fitCrewService := NewFitCrewService(fitCrewRepository, fitAllieService)
+	membershipPlanService := NewMembershipPlanService(membershipPlanRepository, fitAllieService, fitCrewService)
+	invoiceService := NewInvoiceService(invoiceRepository, paymentRepository, customerRepository, fitCrewRepository, fitAllieService, fitCrewService)
+	paymentService := NewPaymentService(paymentRepository, customerRepository, fitCrewRepository, fitCrewService, membershipPlanService, invoiceService, settings.PaymentGateway)
+
 	// Pass multiple repositories into the services
 	return &Services{
-		UserService: NewUserService(userRepository),
+		UserService:           NewUserService(userRepository),
+		AuthService:           NewAuthService(userRepository, refreshTokenRepository, mfaRepository, lockoutService, settings.MFA.RequiredRoles),
+		LockoutService:        lockoutService,
+		PasswordService:       NewPasswordService(userRepository, passwordResetRepository, refreshTokenRepository, settings.Notifier, settings.PasswordResetTTL),
+		OTPService:            NewOTPService(userRepository, otpRepository, settings.SMSSender, settings.OTP),
+		MFAService:            NewMFAService(userRepository, mfaRepository, lockoutService, settings.MFA),
+		MenuService:           NewMenuService(menuRepository),
+		ImpersonationService:  NewImpersonationService(userRepository, impersonationRepository, settings.ImpersonationTTL),
+		FitAllieService:       fitAllieService,
+		FitCrewService:        fitCrewService,
+		MembershipService:     NewMembershipService(customerRepository, userRepository, fitCrewService, membershipPlanService, paymentService),
+		MembershipPlanService: membershipPlanService,
+		TrainerService:        NewTrainerService(trainerRepository, userRepository, fitCrewService),
+		FitServiceService:     NewFitServiceService(fitServiceRepository, fitAllieService, fitCrewService),
+		CrewScheduleService:   NewCrewScheduleService(crewScheduleRepository, fitCrewService),
+		ClassService:          NewClassService(classRepository, bookingRepository, trainerRepository, fitServiceRepository, fitCrewService, settings.SMSSender),
+		BookingService:        NewBookingService(bookingRepository, classRepository, customerRepository, fitCrewRepository, fitAllieService, fitCrewService, settings.SMSSender),
+		CheckInService:        NewCheckInService(crewVisitRepository, customerRepository, fitCrewRepository, trainerRepository, fitCrewService, settings.CheckInCodeTTL),
+		PaymentService:        paymentService,
+		InvoiceService:        invoiceService,
+		SettlementService:     NewSettlementService(settlementRepository, fitAllieService),
 		// OtherService: NewOtherService(otherRepository),
 	}
 }
@@ -0,0 +1,308 @@
+// internal/services/settlement_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/invoicing"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSettlementNotFound = errors.New(SETTLEMENT_NOT_FOUND)
+	ErrInvalidSettlement  = errors.New(INVALID_SETTLEMENT_INPUT)
+	ErrSettlementConflict = errors.New(SETTLEMENT_STATE_CONFLICT)
+	ErrNothingToSettle    = errors.New(NOTHING_TO_SETTLE)
+)
+
+// SettlementService settles the payments captured for an allie's crews. A
+// statement covers the ledger transactions posted over a period that no other
+// statement settled: the platform keeps the allie's commission rate of the
+// revenue, tax excluded, and the rest of what customers paid is paid out to the
+// allie. Refunds come off at the rate their payment was settled at.
+type SettlementService struct {
+	settlementRepository repository.SettlementRepositoryInterface
+	fitAllieService      *FitAllieService
+}
+
+func NewSettlementService(settlementRepository repository.SettlementRepositoryInterface, fitAllieService *FitAllieService) *SettlementService {
+	return &SettlementService{
+		settlementRepository: settlementRepository,
+		fitAllieService:      fitAllieService,
+	}
+}
+
+// GenerateStatement drafts the allie's statement for the days from and to,
+// both included, counted in Indian time. The period must be over, so no
+// payment of it is still to come.
+func (s *SettlementService) GenerateStatement(ctx context.Context, requester Requester, allieID uint, input dtos.GenerateSettlementRequest) (*models.SettlementStatement, error) {
+	allie, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID)
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseSettlementDate(input.From)
+	if err != nil {
+		return nil, err
+	}
+	last, err := parseSettlementDate(input.To)
+	if err != nil {
+		return nil, err
+	}
+	end := last.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: the period must not end before it starts", ErrInvalidSettlement)
+	}
+	if end.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the period has not ended yet", ErrInvalidSettlement)
+	}
+
+	entries, err := s.settlementRepository.UnsettledEntries(allieID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	statement := &models.SettlementStatement{
+		AllieID:        int(allieID),
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Status:         SETTLEMENT_STATUS_DRAFT,
+		CommissionRate: allie.CommissionRate,
+		CreatedBy:      requester.UserID,
+	}
+	statement.Lines, err = s.settlementLines(entries, allie.CommissionRate)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Lines) == 0 {
+		return nil, ErrNothingToSettle
+	}
+	statement.Currency = statement.Lines[0].Currency
+	statement.Total()
+
+	outcome, err := s.settlementRepository.CreateStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == repository.SettlementAlreadyDone {
+		return nil, fmt.Errorf("%w: a payment of the period was settled meanwhile, generate the statement again", ErrSettlementConflict)
+	}
+	return statement, nil
+}
+
+// GetStatement returns a statement of the allie with its lines
+func (s *SettlementService) GetStatement(ctx context.Context, requester Requester, allieID, statementID uint) (*models.SettlementStatement, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, err
+	}
+	return s.allieStatement(allieID, statementID)
+}
+
+// ListStatements returns a page of the allie's statements, latest period first
+func (s *SettlementService) ListStatements(ctx context.Context, requester Requester, allieID uint, filter dtos.SettlementFilter) ([]models.SettlementStatement, int64, error) {
+	if _, err := s.fitAllieService.AuthorizeAllie(ctx, requester, allieID); err != nil {
+		return nil, 0, err
+	}
+	filter.AllieID = &allieID
+	filters, err := settlementFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.settlementRepository.List(filters)
+}
+
+// ListAllStatements returns a page of the statements of every allie, such as
+// the approved ones finance has to pay out
+func (s *SettlementService) ListAllStatements(ctx context.Context, filter dtos.SettlementFilter) ([]models.SettlementStatement, int64, error) {
+	filters, err := settlementFilters(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.settlementRepository.List(filters)
+}
+
+// ApproveStatement approves a draft statement of the allie for payout
+func (s *SettlementService) ApproveStatement(ctx context.Context, requester Requester, allieID, statementID uint) (*models.SettlementStatement, error) {
+	if _, err := s.GetStatement(ctx, requester, allieID, statementID); err != nil {
+		return nil, err
+	}
+	outcome, err := s.settlementRepository.Approve(statementID, requester.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if outcome == repository.SettlementStatusChanged {
+		return nil, fmt.Errorf("%w: only draft statements can be approved", ErrSettlementConflict)
+	}
+	return s.allieStatement(allieID, statementID)
+}
+
+// MarkStatementPaid records that an approved statement of the allie was paid out
+func (s *SettlementService) MarkStatementPaid(ctx context.Context, requester Requester, allieID, statementID uint, input dtos.MarkSettlementPaidRequest) (*models.SettlementStatement, error) {
+	reference := strings.TrimSpace(input.Reference)
+	if reference == "" {
+		return nil, fmt.Errorf("%w: the payout reference is required", ErrInvalidSettlement)
+	}
+	if _, err := s.GetStatement(ctx, requester, allieID, statementID); err != nil {
+		return nil, err
+	}
+	outcome, err := s.settlementRepository.MarkPaid(statementID, requester.UserID, reference, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if outcome == repository.SettlementStatusChanged {
+		return nil, fmt.Errorf("%w: only approved statements can be marked paid", ErrSettlementConflict)
+	}
+	return s.allieStatement(allieID, statementID)
+}
+
+// DiscardStatement deletes a draft statement of the allie, leaving its
+// payments to be settled again
+func (s *SettlementService) DiscardStatement(ctx context.Context, requester Requester, allieID, statementID uint) error {
+	if _, err := s.GetStatement(ctx, requester, allieID, statementID); err != nil {
+		return err
+	}
+	outcome, err := s.settlementRepository.Discard(statementID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSettlementNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if outcome == repository.SettlementStatusChanged {
+		return fmt.Errorf("%w: only draft statements can be discarded", ErrSettlementConflict)
+	}
+	return nil
+}
+
+// settlementLines turns ledger entries into a line per captured payment or
+// refund. Payments are charged the allie's current rate; a refund is charged
+// the rate of its payment's line, on this statement or an earlier one, and
+// the current rate when its payment was never settled. Money taken at the desk
+// is already with the allie, so it is left out of the payout while its
+// commission is still charged.
+func (s *SettlementService) settlementLines(entries []models.LedgerEntry, rate int) ([]models.SettlementLine, error) {
+	var lines []models.SettlementLine
+	index := make(map[string]int)
+	desk := make(map[int]int64)
+	var refundOrders []uint
+	for _, entry := range entries {
+		var kind SETTLEMENTLINEKIND
+		switch {
+		case strings.HasPrefix(entry.TransactionRef, "capture:"):
+			kind = SETTLEMENT_LINE_PAYMENT
+		case strings.HasPrefix(entry.TransactionRef, "refund:"):
+			kind = SETTLEMENT_LINE_REFUND
+		default:
+			continue
+		}
+		i, ok := index[entry.TransactionRef]
+		if !ok {
+			if len(lines) > 0 && lines[0].Currency != entry.Currency {
+				return nil, fmt.Errorf("%w: the period has payments in %s and %s, which cannot be settled together", ErrInvalidSettlement, lines[0].Currency, entry.Currency)
+			}
+			i = len(lines)
+			index[entry.TransactionRef] = i
+			lines = append(lines, models.SettlementLine{
+				CrewID:         entry.CrewID,
+				OrderID:        entry.OrderID,
+				TransactionRef: entry.TransactionRef,
+				Kind:           kind,
+				PostedAt:       entry.PostedAt,
+				Currency:       entry.Currency,
+			})
+			if kind == SETTLEMENT_LINE_REFUND {
+				refundOrders = append(refundOrders, entry.OrderID)
+			}
+		}
+
+		line := &lines[i]
+		switch entry.Account {
+		case LEDGER_GATEWAY_CLEARING:
+			line.GrossMinor += entry.DebitMinor - entry.CreditMinor
+		case LEDGER_DESK_CASH:
+			line.GrossMinor += entry.DebitMinor - entry.CreditMinor
+			desk[i] += entry.DebitMinor - entry.CreditMinor
+		case LEDGER_MEMBERSHIP_REVENUE:
+			line.RevenueMinor += entry.CreditMinor - entry.DebitMinor
+		case LEDGER_TAX_PAYABLE:
+			line.TaxMinor += entry.CreditMinor - entry.DebitMinor
+		}
+	}
+
+	rates, err := s.settlementRepository.PaymentRates(refundOrders)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if line.Kind == SETTLEMENT_LINE_PAYMENT {
+			rates[line.OrderID] = rate
+		}
+	}
+	for i := range lines {
+		line := &lines[i]
+		line.CommissionRate = rate
+		if paid, ok := rates[line.OrderID]; ok && line.Kind == SETTLEMENT_LINE_REFUND {
+			line.CommissionRate = paid
+		}
+		line.CommissionMinor = commission(line.RevenueMinor, line.CommissionRate)
+		line.PayoutMinor = line.GrossMinor - desk[i] - line.CommissionMinor
+	}
+	return lines, nil
+}
+
+// allieStatement returns a statement when it belongs to the allie
+func (s *SettlementService) allieStatement(allieID, statementID uint) (*models.SettlementStatement, error) {
+	statement, err := s.settlementRepository.FindByID(statementID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSettlementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(statement.AllieID) != allieID {
+		return nil, ErrSettlementNotFound
+	}
+	return statement, nil
+}
+
+// commission charges a percentage of revenue, rounding half a paisa away from
+// zero so a full refund takes back exactly what its payment was charged
+func commission(revenue int64, rate int) int64 {
+	if revenue < 0 {
+		return -proportion(-revenue, int64(rate), 100)
+	}
+	return proportion(revenue, int64(rate), 100)
+}
+
+// parseSettlementDate parses a YYYY-MM-DD day as its start in Indian time
+func parseSettlementDate(value string) (time.Time, error) {
+	date, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(value), invoicing.IST)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: dates must be YYYY-MM-DD", ErrInvalidSettlement)
+	}
+	return date, nil
+}
+
+func settlementFilters(filter dtos.SettlementFilter) (map[string]interface{}, error) {
+	filters := map[string]interface{}{
+		"offset": filter.Page,
+		"limit":  filter.Limit,
+	}
+	if filter.Status != "" {
+		status := SETTLEMENTSTATUS(strings.ToUpper(strings.TrimSpace(filter.Status)))
+		if status != SETTLEMENT_STATUS_DRAFT && status != SETTLEMENT_STATUS_APPROVED && status != SETTLEMENT_STATUS_PAID {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidSettlement, filter.Status)
+		}
+		filters["status"] = map[string]interface{}{"Op": "eq", "value": status}
+	}
+	if filter.AllieID != nil {
+		filters["allie_id"] = map[string]interface{}{"Op": "eq", "value": *filter.AllieID}
+	}
+	return filters, nil
+}
@@ -0,0 +1,323 @@
+// internal/services/trainer_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/dtos"
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/repository"
+	. "backend/internal/resources/constants"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTrainerNotFound  = errors.New(TRAINER_NOT_FOUND)
+	ErrInvalidTrainer   = errors.New(INVALID_TRAINER_INPUT)
+	ErrTrainerUserTaken = errors.New(TRAINER_USER_TAKEN)
+)
+
+type TrainerService struct {
+	trainerRepository repository.TrainerRepositoryInterface
+	userRepository    repository.UserRepositoryInterface
+	fitCrewService    *FitCrewService
+}
+
+func NewTrainerService(trainerRepository repository.TrainerRepositoryInterface, userRepository repository.UserRepositoryInterface, fitCrewService *FitCrewService) *TrainerService {
+	return &TrainerService{
+		trainerRepository: trainerRepository,
+		userRepository:    userRepository,
+		fitCrewService:    fitCrewService,
+	}
+}
+
+// CreateTrainer adds a trainer to the crew. The trainer may be linked to an
+// existing GYMSTAFF user, or get a new one that must change its password at
+// first login.
+func (s *TrainerService) CreateTrainer(ctx context.Context, requester Requester, crewID uint, input dtos.CreateTrainerRequest) (*models.TrainerProfile, error) {
+	crew, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID)
+	if err != nil {
+		return nil, err
+	}
+	if input.UserID != nil && input.Account != nil {
+		return nil, fmt.Errorf("%w: give either user_id or account, not both", ErrInvalidTrainer)
+	}
+
+	trainer := &models.TrainerProfile{
+		CrewID:          int(crew.ID),
+		FullName:        strings.TrimSpace(input.FullName),
+		Email:           strings.TrimSpace(input.Email),
+		Mobile:          strings.TrimSpace(input.Mobile),
+		AlternateMobile: input.AlternateMobile,
+		IsActive:        true,
+		CrewInactive:    !crew.IsActive,
+	}
+	if err := s.applyDetails(trainer, input.ExpStartedFrom, input.SpecializationIDs, input.Certifications); err != nil {
+		return nil, err
+	}
+
+	var account *models.User
+	switch {
+	case input.UserID != nil:
+		if err := s.linkUser(trainer, *input.UserID); err != nil {
+			return nil, err
+		}
+	case input.Account != nil:
+		if account, err = s.newStaffUser(trainer, input.Account, requester.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.trainerRepository.Create(trainer, account); err != nil {
+		return nil, err
+	}
+	if account != nil {
+		logging.Log.Info("Trainer created with staff account",
+			zap.Uint("trainer_id", trainer.ID),
+			zap.Uint("user_id", account.ID),
+			zap.Uint("created_by", requester.UserID),
+		)
+	}
+	return trainer, nil
+}
+
+// GetTrainer returns a trainer of the crew when the requester may manage the crew
+func (s *TrainerService) GetTrainer(ctx context.Context, requester Requester, crewID, trainerID uint) (*models.TrainerProfile, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, err
+	}
+
+	trainer, err := s.trainerRepository.FindByID(trainerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTrainerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint(trainer.CrewID) != crewID {
+		return nil, ErrTrainerNotFound
+	}
+	return trainer, nil
+}
+
+// ListTrainers returns a page of the crew's trainers. Experience bounds are in
+// full years as of today.
+func (s *TrainerService) ListTrainers(ctx context.Context, requester Requester, crewID uint, filter dtos.TrainerFilter) ([]models.TrainerProfile, int64, error) {
+	if _, err := s.fitCrewService.AuthorizeCrew(ctx, requester, crewID); err != nil {
+		return nil, 0, err
+	}
+	if filter.MinExperience != nil && filter.MaxExperience != nil && *filter.MinExperience > *filter.MaxExperience {
+		return nil, 0, fmt.Errorf("%w: min_experience cannot exceed max_experience", ErrInvalidTrainer)
+	}
+
+	options := repository.TrainerListOptions{
+		Filters: map[string]interface{}{
+			"offset": filter.Page,
+			"limit":  filter.Limit,
+		},
+		Search:           strings.TrimSpace(filter.Search),
+		SpecializationID: filter.SpecializationID,
+	}
+	if filter.IsActive != nil {
+		options.Filters["is_active"] = map[string]interface{}{"Op": "eq", "value": *filter.IsActive}
+	}
+	if filter.VisibleOnly {
+		options.Filters["is_active"] = map[string]interface{}{"Op": "eq", "value": true}
+		options.Filters["crew_inactive"] = map[string]interface{}{"Op": "eq", "value": false}
+	}
+
+	today := startOfDay(time.Now())
+	if filter.MinExperience != nil {
+		startedBy := today.AddDate(-*filter.MinExperience, 0, 0)
+		options.StartedOnOrBefore = &startedBy
+	}
+	if filter.MaxExperience != nil {
+		startedAfter := today.AddDate(-(*filter.MaxExperience + 1), 0, 0)
+		options.StartedAfter = &startedAfter
+	}
+
+	return s.trainerRepository.List(crewID, options)
+}
+
+func (s *TrainerService) UpdateTrainer(ctx context.Context, requester Requester, crewID, trainerID uint, input dtos.UpdateTrainerRequest) (*models.TrainerProfile, error) {
+	trainer, err := s.GetTrainer(ctx, requester, crewID, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	trainer.FullName = strings.TrimSpace(input.FullName)
+	trainer.Email = strings.TrimSpace(input.Email)
+	trainer.Mobile = strings.TrimSpace(input.Mobile)
+	trainer.AlternateMobile = input.AlternateMobile
+	if input.IsActive != nil {
+		trainer.IsActive = *input.IsActive
+	}
+	if err := s.applyDetails(trainer, input.ExpStartedFrom, input.SpecializationIDs, input.Certifications); err != nil {
+		return nil, err
+	}
+
+	if input.UserID != nil {
+		if *input.UserID == 0 {
+			trainer.UserID = nil
+		} else if trainer.UserID == nil || *trainer.UserID != *input.UserID {
+			if err := s.linkUser(trainer, *input.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.trainerRepository.Update(trainer); err != nil {
+		return nil, err
+	}
+	return trainer, nil
+}
+
+func (s *TrainerService) DeleteTrainer(ctx context.Context, requester Requester, crewID, trainerID uint) error {
+	if _, err := s.GetTrainer(ctx, requester, crewID, trainerID); err != nil {
+		return err
+	}
+	return s.trainerRepository.Delete(trainerID)
+}
+
+// applyDetails validates and sets the experience start, specializations and
+// certifications shared by create and update
+func (s *TrainerService) applyDetails(trainer *models.TrainerProfile, expStartedFrom string, specializationIDs []uint, certifications []dtos.TrainerCertificationRequest) error {
+	if trainer.FullName == "" {
+		return fmt.Errorf("%w: full name is required", ErrInvalidTrainer)
+	}
+	if trainer.Mobile == "" {
+		return fmt.Errorf("%w: mobile is required", ErrInvalidTrainer)
+	}
+
+	startedFrom, err := parseTrainerDate("exp_started_from", expStartedFrom)
+	if err != nil {
+		return err
+	}
+	if startedFrom.After(time.Now()) {
+		return fmt.Errorf("%w: exp_started_from cannot be in the future", ErrInvalidTrainer)
+	}
+	trainer.ExpStartedFrom = startedFrom
+
+	specializations, err := s.trainerRepository.FindServices(specializationIDs)
+	if err != nil {
+		return err
+	}
+	if len(specializations) != len(uniqueIDs(specializationIDs)) {
+		return fmt.Errorf("%w: unknown specialization", ErrInvalidTrainer)
+	}
+	trainer.Specializations = specializations
+
+	trainer.Certifications = make([]models.TrainerCertification, 0, len(certifications))
+	for _, input := range certifications {
+		certification := models.TrainerCertification{
+			Name:         strings.TrimSpace(input.Name),
+			Issuer:       input.Issuer,
+			CredentialID: input.CredentialID,
+		}
+		if certification.Name == "" {
+			return fmt.Errorf("%w: certification name is required", ErrInvalidTrainer)
+		}
+		if input.IssuedOn != "" {
+			issuedOn, err := parseTrainerDate("issued_on", input.IssuedOn)
+			if err != nil {
+				return err
+			}
+			certification.IssuedOn = &issuedOn
+		}
+		if input.ExpiresOn != "" {
+			expiresOn, err := parseTrainerDate("expires_on", input.ExpiresOn)
+			if err != nil {
+				return err
+			}
+			certification.ExpiresOn = &expiresOn
+		}
+		if certification.IssuedOn != nil && certification.ExpiresOn != nil && !certification.ExpiresOn.After(*certification.IssuedOn) {
+			return fmt.Errorf("%w: certification must expire after it was issued", ErrInvalidTrainer)
+		}
+		trainer.Certifications = append(trainer.Certifications, certification)
+	}
+	return nil
+}
+
+// linkUser links an existing GYMSTAFF user that no other trainer uses
+func (s *TrainerService) linkUser(trainer *models.TrainerProfile, userID uint) error {
+	user, err := s.userRepository.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.UserType != GYMSTAFF {
+		return fmt.Errorf("%w: only GYMSTAFF users can be linked to a trainer", ErrInvalidTrainer)
+	}
+
+	linked, err := s.trainerRepository.FindByUserID(userID)
+	if err == nil && linked.ID != trainer.ID {
+		return ErrTrainerUserTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	trainer.UserID = &user.ID
+	return nil
+}
+
+// newStaffUser builds the GYMSTAFF user for a trainer, refusing logins already in use
+func (s *TrainerService) newStaffUser(trainer *models.TrainerProfile, account *dtos.TrainerAccountRequest, createdBy uint) (*models.User, error) {
+	names := strings.Fields(trainer.FullName)
+	if len(names) < 2 {
+		return nil, fmt.Errorf("%w: full name needs a first and last name to create the account", ErrInvalidTrainer)
+	}
+	firstName, lastName := names[0], strings.Join(names[1:], " ")
+	if len(firstName) > 25 || len(lastName) > 25 {
+		return nil, fmt.Errorf("%w: first and last name are limited to 25 characters for the account", ErrInvalidTrainer)
+	}
+	if trainer.Email == "" {
+		return nil, fmt.Errorf("%w: email is required to create the account", ErrInvalidTrainer)
+	}
+	for _, identifier := range []string{account.Username, trainer.Email, trainer.Mobile} {
+		_, err := s.userRepository.FindByLogin(identifier)
+		if err == nil {
+			return nil, ErrUserAlreadyRegistered
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(account.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	return &models.User{
+		FirstName:          firstName,
+		LastName:           lastName,
+		Email:              trainer.Email,
+		Mobile:             trainer.Mobile,
+		Username:           account.Username,
+		PasswordHash:       string(passwordHash),
+		UserType:           GYMSTAFF,
+		IsActive:           true,
+		MustChangePassword: true,
+		CreatedBy:          int(createdBy),
+		UpdatedBy:          int(createdBy),
+	}, nil
+}
+
+func parseTrainerDate(field, value string) (time.Time, error) {
+	date, err := time.ParseInLocation(membershipDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a YYYY-MM-DD date", ErrInvalidTrainer, field)
+	}
+	return date, nil
+}
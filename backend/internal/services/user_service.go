@@ -7,6 +7,7 @@ import (
 	"backend/internal/repository"
 	. "backend/internal/resources/constants"
 	"context"
+	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -23,18 +24,25 @@ func NewUserService(userRepository repository.UserRepositoryInterface) *UserServ
 	}
 }
 
-func (s *UserService) CreateUser(ctx context.Context, input dtos.CreateUserRequest) (*models.User, error) {
+// ErrUserAccessDenied is returned when the caller's role does not outrank the user's
+var ErrUserAccessDenied = errors.New(USER_ACCESS_DENIED)
+
+// CreateUser creates a user of a role the caller's role outranks
+func (s *UserService) CreateUser(ctx context.Context, callerRole USERROLE, input dtos.CreateUserRequest) (*models.User, error) {
+	userType := GetUserType(input.UserType)
+	if userType == INVALID {
+		return nil, fmt.Errorf("invalid user type: %s", input.UserType)
+	}
+	if !callerRole.CanManage(userType) {
+		return nil, ErrUserAccessDenied
+	}
+
 	// Hash the password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
 
-userType := GetUserType(input.UserType)
-if userType == INVALID {
-    return nil, fmt.Errorf("invalid user type: %s", input.UserType)
-}
-
 	user := &models.User{
 		FirstName:    input.FirstName,
 		MiddleName:   input.MiddleName,
@@ -44,6 +52,7 @@ if userType == INVALID {
 		Username:     input.Username,
 		PasswordHash: string(passwordHash),
 		UserType:     userType,
+		MustChangePassword: input.MustChangePassword,
 	}
 
 	if err := validateUser(user); err != nil {
@@ -62,12 +71,16 @@ func (s *UserService) GetUserByID(ctx context.Context, id int32) (*models.User,
 	return s.userRepository.FindByID(uint(id))
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, id int32, firstName, middleName, lastName, email, password string) (*models.User, error) {
+// UpdateUser changes a user of a role the caller's role outranks
+func (s *UserService) UpdateUser(ctx context.Context, callerRole USERROLE, id int32, firstName, middleName, lastName, email, password string) (*models.User, error) {
 	// Retrieve existing user
 	user, err := s.userRepository.FindByID(uint(id))
 	if err != nil {
 		return nil, err
 	}
+	if !callerRole.CanManage(user.UserType) {
+		return nil, ErrUserAccessDenied
+	}
 
 	// Update user details
 	user.FirstName = firstName
@@ -96,7 +109,15 @@ func (s *UserService) UpdateUser(ctx context.Context, id int32, firstName, middl
 	return user, nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, id int32) error {
+// DeleteUser deletes a user of a role the caller's role outranks
+func (s *UserService) DeleteUser(ctx context.Context, callerRole USERROLE, id int32) error {
+	user, err := s.userRepository.FindByID(uint(id))
+	if err != nil {
+		return err
+	}
+	if !callerRole.CanManage(user.UserType) {
+		return ErrUserAccessDenied
+	}
 	return s.userRepository.Delete(uint(id))
 }
 
@@ -1,34 +1,153 @@
+// pkg/jwt/jwt.go
 package jwt
 
 import (
-	"github.com/golang-jwt/jwt/v5"
-	"os"
-	"time"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
-var secretKey = os.Getenv("JWT_SECRET")
+// Config describes how tokens are signed and verified
+type Config struct {
+	Algorithm           string            // HS256, RS256 or EdDSA
+	Secret              string            // HMAC secret, used with HS256
+	SigningKeyID        string            // kid written to the header of new tokens
+	PrivateKeyPath      string            // PEM private key, used with RS256 and EdDSA
+	VerificationKeys    map[string]string // kid -> PEM public key path, for rotated-out keys
+	VerificationSecrets map[string]string // kid -> HMAC secret, for rotated-out HS256 secrets
+	Issuer              string
+	Audience            string
+	AccessTTL           time.Duration
+	RefreshTTL          time.Duration
+}
 
-func GenerateToken(userName string, duration time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"userName": userName,
-		"exp":      time.Now().Add(duration).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
+type manager struct {
+	config  Config
+	signing *Key
+	verify  map[string]*Key
 }
 
-func ValidateToken(tokenStr string) (*jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+var (
+	mu      sync.RWMutex
+	current *manager
+)
+
+// Configure loads the signing key and every verification key and secret. It
+// must be called before tokens are signed or parsed.
+func Configure(config Config) error {
+	signing, err := loadSigningKey(config)
+	if err != nil {
+		return err
+	}
+
+	verify := map[string]*Key{signing.ID: signing}
+	for kid, path := range config.VerificationKeys {
+		if _, exists := verify[kid]; exists {
+			return fmt.Errorf("jwt: duplicate key id %q", kid)
 		}
-		return []byte(secretKey), nil
-	})
+		key, err := loadVerificationKey(kid, path)
+		if err != nil {
+			return err
+		}
+		verify[kid] = key
+	}
+	for kid, secret := range config.VerificationSecrets {
+		if _, exists := verify[kid]; exists {
+			return fmt.Errorf("jwt: duplicate key id %q", kid)
+		}
+		key, err := verificationSecret(kid, secret)
+		if err != nil {
+			return err
+		}
+		verify[kid] = key
+	}
+
+	mu.Lock()
+	current = &manager{config: config, signing: signing, verify: verify}
+	mu.Unlock()
+	return nil
+}
+
+func get() (*manager, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return nil, errors.New("jwt: keys are not configured")
+	}
+	return current, nil
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return &claims, nil
-	} else {
+// Sign signs the claims with the active signing key and stamps its kid
+func Sign(claims jwt.Claims) (string, error) {
+	m, err := get()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(m.signing.Method, claims)
+	token.Header["kid"] = m.signing.ID
+	return token.SignedString(m.signing.Private)
+}
+
+// ParseWithClaims verifies the token against the key named by its kid and checks
+// the configured issuer and audience
+func ParseWithClaims(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	m, err := get()
+	if err != nil {
 		return nil, err
 	}
+
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if m.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(m.config.Issuer))
+	}
+	if m.config.Audience != "" {
+		options = append(options, jwt.WithAudience(m.config.Audience))
+	}
+
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.verify[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("invalid token signing method")
+		}
+		return key.Public, nil
+	}, options...)
+}
+
+// Issuer returns the iss claim new tokens are issued with
+func Issuer() string {
+	if m, err := get(); err == nil {
+		return m.config.Issuer
+	}
+	return ""
+}
+
+// Audience returns the aud claim new tokens are issued with
+func Audience() string {
+	if m, err := get(); err == nil {
+		return m.config.Audience
+	}
+	return ""
+}
+
+// AccessTTL returns the lifetime of access tokens
+func AccessTTL() time.Duration {
+	if m, err := get(); err == nil && m.config.AccessTTL > 0 {
+		return m.config.AccessTTL
+	}
+	return time.Hour
+}
+
+// RefreshTTL returns the lifetime of refresh tokens
+func RefreshTTL() time.Duration {
+	if m, err := get(); err == nil && m.config.RefreshTTL > 0 {
+		return m.config.RefreshTTL
+	}
+	return 24 * time.Hour
 }
@@ -0,0 +1,129 @@
+// pkg/jwt/keys.go
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a named signing or verification key
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{} // nil for verification-only keys
+	Public  interface{}
+}
+
+func loadSigningKey(config Config) (*Key, error) {
+	kid := config.SigningKeyID
+	if kid == "" {
+		kid = "default"
+	}
+
+	switch strings.ToUpper(config.Algorithm) {
+	case "", "HS256":
+		if config.Secret == "" {
+			return nil, errors.New("jwt: JWT_SECRET is required for HS256")
+		}
+		secret := []byte(config.Secret)
+		return &Key{ID: kid, Method: jwt.SigningMethodHS256, Private: secret, Public: secret}, nil
+
+	case "RS256", "EDDSA":
+		private, err := readPrivateKey(config.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keyFromPrivate(kid, private)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(key.Method.Alg(), config.Algorithm) {
+			return nil, fmt.Errorf("jwt: key in %s does not match algorithm %s", config.PrivateKeyPath, config.Algorithm)
+		}
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("jwt: unsupported algorithm %q", config.Algorithm)
+	}
+}
+
+func loadVerificationKey(kid, path string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to parse public key %s: %v", path, err)
+	}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Public: pub}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Public: pub}, nil
+	default:
+		return nil, fmt.Errorf("jwt: unsupported public key type %T in %s", public, path)
+	}
+}
+
+// verificationSecret returns a retired HS256 secret, accepted for verification only
+func verificationSecret(kid, secret string) (*Key, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("jwt: secret of key %q is empty", kid)
+	}
+	return &Key{ID: kid, Method: jwt.SigningMethodHS256, Public: []byte(secret)}, nil
+}
+
+func keyFromPrivate(kid string, private crypto.Signer) (*Key, error) {
+	switch priv := private.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Private: priv, Public: &priv.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: priv, Public: priv.Public()}, nil
+	default:
+		return nil, fmt.Errorf("jwt: unsupported private key type %T", private)
+	}
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("jwt: unsupported private key in %s", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("jwt: failed to parse private key %s", path)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	if path == "" {
+		return nil, errors.New("jwt: key path is empty")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to read key %s: %v", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: no PEM data in %s", path)
+	}
+	return block, nil
+}
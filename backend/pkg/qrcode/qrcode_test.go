@@ -0,0 +1,328 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestEncodeChoosesSmallestVersion(t *testing.T) {
+	// byte mode capacities of versions 1 and 2 (ISO/IEC 18004 table 7)
+	tests := []struct {
+		level  Level
+		v1, v2 int
+	}{
+		{L, 17, 32},
+		{M, 14, 26},
+		{Q, 11, 20},
+		{H, 7, 14},
+	}
+
+	for _, tt := range tests {
+		for _, c := range []struct{ n, version int }{{1, 1}, {tt.v1, 1}, {tt.v1 + 1, 2}, {tt.v2, 2}, {tt.v2 + 1, 3}} {
+			code, err := Encode(bytes.Repeat([]byte("a"), c.n), tt.level)
+			if err != nil {
+				t.Fatalf("level %d, %d bytes: %v", tt.level, c.n, err)
+			}
+			if code.Version != c.version {
+				t.Errorf("level %d, %d bytes: version %d, want %d", tt.level, c.n, code.Version, c.version)
+			}
+			if code.Size != 4*c.version+17 {
+				t.Errorf("level %d, %d bytes: size %d, want %d", tt.level, c.n, code.Size, 4*c.version+17)
+			}
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	code, err := Encode(make([]byte, 2953), L)
+	if err != nil {
+		t.Fatalf("2953 bytes at level L: %v", err)
+	}
+	if code.Version != 40 {
+		t.Errorf("2953 bytes at level L: version %d, want 40", code.Version)
+	}
+	if _, err := Encode(make([]byte, 2954), L); !errors.Is(err, ErrTooLong) {
+		t.Errorf("2954 bytes at level L: error %v, want %v", err, ErrTooLong)
+	}
+	if _, err := Encode(make([]byte, 1274), H); !errors.Is(err, ErrTooLong) {
+		t.Errorf("1274 bytes at level H: error %v, want %v", err, ErrTooLong)
+	}
+}
+
+func TestReedSolomonKnownVector(t *testing.T) {
+	// the data codewords of "HELLO WORLD" as a 1-M code and their ten ECC codewords
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	got := reedSolomonRemainder(data, reedSolomonDivisor(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Errorf("ECC = %v, want %v", got, want)
+	}
+}
+
+func TestFormatBitsKnownWords(t *testing.T) {
+	// format information with mask 0 (ISO/IEC 18004 table C.1)
+	tests := []struct {
+		level Level
+		word  int
+	}{
+		{L, 0x77C4},
+		{M, 0x5412},
+		{Q, 0x355F},
+		{H, 0x1689},
+	}
+
+	for _, tt := range tests {
+		code := newCode(1, tt.level)
+		code.drawFormatBits(0)
+		first, second := readFormat(code)
+		if first != tt.word || second != tt.word {
+			t.Errorf("level %d: format words %#x and %#x, want %#x", tt.level, first, second, tt.word)
+		}
+	}
+}
+
+func TestEncodeWritesFormatOfLevel(t *testing.T) {
+	for _, level := range []Level{L, M, Q, H} {
+		code, err := Encode([]byte("hello"), level)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		first, second := readFormat(code)
+		if first != second {
+			t.Fatalf("level %d: format copies differ: %#x and %#x", level, first, second)
+		}
+		if got := (first ^ 0x5412) >> 13; got != formatBits[level] {
+			t.Errorf("level %d: format level bits %b, want %b", level, got, formatBits[level])
+		}
+	}
+}
+
+func TestVersionInformation(t *testing.T) {
+	code, err := Encode(make([]byte, 150), L)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if code.Version != 7 {
+		t.Fatalf("version %d, want 7", code.Version)
+	}
+
+	var below, right int
+	for i := 0; i < 18; i++ {
+		a, b := code.Size-11+i%3, i/3
+		if code.Module(b, a) {
+			below |= 1 << i
+		}
+		if code.Module(a, b) {
+			right |= 1 << i
+		}
+	}
+	// version 7 information (ISO/IEC 18004 table D.1)
+	if below != 0x07C94 || right != 0x07C94 {
+		t.Errorf("version information %#x and %#x, want 0x07c94", below, right)
+	}
+}
+
+func TestFunctionPatterns(t *testing.T) {
+	code, err := Encode([]byte("https://flexio.fit/check-in"), M)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if want := ring != 2; code.Module(corner[0]+dx, corner[1]+dy) != want {
+					t.Fatalf("finder at %v: module (%d,%d) dark = %v, want %v", corner, dx, dy, !want, want)
+				}
+			}
+		}
+	}
+	for i := 8; i < code.Size-8; i++ {
+		if code.Module(i, 6) != (i%2 == 0) || code.Module(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern broken at %d", i)
+		}
+	}
+	if !code.Module(8, code.Size-8) {
+		t.Error("the dark module is light")
+	}
+	if code.Version >= 2 {
+		center := alignmentPositions(code.Version)[1]
+		if !code.Module(center, center) || code.Module(center+1, center) || !code.Module(center+2, center) {
+			t.Error("alignment pattern broken")
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		data  string
+		level Level
+	}{
+		{"A", L},
+		{"hello", M},
+		{"eyJhbGciOiJIUzI1NiJ9", L},
+		{"https://flexio.fit/k/9f2c", L},
+		{"flexio.fit/k/9f", Q},
+		{"0123456789", H},
+	} {
+		code, err := Encode([]byte(tt.data), tt.level)
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", tt.data, err)
+		}
+		got, err := decodeSingleBlock(code)
+		if err != nil {
+			t.Fatalf("decode %q: %v", tt.data, err)
+		}
+		if got != tt.data {
+			t.Errorf("decoded %q, want %q", got, tt.data)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode([]byte("hello"), M)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	data, err := code.PNG(3)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+
+	side := (code.Size + 2*QuietZone) * 3
+	if bounds := img.Bounds(); bounds.Dx() != side || bounds.Dy() != side {
+		t.Fatalf("png is %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), side, side)
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+	if dark(0, 0) {
+		t.Error("quiet zone is dark")
+	}
+	if !dark(QuietZone*3, QuietZone*3) {
+		t.Error("top left finder corner is light")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	code, err := Encode([]byte("hello"), M)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	side := code.Size + 2*QuietZone
+	svg := code.SVG(4)
+
+	if want := fmt.Sprintf(`width="%d" height="%d" viewBox="0 0 %d %d"`, side*4, side*4, side, side); !strings.Contains(svg, want) {
+		t.Errorf("svg lacks %s", want)
+	}
+	if want := fmt.Sprintf("M%d,%dh1v1h-1z", QuietZone, QuietZone); !strings.Contains(svg, want) {
+		t.Errorf("svg lacks the top left finder module %s", want)
+	}
+}
+
+// readFormat returns the two copies of the format information
+func readFormat(c *Code) (first, second int) {
+	for i := 0; i <= 5; i++ {
+		first |= b2i(c.Module(8, i)) << i
+	}
+	first |= b2i(c.Module(8, 7)) << 6
+	first |= b2i(c.Module(8, 8)) << 7
+	first |= b2i(c.Module(7, 8)) << 8
+	for i := 9; i < 15; i++ {
+		first |= b2i(c.Module(14-i, 8)) << i
+	}
+
+	for i := 0; i < 8; i++ {
+		second |= b2i(c.Module(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= b2i(c.Module(8, c.Size-15+i)) << i
+	}
+	return first, second
+}
+
+// decodeSingleBlock reads back the byte mode data of a code whose codewords
+// form a single block, unmasking it with the mask named in its format
+func decodeSingleBlock(c *Code) (string, error) {
+	if numBlocks[c.Level][c.Version] != 1 {
+		return "", fmt.Errorf("version %d-%d has more than one block", c.Version, c.Level)
+	}
+	format, _ := readFormat(c)
+	mask := (format ^ 0x5412) >> 10 & 7
+
+	var bits []bool
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] {
+					bits = append(bits, c.Module(x, y) != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+
+	read := func(n int) int {
+		value := 0
+		for i := 0; i < n; i++ {
+			value = value<<1 | b2i(bits[i])
+		}
+		bits = bits[n:]
+		return value
+	}
+	if mode := read(4); mode != 0x4 {
+		return "", fmt.Errorf("mode %b, want byte mode", mode)
+	}
+	n := read(8)
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(read(8))
+	}
+	return string(data), nil
+}
+
+// maskBit is the mask pattern condition of ISO/IEC 18004 table 10
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}